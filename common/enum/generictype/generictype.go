@@ -1,9 +1,11 @@
 package generictype
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/gitmann/b9schema-golang/common/enum/typecategory"
 	"reflect"
+	"strconv"
 	"time"
 )
 
@@ -59,11 +61,13 @@ var Invalid = &GenericType{
 }
 
 // Basic types.
+// - database/sql wrappers are matched by full path, see IsSQLNull.
 var Boolean = &GenericType{
 	slug: "boolean",
 	cat:  typecategory.Basic,
 	kinds: []string{
 		reflect.Bool.String(),
+		"database/sql.NullBool",
 	},
 }
 
@@ -82,6 +86,10 @@ var Integer = &GenericType{
 		reflect.Uint32.String(),
 		reflect.Uint64.String(),
 		reflect.Uintptr.String(),
+		"database/sql.NullByte",
+		"database/sql.NullInt16",
+		"database/sql.NullInt32",
+		"database/sql.NullInt64",
 	},
 }
 
@@ -91,6 +99,7 @@ var Float = &GenericType{
 	kinds: []string{
 		reflect.Float32.String(),
 		reflect.Float64.String(),
+		"database/sql.NullFloat64",
 	},
 }
 
@@ -99,6 +108,7 @@ var String = &GenericType{
 	cat:  typecategory.Basic,
 	kinds: []string{
 		reflect.String.String(),
+		"database/sql.NullString",
 	},
 }
 
@@ -131,6 +141,15 @@ var Map = &GenericType{
 	},
 }
 
+// Union is a value of one of several types, e.g. implementations of an interface.
+// - Children are the alternative types.
+// - Union has no kinds because it is never derived from a reflect.Kind.
+var Union = &GenericType{
+	slug:  "union",
+	cat:   typecategory.Compound,
+	kinds: []string{},
+}
+
 // Known types map Go standard types to b9schema types.
 // - kinds is a list of "PkgPath.Type"
 // These are a subset of protobuf well-known types:
@@ -141,9 +160,28 @@ var DateTime = &GenericType{
 	cat:  typecategory.Known,
 	kinds: []string{
 		"time.Time",
+		"database/sql.NullTime",
+	},
+}
+
+// Duration is only matched by full path because any integer type can be converted to time.Duration.
+var Duration = &GenericType{
+	slug: "duration",
+	cat:  typecategory.Known,
+	kinds: []string{
+		"time.Duration",
 	},
 }
 
+// Any is a dynamic type that allows any value, e.g. nil interfaces with Reflector.AllowAnyInterface.
+// - Any has no kinds because it is never derived from a reflect.Kind.
+// - json.RawMessage is a named []byte type, it is matched by type before byte slices are checked.
+var Any = &GenericType{
+	slug:  "any",
+	cat:   typecategory.Known,
+	kinds: []string{},
+}
+
 // Reference types.
 var Interface = &GenericType{
 	slug:        "interface",
@@ -211,8 +249,11 @@ func init() {
 	mapTypes(List)
 	mapTypes(Struct)
 	mapTypes(Map)
+	mapTypes(Union)
 
 	mapTypes(DateTime)
+	mapTypes(Duration)
+	mapTypes(Any)
 
 	mapTypes(Interface)
 	mapTypes(Pointer)
@@ -228,6 +269,19 @@ func GenericTypeOf(v reflect.Value) *GenericType {
 			return t
 		}
 
+		// JSON numbers are classified by value.
+		if v.Type() == jsonNumberType {
+			if IsJSONInteger(v.String()) {
+				return Integer
+			}
+			return Float
+		}
+
+		// JSON raw messages hold any encoded JSON value.
+		if v.Type() == jsonRawMessageType {
+			return Any
+		}
+
 		// Look for special types.
 		if v.Type().PkgPath() != "" {
 			fullPath := FullPathOf(v)
@@ -243,6 +297,11 @@ func GenericTypeOf(v reflect.Value) *GenericType {
 			}
 		}
 
+		// Byte slices are encoded as base64 strings. Byte arrays are still encoded as lists of numbers.
+		if IsByteSlice(v) {
+			return String
+		}
+
 		// Not a special type.
 		return t
 	}
@@ -263,6 +322,43 @@ func PathDefaultOfType(typeString string) string {
 	return typeString
 }
 
+// jsonNumberType is the type of numbers decoded by json.Decoder.UseNumber.
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// jsonRawMessageType is the type of encoded JSON values.
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// IsJSONRawMessage returns true if the Value is a json.RawMessage.
+func IsJSONRawMessage(v reflect.Value) bool {
+	return v.IsValid() && v.Type() == jsonRawMessageType
+}
+
+// IsJSONNumber returns true if the Value is a json.Number.
+// - Interfaces are unwrapped.
+func IsJSONNumber(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.IsValid() && v.Type() == jsonNumberType
+}
+
+// IsSQLNull returns true if the Value is a database/sql wrapper of a nullable value, e.g. sql.NullString.
+// - Wrappers are structs with the value as first field and a Valid flag.
+func IsSQLNull(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && v.Type().PkgPath() == "database/sql" && lookupByKind[FullPathOf(v)] != nil
+}
+
+// IsByteSlice returns true if the Value is a slice of bytes, e.g. []byte
+func IsByteSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// IsJSONInteger returns true if a json.Number string is an integer, i.e. it has no decimal point or exponent.
+func IsJSONInteger(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
 // FullPathOf returns the full package path for a Value.
 func FullPathOf(v reflect.Value) string {
 	return fmt.Sprintf("%s.%s", v.Type().PkgPath(), v.Type().Name())
@@ -11,6 +11,30 @@ const (
 	True      ThreeFlag = 1
 )
 
+// String returns "undefined", "false" or "true".
+// - Values other than the defined constants are returned as numbers, e.g. "2"
 func (tf ThreeFlag) String() string {
-	return fmt.Sprintf("%d", tf)
+	switch tf {
+	case Undefined:
+		return "undefined"
+	case False:
+		return "false"
+	case True:
+		return "true"
+	}
+	return fmt.Sprintf("%d", int(tf))
+}
+
+// Parse returns the ThreeFlag for a string returned by String.
+// - The numeric values "0", "-1" and "1" are also accepted.
+func Parse(s string) (ThreeFlag, error) {
+	switch s {
+	case "undefined", "0":
+		return Undefined, nil
+	case "false", "-1":
+		return False, nil
+	case "true", "1":
+		return True, nil
+	}
+	return Undefined, fmt.Errorf("invalid threeflag value %q", s)
 }
@@ -0,0 +1,58 @@
+package threeflag
+
+import (
+	"testing"
+)
+
+func TestThreeFlag_String(t *testing.T) {
+	testCases := []struct {
+		flag ThreeFlag
+		want string
+	}{
+		{flag: Undefined, want: "undefined"},
+		{flag: False, want: "false"},
+		{flag: True, want: "true"},
+		{flag: ThreeFlag(2), want: "2"},
+	}
+
+	for _, test := range testCases {
+		if got := test.flag.String(); got != test.want {
+			t.Errorf("TEST_FAIL %d: got=%q want=%q", int(test.flag), got, test.want)
+		} else {
+			t.Logf("TEST_OK %d: got=%q", int(test.flag), got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	// String output must round trip.
+	for _, flag := range []ThreeFlag{Undefined, False, True} {
+		if got, err := Parse(flag.String()); err != nil || got != flag {
+			t.Errorf("TEST_FAIL %s: got=%d err=%v", flag, int(got), err)
+		} else {
+			t.Logf("TEST_OK %s: got=%d", flag, int(got))
+		}
+	}
+
+	testCases := []struct {
+		in      string
+		want    ThreeFlag
+		wantErr bool
+	}{
+		{in: "0", want: Undefined},
+		{in: "-1", want: False},
+		{in: "1", want: True},
+		{in: "", wantErr: true},
+		{in: "TRUE", wantErr: true},
+		{in: "2", wantErr: true},
+	}
+
+	for _, test := range testCases {
+		got, err := Parse(test.in)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("TEST_FAIL %q: got=%d err=%v want=%d wantErr=%t", test.in, int(got), err, int(test.want), test.wantErr)
+		} else {
+			t.Logf("TEST_OK %q: got=%d err=%v", test.in, int(got), err)
+		}
+	}
+}
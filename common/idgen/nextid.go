@@ -1,14 +1,16 @@
 package idgen
 
-var lastID int
+import "sync/atomic"
+
+// lastID is updated atomically so that IDs are unique across goroutines.
+var lastID int64
 
 // NextID is used internally to generate the next element ID
 func NextID() int {
-	lastID++
-	return lastID
+	return int(atomic.AddInt64(&lastID, 1))
 }
 
 // Reset resets the ID counter to zero.
 func Reset() {
-	lastID = 0
+	atomic.StoreInt64(&lastID, 0)
 }
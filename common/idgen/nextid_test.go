@@ -0,0 +1,41 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNextID_Concurrent(t *testing.T) {
+	const goroutines = 50
+	const idsPerGoroutine = 1000
+
+	Reset()
+
+	ids := make(chan int, goroutines*idsPerGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < idsPerGoroutine; j++ {
+				ids <- NextID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := map[int]bool{}
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("TEST_FAIL duplicate id=%d", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != goroutines*idsPerGoroutine {
+		t.Errorf("TEST_FAIL got=%d ids want=%d", len(seen), goroutines*idsPerGoroutine)
+	} else {
+		t.Logf("TEST_OK got=%d unique ids", len(seen))
+	}
+}
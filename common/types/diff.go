@@ -0,0 +1,166 @@
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+)
+
+// ChangeKind describes how an element changed between two schemas.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeRetyped  ChangeKind = "retyped"
+	ChangeNullable ChangeKind = "nullable"
+)
+
+// Severity classifies a change for API compatibility.
+type Severity string
+
+const (
+	Breaking    Severity = "breaking"
+	NonBreaking Severity = "non-breaking"
+)
+
+// Change is a difference found by Diff.
+type Change struct {
+	// Path is the dotted path of the element starting with the TypeRef name, e.g. "BasicStruct.IntVal"
+	// - Unnamed elements (e.g. list items) use the path default of their type, e.g. "[]"
+	Path string
+
+	Kind     ChangeKind
+	Severity Severity
+
+	// Old and New are the types of the element as "<type>[:<TypeRef>][|null]", empty if the element does not exist.
+	Old string
+	New string
+}
+
+// Diff compares the TypeRef trees of two schemas and returns changes in path order.
+// - TypeRefs are matched by name, then fields are matched by json name so that renamed Go fields are not changes.
+// - Removed TypeRefs, removed required fields, added required fields and type changes are Breaking.
+// - Added TypeRefs, added optional fields and removed optional fields are NonBreaking.
+// - Fields are optional if they are nullable or have the "omitempty" option in the json dialect, see TypeNode.IsOptional.
+// - Elements that become nullable are Breaking, elements that become non-nullable are NonBreaking.
+// - Excluded elements are ignored.
+func Diff(old, new *Schema) []Change {
+	out := []Change{}
+	diffChildren(old.TypeRef, new.TypeRef, nil, &out)
+	return out
+}
+
+// diffChildren compares the children of two elements and appends changes to out.
+func diffChildren(old, new *TypeNode, path []string, out *[]Change) {
+	oldMap := diffChildMap(old)
+	newMap := diffChildMap(new)
+
+	keys := []string{}
+	for k := range oldMap {
+		keys = append(keys, k)
+	}
+	for k := range newMap {
+		if oldMap[k] == nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	isTopLevel := old.Parent == nil
+	for _, key := range keys {
+		oldChild, newChild := oldMap[key], newMap[key]
+
+		segment := key
+		if segment == "" {
+			if oldChild != nil {
+				segment = generictype.PathDefaultOfType(oldChild.Type)
+			} else {
+				segment = generictype.PathDefaultOfType(newChild.Type)
+			}
+		}
+		childPath := append(append([]string{}, path...), segment)
+
+		switch {
+		case oldChild == nil:
+			severity := NonBreaking
+			if !isTopLevel && !newChild.IsOptional() {
+				severity = Breaking
+			}
+			*out = append(*out, Change{
+				Path:     strings.Join(childPath, "."),
+				Kind:     ChangeAdded,
+				Severity: severity,
+				New:      diffType(newChild),
+			})
+		case newChild == nil:
+			severity := NonBreaking
+			if isTopLevel || !oldChild.IsOptional() {
+				severity = Breaking
+			}
+			*out = append(*out, Change{
+				Path:     strings.Join(childPath, "."),
+				Kind:     ChangeRemoved,
+				Severity: severity,
+				Old:      diffType(oldChild),
+			})
+		case oldChild.Type != newChild.Type || oldChild.TypeRef != newChild.TypeRef:
+			*out = append(*out, Change{
+				Path:     strings.Join(childPath, "."),
+				Kind:     ChangeRetyped,
+				Severity: Breaking,
+				Old:      diffType(oldChild),
+				New:      diffType(newChild),
+			})
+		default:
+			if oldChild.Nullable != newChild.Nullable {
+				severity := NonBreaking
+				if newChild.Nullable {
+					severity = Breaking
+				}
+				*out = append(*out, Change{
+					Path:     strings.Join(childPath, "."),
+					Kind:     ChangeNullable,
+					Severity: severity,
+					Old:      diffType(oldChild),
+					New:      diffType(newChild),
+				})
+			}
+			diffChildren(oldChild, newChild, childPath, out)
+		}
+	}
+}
+
+// diffChildMap returns a map of children without excluded children.
+// - TypeRefs are keyed by MapKey, fields and other children by json name if they have one.
+func diffChildMap(t *TypeNode) map[string]*TypeNode {
+	out := map[string]*TypeNode{}
+	for _, child := range t.Children {
+		if native := child.NativeDefault(); native != nil && native.Include == threeflag.False {
+			continue
+		}
+
+		key := child.MapKey()
+		if t.Parent != nil {
+			if jsonName := child.GetNativeType("json").Name; jsonName != "" {
+				key = jsonName
+			}
+		}
+		out[key] = child
+	}
+	return out
+}
+
+// diffType returns the type of an element as "<type>[:<TypeRef>][|null]".
+func diffType(t *TypeNode) string {
+	out := t.Type
+	if t.TypeRef != "" {
+		out += ":" + t.TypeRef
+	}
+	if t.Nullable {
+		out += "|null"
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+)
+
+func TestDiff(t *testing.T) {
+	oldSchema := newTestSchema("/basic", "BasicStruct", "IntVal", "StringVal", "Name", "Email", "Tags", "Note")
+	oldRef := oldSchema.TypeRef.ChildByName("BasicStruct", nil)
+	oldRef.ChildByName("Name", nil).SetName("json", "name")
+	oldRef.ChildByName("Email", nil).SetName("json", "email")
+	oldRef.ChildByName("Note", nil).Nullable = true
+
+	// Modify a copy:
+	// - remove IntVal and retype StringVal
+	// - rename the Go field of "name" and the json name of Email
+	// - make Tags nullable and Note non-nullable
+	// - add an optional field and a type ref
+	newSchema := &Schema{
+		Root:    oldSchema.Root.Copy(),
+		TypeRef: oldSchema.TypeRef.Copy(),
+	}
+	ref := newSchema.TypeRef.ChildByName("BasicStruct", nil)
+	ref.RemoveChild(ref.ChildByName("IntVal", nil))
+	ref.ChildByName("StringVal", nil).Type = "integer"
+	ref.ChildByName("Name", nil).Name = "FullName"
+	ref.ChildByName("Email", nil).SetName("json", "mail")
+	ref.ChildByName("Tags", nil).Nullable = true
+	ref.ChildByName("Note", nil).Nullable = false
+	extra := ref.NewChild("Extra")
+	extra.Type = "string"
+	extra.Nullable = true
+	newSchema.TypeRef.NewChild("Other").Type = "struct"
+
+	gotStrings := []string{}
+	for _, c := range Diff(oldSchema, newSchema) {
+		gotStrings = append(gotStrings, fmt.Sprintf("%s|%s|%s|%s|%s", c.Path, c.Kind, c.Severity, c.Old, c.New))
+	}
+	util.CompareStrings(t, "diff", gotStrings, []string{
+		`BasicStruct.Extra|added|non-breaking||string|null`,
+		`BasicStruct.IntVal|removed|breaking|string|`,
+		`BasicStruct.Note|nullable|non-breaking|string|null|string`,
+		`BasicStruct.StringVal|retyped|breaking|string|integer`,
+		`BasicStruct.Tags|nullable|breaking|string|string|null`,
+		`BasicStruct.email|removed|breaking|string|`,
+		`BasicStruct.mail|added|breaking||string`,
+		`Other|added|non-breaking||struct`,
+	})
+
+	if got := Diff(oldSchema, oldSchema); len(got) != 0 {
+		t.Errorf("TEST_FAIL diff-same: got %d changes, want 0", len(got))
+	} else {
+		t.Logf("TEST_OK diff-same")
+	}
+}
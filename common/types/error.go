@@ -12,4 +12,16 @@ const (
 	MapKeyTypeErr        = "map key type must be string"
 	SliceMultiTypeErr    = "slice contains multiple kinds"
 	DuplicateMapKeyErr   = "duplicate map key"
+	MaxDepthErr          = "maximum depth exceeded"
+	ReflectionPanicErr   = "reflection panic"
+	TypeRefCollisionErr  = "type ref name used by different types"
+
+	// Errors for invalid b9schema tag options, the native error has details.
+	InlineFieldErr = "invalid inline field"
+	KeyPatternErr  = "invalid keyPattern option"
+	PatternErr     = "invalid pattern option"
+
+	// CustomMarshalerErr is set on elements with a custom json.Marshaler.
+	// - The schema is still derived but it may not match the serialized JSON.
+	CustomMarshalerErr = "custom json marshaler, schema may not match JSON"
 )
@@ -1,11 +1,29 @@
 package types
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
 const (
 	ROOT_NAME    = "Root"
 	TYPEREF_NAME = "TypeRef"
+
+	// TAG_DIALECT is the struct tag name used for b9schema options, e.g. `b9schema:"format=email"`
+	TAG_DIALECT = "b9schema"
+
+	// INLINE_OPTION is the b9schema tag option for a catch-all map field, e.g. `b9schema:"inline"`
+	// - The map values are extra keys of the parent struct instead of a named property.
+	INLINE_OPTION = "inline"
+
+	// OPERATION_SEPARATOR separates the path and HTTP method in a MetaKey, e.g. "/users post"
+	OPERATION_SEPARATOR = " "
 )
 
 // Schema is the result of parsing types.
+// - Schemas marshal to stable JSON, see TypeNode.MarshalJSON.
 type Schema struct {
 	// Root is the node ID of the root of types in the order found.
 	Root *TypeNode
@@ -24,6 +42,43 @@ func NewSchema(nativeDialect string) *Schema {
 	return schema
 }
 
+// NewSchemaFromJSON loads a schema that was marshaled to JSON, see TypeNode.MarshalJSON.
+// - Parent pointers are not marshaled so they are linked again after unmarshaling.
+// - Returns an error if the JSON is invalid or the schema does not have Root and TypeRef elements.
+func NewSchemaFromJSON(b []byte) (*Schema, error) {
+	schema := &Schema{}
+	if err := json.Unmarshal(b, schema); err != nil {
+		return nil, err
+	}
+
+	if schema.Root == nil || schema.TypeRef == nil {
+		return nil, errors.New("schema must have Root and TypeRef elements")
+	}
+
+	for _, t := range []*TypeNode{schema.Root, schema.TypeRef} {
+		t.Parent = nil
+		linkChildren(t)
+	}
+
+	return schema, nil
+}
+
+// linkChildren sets the Parent of all descendants of an element.
+// - Empty Children and Native are initialized like NewTypeNode so that loaded elements can be changed.
+func linkChildren(t *TypeNode) {
+	if t.Children == nil {
+		t.Children = []*TypeNode{}
+	}
+	if t.Native == nil {
+		t.Native = map[string]*NativeType{}
+	}
+
+	for _, childNode := range t.Children {
+		childNode.Parent = t
+		linkChildren(childNode)
+	}
+}
+
 // CopyWithoutNative removes all native dialects for the minimal schema.
 func (schema *Schema) CopyWithoutNative() *Schema {
 	return &Schema{
@@ -31,3 +86,72 @@ func (schema *Schema) CopyWithoutNative() *Schema {
 		TypeRef: schema.TypeRef.CopyWithoutNative(),
 	}
 }
+
+// Walk calls fn for all elements in the Root tree and then the TypeRef tree, see TypeNode.Walk.
+func (schema *Schema) Walk(fn func(node *TypeNode) error) error {
+	for _, t := range []*TypeNode{schema.Root, schema.TypeRef} {
+		if err := t.walk(fn); err == ErrStopWalk {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge adds copies of the root elements and type refs of another schema.
+// - Type refs are combined by name.
+// - Returns an error and leaves the schema unchanged if two different type refs have the same name.
+func (schema *Schema) Merge(other *Schema) error {
+	if other == nil {
+		return nil
+	}
+
+	// Check for conflicts before changing anything.
+	typeRefMap := schema.TypeRef.ChildMap()
+	for _, otherRef := range other.TypeRef.Children {
+		if ref := typeRefMap[otherRef.MapKey()]; ref != nil && !ref.Equals(otherRef, false) {
+			return fmt.Errorf("type ref %q has conflicting definitions", otherRef.MapKey())
+		}
+	}
+
+	for _, otherRoot := range other.Root.Children {
+		schema.Root.AddChild(otherRoot.Copy())
+	}
+
+	for _, otherRef := range other.TypeRef.Children {
+		if typeRefMap[otherRef.MapKey()] == nil {
+			schema.TypeRef.AddChild(otherRef.Copy())
+		}
+	}
+
+	return nil
+}
+
+// OperationKey builds a MetaKey for an API operation from a path and HTTP method.
+// - Path comes first so that operations on the same path sort together.
+// - If method is empty, the path is returned unchanged.
+func OperationKey(path, method string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		return path
+	}
+	return path + OPERATION_SEPARATOR + method
+}
+
+// httpMethods are the methods recognized by SplitOperationKey.
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// SplitOperationKey returns the path and HTTP method from a MetaKey built by OperationKey.
+// - method is empty if the MetaKey does not end with a known HTTP method.
+func SplitOperationKey(metaKey string) (path, method string) {
+	if i := strings.LastIndex(metaKey, OPERATION_SEPARATOR); i >= 0 {
+		if method = metaKey[i+len(OPERATION_SEPARATOR):]; httpMethods[method] {
+			return metaKey[:i], method
+		}
+	}
+	return metaKey, ""
+}
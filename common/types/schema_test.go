@@ -0,0 +1,100 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestSchema builds a schema with one root element that references a struct type ref.
+// - fields are the names of string fields in the type ref.
+func newTestSchema(metaKey, typeRef string, fields ...string) *Schema {
+	schema := NewSchema("golang")
+
+	root := schema.Root.NewChild("")
+	root.MetaKey = metaKey
+	root.Type = "struct"
+	root.TypeRef = typeRef
+
+	ref := schema.TypeRef.NewChild(typeRef)
+	ref.Type = "struct"
+	for _, field := range fields {
+		ref.NewChild(field).Type = "string"
+	}
+
+	return schema
+}
+
+func TestSchema_Merge(t *testing.T) {
+	testCases := []struct {
+		name      string
+		schema    *Schema
+		other     *Schema
+		wantRoots []string
+		wantRefs  []string
+		wantErr   bool
+	}{
+		{
+			name:      "nil",
+			schema:    newTestSchema("/a", "A", "Name"),
+			wantRoots: []string{"/a"},
+			wantRefs:  []string{"A"},
+		},
+		{
+			name:      "distinct",
+			schema:    newTestSchema("/a", "A", "Name"),
+			other:     newTestSchema("/b", "B", "Value"),
+			wantRoots: []string{"/a", "/b"},
+			wantRefs:  []string{"A", "B"},
+		},
+		{
+			name:      "same-definition",
+			schema:    newTestSchema("/a", "A", "Name"),
+			other:     newTestSchema("/a2", "A", "Name"),
+			wantRoots: []string{"/a", "/a2"},
+			wantRefs:  []string{"A"},
+		},
+		{
+			name:      "conflict",
+			schema:    newTestSchema("/a", "A", "Name"),
+			other:     newTestSchema("/a2", "A", "Other"),
+			wantRoots: []string{"/a"},
+			wantRefs:  []string{"A"},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.schema.Merge(test.other)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("TEST_FAIL %s: err=%v wantErr=%t", test.name, err, test.wantErr)
+			}
+
+			gotRoots := []string{}
+			for _, child := range test.schema.Root.Children {
+				gotRoots = append(gotRoots, child.MetaKey)
+				if child.Parent != test.schema.Root {
+					t.Errorf("TEST_FAIL %s: root %q has wrong parent", test.name, child.MetaKey)
+				}
+			}
+			gotRefs := test.schema.TypeRef.ChildKeys(nil)
+
+			if strings.Join(gotRoots, ",") != strings.Join(test.wantRoots, ",") ||
+				strings.Join(gotRefs, ",") != strings.Join(test.wantRefs, ",") {
+				t.Errorf("TEST_FAIL %s: got=%v,%v want=%v,%v", test.name, gotRoots, gotRefs, test.wantRoots, test.wantRefs)
+			} else {
+				t.Logf("TEST_OK %s: got=%v,%v", test.name, gotRoots, gotRefs)
+			}
+		})
+	}
+
+	// Merged elements are copies.
+	schema := newTestSchema("/a", "A", "Name")
+	other := newTestSchema("/b", "B", "Value")
+	if err := schema.Merge(other); err != nil {
+		t.Fatalf("TEST_FAIL copy: err=%s", err)
+	}
+	if schema.Root.Children[1] == other.Root.Children[0] || schema.TypeRef.Children[1] == other.TypeRef.Children[0] {
+		t.Errorf("TEST_FAIL copy: merged elements are not copies")
+	}
+}
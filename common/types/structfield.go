@@ -12,6 +12,7 @@ import (
 // - tag="-" --> ignored field, Ignore=true
 // - tag="someString" --> alias only, Alias = "someString"
 // - tag="someString,options" --> alias with options, Alias="someString", Options=remainder after the first comma
+// - tag="key=value,options" in the TAG_DIALECT --> options only, the TAG_DIALECT has no alias
 // - If tag = "-", Ignore is true -->
 type StructFieldTag struct {
 	Ignore  bool
@@ -26,7 +27,17 @@ type StructFieldTag struct {
 // - if tag string is "-", field is ignored
 // - either <alias> or <options> can be omitted
 // - if <options> is empty, the comma may be omitted
+// - option values may be lists delimited by "|", e.g. enum=red|green|blue
+// - option values may be single-quoted to include commas, e.g. pattern='^[a-z]{1,3}$'
+//   - a single quote in a quoted value is written twice
 func NewStructFieldTag(tag string) *StructFieldTag {
+	return parseStructFieldTag(tag, false)
+}
+
+// parseStructFieldTag parses a tag string like NewStructFieldTag.
+// - if optionsOnly is true, the tag has no alias and all tokens are options.
+// - ParseTags sets optionsOnly for the TAG_DIALECT, e.g. `b9schema:"deprecated,format=email"`
+func parseStructFieldTag(tag string, optionsOnly bool) *StructFieldTag {
 	t := &StructFieldTag{
 		Options: NewNativeOption(),
 	}
@@ -44,6 +55,9 @@ func NewStructFieldTag(tag string) *StructFieldTag {
 	if tag == "-" {
 		// Ignored field.
 		t.Ignore = true
+	} else if optionsOnly {
+		// No alias, the whole tag is used so that quoted values with commas are kept as-is.
+		rawOptions = tag
 	} else if strings.Contains(tag, ",") {
 		//	GetName with options.
 		tokens := strings.SplitN(tag, ",", 2)
@@ -57,7 +71,7 @@ func NewStructFieldTag(tag string) *StructFieldTag {
 
 	if rawOptions != "" {
 		// The raw option string is a comma-delimited list of option values.
-		for _, opt := range strings.Split(rawOptions, ",") {
+		for _, opt := range splitOptions(rawOptions) {
 			opt = strings.TrimSpace(opt)
 			if opt != "" {
 				tokens := strings.SplitN(opt, "=", 2)
@@ -65,7 +79,7 @@ func NewStructFieldTag(tag string) *StructFieldTag {
 					var key, val string
 					key = strings.TrimSpace(tokens[0])
 					if len(tokens) > 1 {
-						val = strings.TrimSpace(tokens[1])
+						val = unquoteOption(strings.TrimSpace(tokens[1]))
 					}
 
 					if val != "" {
@@ -81,6 +95,36 @@ func NewStructFieldTag(tag string) *StructFieldTag {
 	return t
 }
 
+// splitOptions splits a comma-delimited option string.
+// - Commas in single-quoted values are not delimiters.
+func splitOptions(s string) []string {
+	out := []string{}
+	start := 0
+	quoted := false
+	for i, c := range s {
+		switch c {
+		case '\'':
+			// Doubled quotes toggle twice so they do not change the state.
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// unquoteOption removes single quotes around an option value and undoubles quotes in the value.
+// - Values without quotes are unchanged.
+func unquoteOption(val string) string {
+	if len(val) < 2 || val[0] != '\'' || val[len(val)-1] != '\'' {
+		return val
+	}
+	return strings.ReplaceAll(val[1:len(val)-1], "''", "'")
+}
+
 // Equals returns true if two StructFieldTag structs have the same values.
 func (s *StructFieldTag) Equals(other *StructFieldTag) bool {
 	if s == nil && other == nil {
@@ -148,7 +192,7 @@ func ParseTags(tag reflect.StructTag) Tags {
 		qvalue := string(tag[:i+1])
 		tag = tag[i+1:]
 
-		tags[name] = NewStructFieldTag(qvalue)
+		tags[name] = parseStructFieldTag(qvalue, name == TAG_DIALECT)
 	}
 	return tags
 }
@@ -8,6 +8,7 @@ import (
 func TestNewStructFieldTag(t *testing.T) {
 	testCases := []struct {
 		name    string
+		dialect string
 		tag     string
 		wantTag *StructFieldTag
 	}{
@@ -47,18 +48,80 @@ func TestNewStructFieldTag(t *testing.T) {
 		{
 			name:    "alias, options",
 			tag:     `"abc,def,ghi"`,
-			wantTag: &StructFieldTag{Alias: "abc", Options: map[string]string{"def": "", "ghi": ""}},
+			wantTag: &StructFieldTag{Alias: "abc", Options: NativeOptionFromMap(map[string]string{"def": "", "ghi": ""})},
 		},
 		{
 			name:    "comma, options",
 			tag:     `",def,ghi"`,
-			wantTag: &StructFieldTag{Options: map[string]string{"def": "", "ghi": ""}},
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"def": "", "ghi": ""})},
+		},
+		{
+			name:    "key-value only",
+			dialect: TAG_DIALECT,
+			tag:     `"format=email"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"format": "email"})},
+		},
+		{
+			name:    "key-value list",
+			dialect: TAG_DIALECT,
+			tag:     `"enum=red|green|blue"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"enum": "red|green|blue"})},
+		},
+		{
+			name:    "alias, key-value list",
+			tag:     `"color,enum=red|green|blue,def"`,
+			wantTag: &StructFieldTag{Alias: "color", Options: NativeOptionFromMap(map[string]string{"enum": "red|green|blue", "def": ""})},
+		},
+		{
+			name:    "key-value, options",
+			dialect: TAG_DIALECT,
+			tag:     `"format=email,def"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"format": "email", "def": ""})},
+		},
+		{
+			name:    "key-value constraints",
+			dialect: TAG_DIALECT,
+			tag:     `"minLength=1,maxLength=255"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"minLength": "1", "maxLength": "255"})},
+		},
+		{
+			name:    "quoted pattern",
+			dialect: TAG_DIALECT,
+			tag:     `"pattern='^[a-z]+$'"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"pattern": "^[a-z]+$"})},
+		},
+		{
+			name:    "quoted pattern with commas",
+			tag:     `"code,pattern='^[A-Z]{2,3}(,[A-Z]{2,3})*$',minLength=2"`,
+			wantTag: &StructFieldTag{Alias: "code", Options: NativeOptionFromMap(map[string]string{"pattern": "^[A-Z]{2,3}(,[A-Z]{2,3})*$", "minLength": "2"})},
+		},
+		{
+			name:    "quoted quote",
+			dialect: TAG_DIALECT,
+			tag:     `"desc='It''s here, really'"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"desc": "It's here, really"})},
+		},
+		{
+			name:    "bare option",
+			dialect: TAG_DIALECT,
+			tag:     `"deprecated,format=email"`,
+			wantTag: &StructFieldTag{Options: NativeOptionFromMap(map[string]string{"deprecated": "", "format": "email"})},
+		},
+		{
+			name:    "key-value alias",
+			dialect: "json",
+			tag:     `"a=b,omitempty"`,
+			wantTag: &StructFieldTag{Alias: "a=b", Options: NativeOptionFromMap(map[string]string{"omitempty": ""})},
 		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
 			gotTag := NewStructFieldTag(test.tag)
+			if test.dialect == TAG_DIALECT {
+				// ParseTags parses the TAG_DIALECT as options only.
+				gotTag = parseStructFieldTag(test.tag, true)
+			}
 
 			if !gotTag.Equals(test.wantTag) {
 				t.Errorf("TEST_FAIL %s: got=%v want=%v", test.name, gotTag, test.wantTag)
@@ -85,7 +148,7 @@ func TestParseTags(t *testing.T) {
 			wantTags: Tags{
 				"json": &StructFieldTag{
 					Alias:   "abc",
-					Options: map[string]string{"def": ""},
+					Options: NativeOptionFromMap(map[string]string{"def": ""}),
 				},
 			},
 		},
@@ -95,11 +158,11 @@ func TestParseTags(t *testing.T) {
 			wantTags: Tags{
 				"json": &StructFieldTag{
 					Alias:   "abc",
-					Options: map[string]string{"def": ""},
+					Options: NativeOptionFromMap(map[string]string{"def": ""}),
 				},
 				"bigquery": &StructFieldTag{
 					Alias:   "-",
-					Options: map[string]string{"xyz": "", "123": ""},
+					Options: NativeOptionFromMap(map[string]string{"xyz": "", "123": ""}),
 				},
 			},
 		},
@@ -120,7 +183,7 @@ func TestParseTags(t *testing.T) {
 			for tagName := range allKeys {
 				got := gotTags[tagName]
 				want := test.wantTags[tagName]
-				if !reflect.DeepEqual(got, want) {
+				if !got.Equals(want) {
 					t.Errorf("TEST_FAIL %s: %q got=%value want=%value", test.name, tagName, got, want)
 				} else {
 					t.Logf("TEST_OK %s: %q got=%value", test.name, tagName, got)
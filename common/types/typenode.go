@@ -1,13 +1,17 @@
 package types
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
 	"sort"
+	"strings"
 	"unicode"
 
 	"github.com/gitmann/b9schema-golang/common/enum/generictype"
 	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/enum/typecategory"
 )
 
 // TypeNode holds type information about an element.
@@ -40,6 +44,10 @@ type TypeNode struct {
 	// This can be used to attach additional metadata during rendering.
 	MetaKey string `json:",omitempty"`
 
+	// Order is the declaration order of a named child in its parent, starting at 1.
+	// - 0 means that the order is not set, e.g. list items.
+	Order int `json:",omitempty"`
+
 	// Pointers to Parent and Child ID strings.
 	Parent   *TypeNode   `json:"-"`
 	Children []*TypeNode `json:",omitempty"`
@@ -191,10 +199,12 @@ func (t *TypeNode) Copy() *TypeNode {
 	// Copy simple fields.
 	n.Parent = nil
 	n.Description = t.Description
+	n.Nullable = t.Nullable
 	n.Type = t.Type
 	n.TypeRef = t.TypeRef
 	n.Error = t.Error
 	n.MetaKey = t.MetaKey
+	n.Order = t.Order
 
 	// Copy Children with new element as parent.
 	for _, childNode := range t.Children {
@@ -210,6 +220,82 @@ func (t *TypeNode) Copy() *TypeNode {
 	return n
 }
 
+// MarshalJSON returns the element as JSON with stable output for version control.
+// - Children are sorted by MapKey, unnamed children (e.g. list items) keep their order.
+// - Native dialects and options are maps so they are sorted by encoding/json.
+func (t *TypeNode) MarshalJSON() ([]byte, error) {
+	// typeNodeJSON has no methods so that Marshal does not call MarshalJSON recursively.
+	type typeNodeJSON TypeNode
+
+	n := typeNodeJSON(*t)
+	n.Children = t.sortedChildren()
+
+	return json.Marshal(n)
+}
+
+// Equals returns true if two elements and their descendants describe the same type.
+// - Names, MetaKey, Type, TypeRef, Nullable and Error are compared.
+// - Children are compared in MapKey order, unnamed children (e.g. list items) in their order.
+// - Native types are compared only if includeNative is true.
+// - Two nil elements are equal, a nil element is not equal to a non-nil element.
+func (t *TypeNode) Equals(other *TypeNode, includeNative bool) bool {
+	if t == nil || other == nil {
+		return t == nil && other == nil
+	}
+
+	if t.Name != other.Name || t.MetaKey != other.MetaKey || t.Type != other.Type || t.TypeRef != other.TypeRef ||
+		t.Nullable != other.Nullable || t.Error != other.Error || len(t.Children) != len(other.Children) {
+		return false
+	}
+
+	if includeNative && !sameNative(t.Native, other.Native) {
+		return false
+	}
+
+	tChildren, otherChildren := t.sortedChildren(), other.sortedChildren()
+	for i := range tChildren {
+		if !tChildren[i].Equals(otherChildren[i], includeNative) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedChildren returns a copy of Children sorted by MapKey, see MarshalJSON.
+func (t *TypeNode) sortedChildren() []*TypeNode {
+	out := append([]*TypeNode{}, t.Children...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].MapKey() < out[j].MapKey()
+	})
+	return out
+}
+
+// sameNative returns true if two maps of native types have the same dialects and values.
+// - nil and empty maps are equal.
+func sameNative(a, b map[string]*NativeType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for dialect, aNative := range a {
+		bNative, ok := b[dialect]
+		if !ok || (aNative == nil) != (bNative == nil) {
+			return false
+		}
+		if aNative == nil {
+			continue
+		}
+
+		if aNative.Dialect != bNative.Dialect || aNative.Name != bNative.Name || aNative.Type != bNative.Type ||
+			aNative.TypeRef != bNative.TypeRef || aNative.Format != bNative.Format ||
+			aNative.Include != bNative.Include || aNative.Error != bNative.Error ||
+			!aNative.Options.Equals(bNative.Options) {
+			return false
+		}
+	}
+	return true
+}
+
 // CopyWithoutNative makes a copy of a TypeNode and its Children without Native types.
 // - The copied element has no Parent.
 func (t *TypeNode) CopyWithoutNative() *TypeNode {
@@ -218,10 +304,12 @@ func (t *TypeNode) CopyWithoutNative() *TypeNode {
 	// Copy simple fields.
 	n.Parent = nil
 	n.Description = t.Description
+	n.Nullable = t.Nullable
 	n.Type = t.Type
 	n.TypeRef = t.TypeRef
 	n.Error = t.Error
 	n.MetaKey = t.MetaKey
+	n.Order = t.Order
 
 	// Copy Children with new element as parent.
 	for _, childNode := range t.Children {
@@ -243,22 +331,26 @@ func (t *TypeNode) GetNativeType(dialect string) *NativeType {
 	newType.Name = t.Name
 	newType.Type = t.Type
 	newType.TypeRef = t.NativeDefault().TypeRef
-	newType.Include = threeflag.Undefined
+	newType.Format = t.NativeDefault().Format
+	newType.Include = t.NativeDefault().Include
 
 	// Check if a native type exists for the dialect.
 	if dialect != "" {
 		oldType := t.Native[dialect]
 		if oldType != nil {
 			// Replace with values from oldType if set.
-			if newType.Name != "" && oldType.Name != "" {
+			if oldType.Name != "" {
 				newType.Name = oldType.Name
 			}
-			if newType.Type != "" && oldType.Type != "" {
+			if oldType.Type != "" {
 				newType.Type = oldType.Type
 			}
-			if newType.TypeRef != "" && oldType.TypeRef != "" {
+			if oldType.TypeRef != "" {
 				newType.TypeRef = oldType.TypeRef
 			}
+			if oldType.Format != "" {
+				newType.Format = oldType.Format
+			}
 			if oldType.Include != threeflag.Undefined {
 				newType.Include = oldType.Include
 			}
@@ -268,6 +360,72 @@ func (t *TypeNode) GetNativeType(dialect string) *NativeType {
 	return newType
 }
 
+// ErrStopWalk may be returned by a Walk function to stop walking without an error.
+var ErrStopWalk = errors.New("stop walk")
+
+// ResolveNativeType returns a native type like GetNativeType from several dialects.
+// - Dialects are in order of precedence, e.g. "yaml" then "json"; each value is taken from the first dialect that sets it.
+// - The returned native type has the dialect of the first native type found, or the last dialect if none is found.
+func (t *TypeNode) ResolveNativeType(dialects ...string) *NativeType {
+	if len(dialects) == 0 {
+		return t.GetNativeType("")
+	}
+
+	// Apply dialects from lowest to highest precedence.
+	newType := t.GetNativeType(dialects[len(dialects)-1])
+	for i := len(dialects) - 2; i >= 0; i-- {
+		oldType := t.Native[dialects[i]]
+		if dialects[i] == "" || oldType == nil {
+			continue
+		}
+
+		newType.Dialect = oldType.Dialect
+		if oldType.Name != "" {
+			newType.Name = oldType.Name
+		}
+		if oldType.Type != "" {
+			newType.Type = oldType.Type
+		}
+		if oldType.TypeRef != "" {
+			newType.TypeRef = oldType.TypeRef
+		}
+		if oldType.Format != "" {
+			newType.Format = oldType.Format
+		}
+		if oldType.Include != threeflag.Undefined {
+			newType.Include = oldType.Include
+		}
+	}
+
+	return newType
+}
+
+// Walk calls fn for the element and its descendants depth-first.
+// - Children are visited in ChildKeys order.
+// - If fn returns ErrStopWalk, walking stops and Walk returns nil.
+// - If fn returns any other error, walking stops and Walk returns the error.
+func (t *TypeNode) Walk(fn func(node *TypeNode) error) error {
+	if err := t.walk(fn); err != nil && err != ErrStopWalk {
+		return err
+	}
+	return nil
+}
+
+// walk is the recursive part of Walk that passes ErrStopWalk up to the caller.
+func (t *TypeNode) walk(fn func(node *TypeNode) error) error {
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	childMap := t.ChildMap()
+	for _, key := range t.ChildKeys(childMap) {
+		if err := childMap[key].walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RemoveParent removes the Parent.
 func (t *TypeNode) RemoveParent() {
 	if t.Parent != nil {
@@ -318,12 +476,75 @@ func (t *TypeNode) NativeDefault() *NativeType {
 	return t.Native[t.NativeDialect]
 }
 
+// SetError sets the generic error and the error of the native default so that they do not diverge.
+// - Callers may replace the native error with a detailed message after SetError.
+func (t *TypeNode) SetError(err string) {
+	t.Error = err
+	if native := t.NativeDefault(); native != nil {
+		native.Error = err
+	}
+}
+
+// ClearErrors clears the generic error and the error of the native default.
+// - If recursive is true, errors of all descendants are cleared too.
+func (t *TypeNode) ClearErrors(recursive bool) {
+	t.SetError("")
+
+	if recursive {
+		for _, childNode := range t.Children {
+			childNode.ClearErrors(true)
+		}
+	}
+}
+
+// Category returns the type category of the element's generic type.
+// - Types that are not generic types (e.g. "invalid:chan") are in the Invalid category.
+func (t *TypeNode) Category() typecategory.TypeCategory {
+	if gt := generictype.FromType(t.Type); gt != nil {
+		return gt.Category()
+	}
+	return typecategory.Invalid
+}
+
 // IsBasicType returns true if the element is a basic type.
 func (t *TypeNode) IsBasicType() bool {
-	switch t.Type {
-	case "string", "integer", "float", "boolean":
+	return t.Category() == typecategory.Basic
+}
+
+// IsInvalid returns true if the element has an invalid type, e.g. an unsupported kind.
+func (t *TypeNode) IsInvalid() bool {
+	return t.Category() == typecategory.Invalid
+}
+
+// IsCompound returns true if the element is a list, struct, map or union.
+func (t *TypeNode) IsCompound() bool {
+	return t.Category() == typecategory.Compound
+}
+
+// IsReference returns true if the element is an interface or pointer.
+func (t *TypeNode) IsReference() bool {
+	return t.Category() == typecategory.Reference
+}
+
+// IsKnown returns true if the element is a known type, e.g. datetime or duration.
+func (t *TypeNode) IsKnown() bool {
+	return t.Category() == typecategory.Known
+}
+
+// IsOptional returns true if an element may be omitted or null in serialized output.
+// - Nullable elements (e.g. pointers, interfaces) are optional.
+// - Elements with the "omitempty" option in the json dialect are optional.
+func (t *TypeNode) IsOptional() bool {
+	if t.Nullable {
 		return true
 	}
+
+	if jsonNative := t.Native["json"]; jsonNative != nil {
+		if _, ok := jsonNative.Options.Lookup("omitempty"); ok {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -347,86 +568,197 @@ func (t *TypeNode) Ancestors() []*TypeNode {
 	return append(t.Parent.Ancestors(), t)
 }
 
+// PathString builds a dotted path from the top of the tree to the element, e.g. "Root.{}:Name.Field:string"
+// - Segments use the native names of the given dialect, see PathSegment.
+func (t *TypeNode) PathString(dialect string) string {
+	segments := []string{}
+	for _, n := range t.Ancestors() {
+		segments = append(segments, n.PathSegment(dialect, true))
+	}
+	return strings.Join(segments, ".")
+}
+
+// PathSegment builds the path segment of an element: [<Name>:]<Type>[:<TypeRef>]
+// - Name is the native name of the given dialect and is omitted if empty.
+// - Type is the path default of the generic type, e.g. "{}", or the type string of invalid types.
+// - TypeRef is omitted if withTypeRef is false.
+// - If Error is set, the segment is wrapped with "!"
+// - If the segment contains ".", it is quoted.
+// - Top-level elements (e.g. Root) are only their name.
+func (t *TypeNode) PathSegment(dialect string, withTypeRef bool) string {
+	return t.PathSegmentNative(t.GetNativeType(dialect), withTypeRef)
+}
+
+// PathSegmentNative returns a path segment like PathSegment with the name and TypeRef of a resolved native type.
+// - e.g. a native type from ResolveNativeType for renderers with several dialects.
+func (t *TypeNode) PathSegmentNative(native *NativeType, withTypeRef bool) string {
+	if t.Parent == nil {
+		return t.Name
+	}
+
+	namePart := native.Name
+	if namePart != "" {
+		namePart += ":"
+	}
+
+	typePart := generictype.PathDefaultOfType(t.Type)
+	if t.IsInvalid() {
+		typePart = t.Type
+	}
+
+	refPart := ""
+	if withTypeRef && native.TypeRef != "" {
+		refPart = ":" + native.TypeRef
+	}
+
+	segment := namePart + typePart + refPart
+
+	if t.Error != "" {
+		segment = fmt.Sprintf("!%s!", segment)
+	}
+
+	if strings.Contains(segment, ".") {
+		segment = fmt.Sprintf("%q", segment)
+	}
+
+	return segment
+}
+
 // NativeOption stores options as key-value pairs but returns a list of strings.
 // - Value-only entries are unique by value.
 // - Values with keys are unique by key.
-type NativeOption map[string]string
+// - Insertion order is kept for AsOrderedList, replacing a value keeps its position.
+// - The zero value is an empty NativeOption ready to use.
+type NativeOption struct {
+	values map[string]string
+
+	// keys holds the keys of values in insertion order.
+	keys []string
+}
+
+// LIST_SEPARATOR delimits values of list options, e.g. enum=red|green|blue
+const LIST_SEPARATOR = "|"
 
 func NewNativeOption() NativeOption {
-	return map[string]string{}
+	return NativeOption{values: map[string]string{}}
 }
 
-// Equals returns true if both NativeOption struct have the same values.
-func (n NativeOption) Equals(other NativeOption) bool {
-	if n == nil && other == nil {
-		// Both are nil so equal.
-		return true
+// NativeOptionFromMap returns a NativeOption with the entries of a map.
+// - Maps are not ordered so entries are inserted in key order.
+func NativeOptionFromMap(m map[string]string) NativeOption {
+	n := NewNativeOption()
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		n.set(k, m[k])
+	}
+	return n
+}
 
-	// Treat nil option map as zero-length.
-	var thisLen, otherLen int
-	if n != nil {
-		thisLen = len(n)
+// set sets a value and records the key if it is new.
+func (n *NativeOption) set(key, val string) {
+	if n.values == nil {
+		n.values = map[string]string{}
 	}
-	if other != nil {
-		otherLen = len(other)
+	if _, ok := n.values[key]; !ok {
+		n.keys = append(n.keys, key)
 	}
+	n.values[key] = val
+}
+
+// Get returns the value of a key, value-only entries and missing keys return an empty string.
+func (n *NativeOption) Get(key string) string {
+	return n.values[key]
+}
+
+// Lookup returns the value of a key and true if the key is set.
+func (n *NativeOption) Lookup(key string) (string, bool) {
+	val, ok := n.values[key]
+	return val, ok
+}
+
+// Len returns the number of entries.
+func (n *NativeOption) Len() int {
+	return len(n.values)
+}
+
+// Keys returns the keys of all entries in insertion order.
+func (n *NativeOption) Keys() []string {
+	return append([]string{}, n.keys...)
+}
 
-	if thisLen != otherLen {
+// Equals returns true if both NativeOption struct have the same values.
+// - Insertion order is not compared.
+func (n *NativeOption) Equals(other NativeOption) bool {
+	if n.Len() != other.Len() {
 		return false
-	} else if thisLen == 0 {
-		return true
 	}
 
-	return reflect.DeepEqual(n, other)
+	for k, v := range n.values {
+		if otherVal, ok := other.values[k]; !ok || otherVal != v {
+			return false
+		}
+	}
+	return true
 }
 
-// AsList returns options as a slice of strings.
-func (n NativeOption) AsList() []string {
-	// Return empty slice if no options are set.
-	if len(n) == 0 {
-		return make([]string, 0)
-	}
+// AsList returns options as a slice of strings sorted for stable output.
+func (n *NativeOption) AsList() []string {
+	s := n.AsOrderedList()
+
+	// Sort slice for output.
+	sort.Strings(s)
+	return s
+}
 
-	s := make([]string, len(n))
-	i := 0
-	for k, v := range n {
-		if v == "" {
+// AsOrderedList returns options as a slice of strings in insertion order, e.g. for dialects where rule order matters.
+func (n *NativeOption) AsOrderedList() []string {
+	s := make([]string, 0, len(n.keys))
+	for _, k := range n.keys {
+		if v := n.values[k]; v == "" {
 			//	Value only
-			s[i] = k
+			s = append(s, k)
 		} else {
 			// Key-Value pair
-			s[i] = fmt.Sprintf("%s=%s", k, v)
+			s = append(s, fmt.Sprintf("%s=%s", k, v))
 		}
-		i++
 	}
-
-	// Sort slice for output.
-	sort.Strings(s)
 	return s
 }
 
 // AddVal adds an option value string.
-func (n NativeOption) AddVal(val string) {
+func (n *NativeOption) AddVal(val string) {
 	// Ignore if value is empty.
 	if val == "" {
 		return
 	}
-	n[val] = ""
+	n.set(val, "")
 }
 
 // Delete removes an entry from the option map.
 // - key will match either key-value pairs or value-only settings.
-func (n NativeOption) Delete(key string) {
-	// Ignore empty key.
-	if key == "" {
+func (n *NativeOption) Delete(key string) {
+	// Ignore empty or missing key.
+	if _, ok := n.values[key]; key == "" || !ok {
 		return
 	}
-	delete(n, key)
+	delete(n.values, key)
+
+	for i, k := range n.keys {
+		if k == key {
+			n.keys = append(n.keys[:i:i], n.keys[i+1:]...)
+			break
+		}
+	}
 }
 
 // AddKeyVal adds an option string key=val
-func (n NativeOption) AddKeyVal(key, val string) {
+func (n *NativeOption) AddKeyVal(key, val string) {
 	// Ignore if key is empty.
 	if key == "" {
 		return
@@ -439,51 +771,113 @@ func (n NativeOption) AddKeyVal(key, val string) {
 	}
 
 	// Set value.
-	n[key] = val
+	n.set(key, val)
 }
 
 // AddBool adds a boolean as an option string.
-// - key is required
-//   - if key is empty, nothing is added
+// - key is required, if key is empty nothing is added
 // - val is boolean value
-func (n NativeOption) AddBool(key string, val bool) {
+func (n *NativeOption) AddBool(key string, val bool) {
 	// Ignore if key is missing.
 	if key == "" {
 		return
 	}
 
-	n[key] = fmt.Sprintf("%t", val)
+	n.set(key, fmt.Sprintf("%t", val))
 }
 
 // AddThreeFlag adds a ThreeFlag value as a string.
-// - key is required
-//   - if key is empty, nothing is added
+// - key is required, if key is empty nothing is added
 // - val is ThreeFlag value
-func (n NativeOption) AddThreeFlag(key string, val threeflag.ThreeFlag) {
+func (n *NativeOption) AddThreeFlag(key string, val threeflag.ThreeFlag) {
 	// Ignore if key is missing.
 	if key == "" {
 		return
 	}
 
-	n[key] = val.String()
+	n.set(key, val.String())
+}
+
+// AddList adds a list of values as an option string delimited by LIST_SEPARATOR.
+// - key is required, if key is empty nothing is added
+// - if vals is empty, key is deleted
+func (n *NativeOption) AddList(key string, vals []string) {
+	n.AddKeyVal(key, strings.Join(vals, LIST_SEPARATOR))
+}
+
+// GetList returns the values of a list option in order.
+// - Returns nil if the option is not set.
+func (n *NativeOption) GetList(key string) []string {
+	val, ok := n.values[key]
+	if !ok {
+		return nil
+	}
+	return SplitList(val)
+}
+
+// SplitList splits a list option string into its values.
+// - Values are trimmed and empty values are dropped.
+func SplitList(val string) []string {
+	out := []string{}
+	for _, v := range strings.Split(val, LIST_SEPARATOR) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
 
 // UpdateFrom updates with values from another NativeOption.
-func (n NativeOption) UpdateFrom(other NativeOption) {
-	for k, v := range other {
-		n[k] = v
+// - New keys are added in the insertion order of other.
+func (n *NativeOption) UpdateFrom(other NativeOption) {
+	for _, k := range other.keys {
+		n.set(k, other.values[k])
 	}
 }
 
 // Copy makes a copy of the NativeOption.
-func (n NativeOption) Copy() NativeOption {
+func (n *NativeOption) Copy() NativeOption {
 	c := NewNativeOption()
+	c.UpdateFrom(*n)
+	return c
+}
 
-	for k, v := range n {
-		c[k] = v
+// MarshalJSON marshals options as a JSON object with sorted keys like a map.
+func (n NativeOption) MarshalJSON() ([]byte, error) {
+	if n.values == nil {
+		return []byte("{}"), nil
 	}
+	return json.Marshal(n.values)
+}
 
-	return c
+// UnmarshalJSON loads options from a JSON object, keys are inserted in document order.
+func (n *NativeOption) UnmarshalJSON(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := d.Token(); err != nil {
+		return err
+	} else if tok == nil {
+		// null is an empty NativeOption.
+		*n = NewNativeOption()
+		return nil
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("options must be a JSON object, found %v", tok)
+	}
+
+	*n = NewNativeOption()
+	for d.More() {
+		key, err := d.Token()
+		if err != nil {
+			return err
+		}
+		var val string
+		if err := d.Decode(&val); err != nil {
+			return err
+		}
+		n.set(key.(string), val)
+	}
+
+	_, err := d.Token()
+	return err
 }
 
 // NativeType holds key-value attributes specific to one dialect.
@@ -501,6 +895,9 @@ type NativeType struct {
 	// TypeRef holds the native name of a type if different from the generic TypeRef.
 	TypeRef string `json:",omitempty"`
 
+	// Format refines the generic type, e.g. "int64" for integers or "date-time" for datetime.
+	Format string `json:",omitempty"`
+
 	// Include indicates whether an element should be included in output for a dialect.
 	// Include has three value values:
 	// - "" (empty string) means value is not set
@@ -567,6 +964,9 @@ func (n *NativeType) AsMap() map[string]string {
 	if n.TypeRef != "" {
 		m["TypeRef"] = n.TypeRef
 	}
+	if n.Format != "" {
+		m["Format"] = n.Format
+	}
 	if n.Error != "" {
 		m["Error"] = n.Error
 	}
@@ -586,6 +986,7 @@ func (n *NativeType) Copy() *NativeType {
 		Name:    n.Name,
 		Type:    n.Type,
 		TypeRef: n.TypeRef,
+		Format:  n.Format,
 		Include: n.Include,
 		Options: n.Options.Copy(),
 		Error:   n.Error,
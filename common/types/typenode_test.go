@@ -0,0 +1,338 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/util"
+)
+
+func TestTypeNode_GetNativeType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		genericName string
+		alias       string
+		wantName    string
+	}{
+		{
+			name:        "alias",
+			genericName: "Field",
+			alias:       "field",
+			wantName:    "field",
+		},
+		{
+			name:        "no-alias",
+			genericName: "Field",
+			wantName:    "Field",
+		},
+		{
+			name:     "empty-generic-name",
+			alias:    "field",
+			wantName: "field",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, test := range testCases {
+		node := NewTypeNode(test.genericName, "golang")
+		node.Type = "map"
+
+		json := NewNativeType("json")
+		json.Name = test.alias
+		json.Include = threeflag.Undefined
+		node.Native["json"] = json
+
+		got := node.GetNativeType("json")
+		if got.Name != test.wantName || got.Type != "map" {
+			t.Errorf("TEST_FAIL %s: got=%q,%q want=%q,%q", test.name, got.Name, got.Type, test.wantName, "map")
+		} else {
+			t.Logf("TEST_OK %s: got=%q", test.name, got.Name)
+		}
+	}
+}
+
+func TestTypeNode_PathString(t *testing.T) {
+	root := NewTypeNode(ROOT_NAME, "golang")
+	root.Type = "root"
+
+	top := root.NewChild("")
+	top.Type = "struct"
+	top.TypeRef = "Person"
+	top.NativeDefault().TypeRef = "Person"
+
+	name := top.NewChild("FullName")
+	name.Type = "string"
+	name.Native["json"] = NewNativeType("json")
+	name.Native["json"].Name = "fullName"
+
+	list := top.NewChild("Tags")
+	list.Type = "list"
+	item := list.NewChild("")
+	item.Type = "string"
+
+	dotted := top.NewChild("a.b")
+	dotted.Type = "integer"
+
+	bad := top.NewChild("Func")
+	bad.Type = "invalid:func"
+	bad.Error = InvalidKindErr
+
+	testCases := []struct {
+		name    string
+		node    *TypeNode
+		dialect string
+		want    string
+	}{
+		{name: "root", node: root, want: `Root`},
+		{name: "top", node: top, want: `Root.{}:Person`},
+		{name: "field", node: name, want: `Root.{}:Person.FullName:string`},
+		{name: "field-json", node: name, dialect: "json", want: `Root.{}:Person.fullName:string`},
+		{name: "list-item", node: item, want: `Root.{}:Person.Tags:[].string`},
+		{name: "dotted", node: dotted, want: `Root.{}:Person."a.b:integer"`},
+		{name: "error", node: bad, want: `Root.{}:Person.!Func:invalid:func!`},
+	}
+
+	for _, test := range testCases {
+		got := test.node.PathString(test.dialect)
+		if got != test.want {
+			t.Errorf("TEST_FAIL %s: got=%s want=%s", test.name, got, test.want)
+		} else {
+			t.Logf("TEST_OK %s: got=%s", test.name, got)
+		}
+	}
+}
+
+func TestTypeNode_Equals(t *testing.T) {
+	// newTree builds a struct with string fields in the given order and a list of integers.
+	newTree := func(fields ...string) *TypeNode {
+		root := NewTypeNode("", "golang")
+		root.Type = "struct"
+		for _, field := range fields {
+			root.NewChild(field).Type = "string"
+		}
+
+		list := root.NewChild("List")
+		list.Type = "list"
+		list.NewChild("").Type = "integer"
+		return root
+	}
+
+	testCases := []struct {
+		name          string
+		a             *TypeNode
+		b             *TypeNode
+		change        func(a, b *TypeNode)
+		includeNative bool
+		want          bool
+	}{
+		{name: "equal", a: newTree("A", "B"), b: newTree("A", "B"), want: true},
+		{name: "reordered", a: newTree("A", "B"), b: newTree("B", "A"), want: true},
+		{name: "nil", want: true},
+		{name: "nil-other", a: newTree("A"), want: false},
+		{name: "nil-receiver", b: newTree("A"), want: false},
+		{name: "child-count", a: newTree("A", "B"), b: newTree("A"), want: false},
+		{name: "child-name", a: newTree("A"), b: newTree("B"), want: false},
+		{
+			name: "type",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.ChildByName("List", nil).Children[0].Type = "float"
+			},
+			want: false,
+		},
+		{
+			name: "error",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.ChildByName("A", nil).Error = MaxDepthErr
+			},
+			want: false,
+		},
+		{
+			name: "type-ref",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.TypeRef = "Other"
+			},
+			want: false,
+		},
+		{
+			name: "native-ignored",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.ChildByName("A", nil).NativeDefault().Options.AddBool("IsZero", true)
+			},
+			want: true,
+		},
+		{
+			name: "native-option",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.ChildByName("A", nil).NativeDefault().Options.AddBool("IsZero", true)
+			},
+			includeNative: true,
+			want:          false,
+		},
+		{
+			name: "native-dialect",
+			a:    newTree("A"),
+			b:    newTree("A"),
+			change: func(a, b *TypeNode) {
+				b.Native["json"] = NewNativeType("json")
+			},
+			includeNative: true,
+			want:          false,
+		},
+		{name: "native-equal", a: newTree("B", "A"), b: newTree("A", "B"), includeNative: true, want: true},
+	}
+
+	for _, test := range testCases {
+		if test.change != nil {
+			test.change(test.a, test.b)
+		}
+
+		got := test.a.Equals(test.b, test.includeNative)
+		if got != test.want {
+			t.Errorf("TEST_FAIL %s: got=%t want=%t", test.name, got, test.want)
+		} else {
+			t.Logf("TEST_OK %s: got=%t", test.name, got)
+		}
+
+		// Equality is symmetric.
+		if got := test.b.Equals(test.a, test.includeNative); got != test.want {
+			t.Errorf("TEST_FAIL %s: reversed got=%t want=%t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTypeNode_SetError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		recursive bool
+		wantChild string
+	}{
+		{name: "not-recursive", wantChild: "child error"},
+		{name: "recursive", recursive: true},
+	}
+
+	for _, test := range testCases {
+		parent := NewTypeNode("Parent", "golang")
+		child := parent.NewChild("Child")
+
+		parent.SetError("parent error")
+		child.SetError("child error")
+		if parent.NativeDefault().Error != parent.Error || child.NativeDefault().Error != child.Error {
+			t.Errorf("TEST_FAIL %s: native errors not set", test.name)
+			continue
+		}
+
+		parent.ClearErrors(test.recursive)
+		if parent.Error != "" || parent.NativeDefault().Error != "" {
+			t.Errorf("TEST_FAIL %s: parent got=%q,%q want empty", test.name, parent.Error, parent.NativeDefault().Error)
+		} else if child.Error != test.wantChild || child.NativeDefault().Error != test.wantChild {
+			t.Errorf("TEST_FAIL %s: child got=%q,%q want=%q", test.name, child.Error, child.NativeDefault().Error, test.wantChild)
+		} else {
+			t.Logf("TEST_OK %s", test.name)
+		}
+	}
+}
+
+func TestTypeNode_Category(t *testing.T) {
+	testCases := []struct {
+		typeName string
+		want     string
+	}{
+		{typeName: "string", want: "basic"},
+		{typeName: "integer", want: "basic"},
+		{typeName: "struct", want: "compound"},
+		{typeName: "union", want: "compound"},
+		{typeName: "datetime", want: "known"},
+		{typeName: "any", want: "known"},
+		{typeName: "pointer", want: "reference"},
+		{typeName: "interface", want: "reference"},
+		{typeName: "invalid", want: "invalid"},
+		{typeName: "invalid:chan", want: "invalid"},
+		{typeName: "", want: "invalid"},
+		{typeName: "root", want: "internal"},
+	}
+
+	for _, test := range testCases {
+		node := NewTypeNode("Field", "golang")
+		node.Type = test.typeName
+
+		// Exactly one helper matches the category, internal types match none.
+		flags := []string{}
+		for name, ok := range map[string]bool{
+			"basic":     node.IsBasicType(),
+			"compound":  node.IsCompound(),
+			"known":     node.IsKnown(),
+			"reference": node.IsReference(),
+			"invalid":   node.IsInvalid(),
+		} {
+			if ok {
+				flags = append(flags, name)
+			}
+		}
+
+		want := []string{test.want}
+		if test.want == "internal" {
+			want = []string{}
+		}
+
+		if got := node.Category().String(); got != test.want {
+			t.Errorf("TEST_FAIL %q: category got=%q want=%q", test.typeName, got, test.want)
+		} else if strings.Join(flags, ",") != strings.Join(want, ",") {
+			t.Errorf("TEST_FAIL %q: helpers got=%v want=%v", test.typeName, flags, want)
+		} else {
+			t.Logf("TEST_OK %q: %s", test.typeName, got)
+		}
+	}
+}
+
+func TestNativeOption_AsOrderedList(t *testing.T) {
+	n := NewNativeOption()
+	n.AddVal("required")
+	n.AddKeyVal("minLength", "1")
+	n.AddKeyVal("pattern", "^[a-z]+$")
+	n.AddKeyVal("minLength", "2")
+	n.Delete("pattern")
+	n.AddVal("trim")
+
+	// Replacing a value keeps its position, deleted keys are removed.
+	util.CompareStrings(t, "ordered", n.AsOrderedList(), []string{"required", "minLength=2", "trim"})
+	util.CompareStrings(t, "sorted", n.AsList(), []string{"minLength=2", "required", "trim"})
+
+	// Copies and updates keep the order.
+	c := n.Copy()
+	c.UpdateFrom(NativeOptionFromMap(map[string]string{"b": "1", "a": "2"}))
+	util.CompareStrings(t, "copy", c.AsOrderedList(), []string{"required", "minLength=2", "trim", "a=2", "b=1"})
+	util.CompareStrings(t, "original", n.AsOrderedList(), []string{"required", "minLength=2", "trim"})
+
+	// Unmarshaled options are in document order.
+	var u NativeOption
+	if err := json.Unmarshal([]byte(`{"z":"1","required":"","a":"2"}`), &u); err != nil {
+		t.Fatalf("TEST_FAIL unmarshal: err=%s", err)
+	}
+	util.CompareStrings(t, "unmarshal", u.AsOrderedList(), []string{"z=1", "required", "a=2"})
+
+	// Marshaled options have sorted keys like a map.
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("TEST_FAIL marshal: err=%s", err)
+	}
+	util.CompareStrings(t, "marshal", []string{string(b)}, []string{`{"a":"2","required":"","z":"1"}`})
+}
+
+func TestParseTags_Order(t *testing.T) {
+	tag := ParseTags(`b9schema:",required,minLength=1,pattern=x"`)[TAG_DIALECT]
+	util.CompareStrings(t, "tag-order", tag.Options.AsOrderedList(), []string{"required", "minLength=1", "pattern=x"})
+}
@@ -3,6 +3,8 @@ package util
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ValueIfTrue converts a boolean into strings for true and false.
@@ -89,14 +91,46 @@ func BlockIndent(txt string, prefixes []string, indents []string) string {
 }
 
 // Capitalize returns string with its first letter in uppercase.
+// - The first rune is converted so that multi-byte UTF-8 letters (e.g. "é") are capitalized.
+// - Runes without an uppercase form (e.g. CJK characters) are unchanged.
 func Capitalize(s string) string {
-	// Capitalize entire string if it is short.
-	if len(s) < 2 {
-		return strings.ToUpper(s)
+	if s == "" {
+		return s
 	}
 
-	// Build a new string with 1st letter uppercase.
-	return strings.ToUpper(s[0:1]) + s[1:]
+	// Only the first rune is changed, the rest of the string is kept as is.
+	r, size := utf8.DecodeRuneInString(s)
+	upper := unicode.ToUpper(r)
+	if upper == r {
+		return s
+	}
+	return string(upper) + s[size:]
+}
+
+// ToIdentifier converts a string such as a URL path into an exported identifier.
+// - Only the last path segment is used.
+// - Non-alphanumeric characters are treated as word separators.
+// - Each word is capitalized, e.g. "/path/to/main-struct" --> "MainStruct"
+func ToIdentifier(s string) string {
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	out := ""
+	for _, w := range words {
+		out += Capitalize(w)
+	}
+
+	// Identifiers cannot start with a digit.
+	if out != "" && unicode.IsDigit([]rune(out)[0]) {
+		out = "_" + out
+	}
+
+	return out
 }
 
 // AppendStrings adds non-empty strings from in to out and returns a new slice.
@@ -0,0 +1,33 @@
+package util
+
+import "testing"
+
+func TestCapitalize(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "a", want: "A"},
+		{in: "A", want: "A"},
+		{in: "key", want: "Key"},
+		{in: "keyName", want: "KeyName"},
+		{in: "1key", want: "1key"},
+		{in: "é", want: "É"},
+		{in: "élan", want: "Élan"},
+		{in: "ßtraße", want: "ßtraße"},
+		{in: "ωmega", want: "Ωmega"},
+		{in: "日本", want: "日本"},
+		{in: "名", want: "名"},
+		{in: "a\xffb", want: "A\xffb"},
+		{in: "\xffab", want: "\xffab"},
+	}
+
+	for _, test := range testCases {
+		if got := Capitalize(test.in); got != test.want {
+			t.Errorf("TEST_FAIL %q: got=%q want=%q", test.in, got, test.want)
+		} else {
+			t.Logf("TEST_OK %q: got=%q", test.in, got)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package fixtures
+
+// Address has the same name as a different type in package main, see TestReflector_TypeRefCollision.
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+// Label has the same name and definition as a type in package main.
+type Label struct {
+	Text string `json:"text"`
+}
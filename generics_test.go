@@ -0,0 +1,175 @@
+//go:build go1.18
+// +build go1.18
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer/openapi"
+	"github.com/gitmann/b9schema-golang/renderer/simple"
+)
+
+// Instantiated generic types need Go 1.18 so they are tested in their own file.
+
+type GenericResponse[T any] struct {
+	Data  T      `json:"data"`
+	Error string `json:"error,omitempty"`
+}
+
+type GenericPair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type GenericTypes struct {
+	User  GenericResponse[BasicStruct]    `json:"user"`
+	Users GenericResponse[[]*BasicStruct] `json:"users"`
+	Pair  GenericPair[string, int]        `json:"pair"`
+}
+
+func TestReflector_GenericTypeNames(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(GenericTypes{}, "/generic")
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:GenericTypes`,
+		`TypeRef.BasicStruct:{}`,
+		`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+		`TypeRef.BasicStruct:{}.Float64Val:float`,
+		`TypeRef.BasicStruct:{}.IntVal:integer`,
+		`TypeRef.BasicStruct:{}.StringVal:string`,
+		`TypeRef.GenericPairStringInt:{}`,
+		`TypeRef.GenericPairStringInt:{}.Key:string`,
+		`TypeRef.GenericPairStringInt:{}.Value:integer`,
+		`TypeRef.GenericResponseBasicStruct:{}`,
+		`TypeRef.GenericResponseBasicStruct:{}.Data:{}:BasicStruct`,
+		`TypeRef.GenericResponseBasicStruct:{}.Error:string`,
+		`TypeRef.GenericResponseListBasicStruct:{}`,
+		`TypeRef.GenericResponseListBasicStruct:{}.Data:[]`,
+		`TypeRef.GenericResponseListBasicStruct:{}.Data:[].{}:BasicStruct`,
+		`TypeRef.GenericResponseListBasicStruct:{}.Error:string`,
+		`TypeRef.GenericTypes:{}`,
+		`TypeRef.GenericTypes:{}.Pair:{}:GenericPairStringInt`,
+		`TypeRef.GenericTypes:{}.User:{}:GenericResponseBasicStruct`,
+		`TypeRef.GenericTypes:{}.Users:{}:GenericResponseListBasicStruct`,
+	}
+	util.CompareStrings(t, "generic/simple", gotStrings, wantStrings)
+
+	gotStrings, err := openapi.NewOpenAPIRenderer(openapi.NewMetaData("generic", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL generic/openapi: err=%s", err)
+	}
+	wantStrings = []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: generic`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /generic:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/GenericTypes'`,
+		`components:`,
+		`  schemas:`,
+		`    BasicStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        BoolVal:`,
+		`          type: boolean`,
+		`        Float64Val:`,
+		`          type: number`,
+		`          format: double`,
+		`        IntVal:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        StringVal:`,
+		`          type: string`,
+		`    GenericPairStringInt:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        key:`,
+		`          type: string`,
+		`        value:`,
+		`          type: integer`,
+		`          format: int64`,
+		`    GenericResponseBasicStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        data:`,
+		`          $ref: '#/components/schemas/BasicStruct'`,
+		`        error:`,
+		`          type: string`,
+		`    GenericResponseListBasicStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        data:`,
+		`          type: array`,
+		`          items:`,
+		`            nullable: true`,
+		`            allOf:`,
+		`            - $ref: '#/components/schemas/BasicStruct'`,
+		`        error:`,
+		`          type: string`,
+		`    GenericTypes:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        pair:`,
+		`          $ref: '#/components/schemas/GenericPairStringInt'`,
+		`        user:`,
+		`          $ref: '#/components/schemas/GenericResponseBasicStruct'`,
+		`        users:`,
+		`          $ref: '#/components/schemas/GenericResponseListBasicStruct'`,
+	}
+	util.CompareStrings(t, "generic/openapi", gotStrings, wantStrings)
+
+	if validateOpenAPI(t, "generic", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK generic: swagger")
+	}
+
+	// The Go type name is kept in a native option.
+	ref := schema.TypeRef.ChildByName("GenericResponseBasicStruct", nil)
+	if ref == nil {
+		t.Fatalf("TEST_FAIL generic/raw: missing TypeRef")
+	}
+	util.CompareStrings(t, "generic/raw", []string{ref.NativeDefault().Options.Get("Type.Name")}, []string{
+		"GenericResponse[github.com/gitmann/b9schema-golang.BasicStruct]",
+	})
+}
+
+func TestReflector_TypeNameFunc(t *testing.T) {
+	r := reflector.NewReflector()
+	r.TypeNameFunc = func(name string) string {
+		return "API" + reflector.SanitizeTypeName(name)
+	}
+	schema := r.DeriveSchema(GenericPair[string, BasicStruct]{}, "/pair")
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:APIGenericPairStringBasicStruct`,
+		`TypeRef.APIBasicStruct:{}`,
+		`TypeRef.APIBasicStruct:{}.BoolVal:boolean`,
+		`TypeRef.APIBasicStruct:{}.Float64Val:float`,
+		`TypeRef.APIBasicStruct:{}.IntVal:integer`,
+		`TypeRef.APIBasicStruct:{}.StringVal:string`,
+		`TypeRef.APIGenericPairStringBasicStruct:{}`,
+		`TypeRef.APIGenericPairStringBasicStruct:{}.Key:string`,
+		`TypeRef.APIGenericPairStringBasicStruct:{}.Value:{}:APIBasicStruct`,
+	}
+	util.CompareStrings(t, "type-name-func", gotStrings, wantStrings)
+}
@@ -1,41 +1,67 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	types2 "go/types"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 	"unsafe"
 
 	"github.com/ghodss/yaml"
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/types"
 	"github.com/gitmann/b9schema-golang/common/util"
 	"github.com/gitmann/b9schema-golang/fixtures"
 	"github.com/gitmann/b9schema-golang/reflector"
 	"github.com/gitmann/b9schema-golang/renderer"
+	"github.com/gitmann/b9schema-golang/renderer/cue"
+	"github.com/gitmann/b9schema-golang/renderer/gostruct"
+	"github.com/gitmann/b9schema-golang/renderer/html"
+	"github.com/gitmann/b9schema-golang/renderer/markdown"
 	"github.com/gitmann/b9schema-golang/renderer/openapi"
+	"github.com/gitmann/b9schema-golang/renderer/python"
 	"github.com/gitmann/b9schema-golang/renderer/simple"
+	"github.com/gitmann/b9schema-golang/renderer/sqlddl"
+	"github.com/gitmann/b9schema-golang/renderer/typescript"
+	"github.com/gitmann/b9schema-golang/renderer/zod"
 )
 
 const (
-	OPENAPI_CLI      = "swagger-cli"
+	OPENAPI_CLI = "swagger-cli"
+
+	// OPENAPI_CLI_FILE is the name of the scratch file in a temporary directory, see validateOpenAPI.
 	OPENAPI_CLI_FILE = "swagger-validate.yaml"
 )
 
 var allTests = map[string][]fixtures.TestCase{
-	"01-root-jaon": rootJSONTests,
-	"02-root-go":   rootGoTests,
-	"03-type":      typeTests,
-	"04-list":      listTests,
-	"05-compound":  compoundTests,
-	"06-reference": referenceTests,
-	"07-cycle":     cycleTests,
-	"08-json-tag":  jsonTagTests,
-	"09-nested":    nestedTests,
+	"01-root-jaon":    rootJSONTests,
+	"02-root-go":      rootGoTests,
+	"03-type":         typeTests,
+	"04-list":         listTests,
+	"05-compound":     compoundTests,
+	"06-reference":    referenceTests,
+	"07-cycle":        cycleTests,
+	"08-json-tag":     jsonTagTests,
+	"09-nested":       nestedTests,
+	"10-markdown":     markdownTests,
+	"11-b9schema-tag": b9schemaTagTests,
 }
 
 // *** All reflect types ***
@@ -299,6 +325,21 @@ var rootGoTests = []fixtures.TestCase{
 			},
 		},
 	},
+	{
+		Name:  "golang-struct-ignored",
+		Value: IgnoredStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.!{}:IgnoredStruct! ERROR:struct has no exported fields`,
+					`TypeRef.!IgnoredStruct:{}! ERROR:struct has no exported fields`,
+				},
+				true: []string{
+					`Root.!{}! ERROR:struct has no exported fields`,
+				},
+			},
+		},
+	},
 
 	{
 		Name:  "golang-interface-struct-noinit",
@@ -405,6 +446,21 @@ type CompoundTypes struct {
 // Special types from protobuf: https://developers.google.com/protocol-buffers/docs/reference/google.protobuf
 type SpecialTypes struct {
 	DateTime time.Time
+	Duration time.Duration
+}
+
+// Pointers to special types are nullable values, not references to a "Time" or "Duration" type.
+type NullableSpecialTypes struct {
+	DateTime *time.Time     `json:"dateTime"`
+	Duration *time.Duration `json:"duration"`
+}
+
+// DurationTypes has time.Duration fields in compound types.
+type DurationTypes struct {
+	Timeout   time.Duration            `json:"timeout"`
+	Retry     time.Duration            `json:"retry,omitempty"`
+	Intervals []time.Duration          `json:"intervals"`
+	Limits    map[string]time.Duration `json:"limits"`
 }
 
 // Redefine types.
@@ -464,31 +520,190 @@ type RedefineStruct struct {
 	DateTime   MyDateTime
 }
 
+// TextID implements encoding.TextMarshaler with a value receiver.
+type TextID struct {
+	hi, lo uint64
+}
+
+func (id TextID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%016x%016x", id.hi, id.lo)), nil
+}
+
+// TextCode implements encoding.TextMarshaler with a pointer receiver.
+type TextCode int
+
+func (c *TextCode) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("C%d", *c)), nil
+}
+
+type TextTypes struct {
+	ID      TextID
+	IDPtr   *TextID
+	Code    TextCode
+	IP      net.IP
+	IDList  []TextID
+	Created time.Time
+}
+
+type DeprecatedTypes struct {
+	Name    string       `json:"name"`
+	OldName string       `json:"oldName,omitempty" b9schema:"deprecated"`
+	Legacy  *BasicStruct `json:"legacy" b9schema:"deprecated,desc=Use name instead."`
+	Current int          `json:"current" b9schema:"deprecated=false"`
+}
+
+type ExampleTypes struct {
+	Name    string    `json:"name" b9schema:"example=Jane Doe"`
+	Code    string    `json:"code" b9schema:"example=42"`
+	Count   int       `json:"count" b9schema:"example=42"`
+	Ratio   float64   `json:"ratio" b9schema:"example=0.5"`
+	Active  bool      `json:"active" b9schema:"example=true"`
+	Created time.Time `json:"created" b9schema:"example=2021-01-02T03:04:05Z"`
+}
+
+type ConstraintTypes struct {
+	Name    string  `json:"name" b9schema:"minLength=1,maxLength=255"`
+	Percent int     `json:"percent" b9schema:"minimum=0,maximum=100"`
+	Ratio   float64 `json:"ratio" b9schema:"minimum=-1.5"`
+	Invalid string  `json:"invalid" b9schema:"minLength=-1,maxLength=many"`
+}
+
+type StringEncodedTypes struct {
+	ID     int64   `json:"id,string"`
+	Count  int     `json:"count,string,omitempty"`
+	Ratio  float64 `json:"ratio,string"`
+	Active bool    `json:"active,string"`
+	Name   string  `json:"name,string"`
+}
+
+type InlineExtraTypes struct {
+	ID     int                    `json:"id"`
+	Name   string                 `json:"name,omitempty"`
+	Extras map[string]interface{} `json:"extras" b9schema:"inline"`
+}
+
+type InlineValueTypes struct {
+	Kind   string            `json:"kind"`
+	Labels map[string]string `json:"labels" b9schema:"inline"`
+}
+
+type InlineTypes struct {
+	Extras InlineExtraTypes `json:"extras"`
+	Values InlineValueTypes `json:"values"`
+}
+
+type InlineErrorTypes struct {
+	Name   string            `json:"name" b9schema:"inline"`
+	Extras map[string]string `json:"extras" b9schema:"inline"`
+	Labels map[string]string `json:"labels" b9schema:"inline"`
+}
+
+type KeyPatternTypes struct {
+	Labels map[string]string `json:"labels" b9schema:"keyPattern=^[a-z]+$,minProperties=1,maxProperties=8"`
+	Extras map[string]int    `json:"extras"`
+	Bad    string            `json:"bad" b9schema:"keyPattern=^[a-z]+$"`
+}
+
+// Money marshals to a string such as "1.25 USD" instead of an object.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency))
+}
+
+type CustomMarshalerTypes struct {
+	Price    Money  `json:"price"`
+	PricePtr *Money `json:"pricePtr"`
+	Name     string `json:"name"`
+}
+
+type PatternTypes struct {
+	Date  string `json:"date" b9schema:"pattern=^\\d{4}-\\d{2}-\\d{2}$"`
+	Code  string `json:"code" b9schema:"pattern='^[A-Z]{2,3}(,[A-Z]{2,3})*$',maxLength=64"`
+	Count int    `json:"count" b9schema:"pattern=^[0-9]+$"`
+}
+
+type ByteTypes struct {
+	Data    []byte          `json:"data"`
+	DataPtr *[]byte         `json:"dataPtr"`
+	Array   [4]byte         `json:"array"`
+	Raw     json.RawMessage `json:"raw"`
+}
+
+type SQLNullTypes struct {
+	NullString  sql.NullString  `json:"nullString"`
+	NullInt64   sql.NullInt64   `json:"nullInt64"`
+	NullInt32   sql.NullInt32   `json:"nullInt32"`
+	NullBool    sql.NullBool    `json:"nullBool"`
+	NullFloat64 sql.NullFloat64 `json:"nullFloat64"`
+	NullTime    sql.NullTime    `json:"nullTime"`
+}
+
+// Shape is implemented by Circle and Square, see TestReflector_AllTests.
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return 3.14159 * c.Radius * c.Radius }
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type ShapeTypes struct {
+	Name   string  `json:"name"`
+	Shape  Shape   `json:"shape"`
+	Shapes []Shape `json:"shapes"`
+}
+
 var typeTests = []fixtures.TestCase{
 	{
-		Name:  "boolean",
-		Value: BoolTypes{},
+		Name:  "pattern",
+		Value: PatternTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:BoolTypes`,
-					`TypeRef.BoolTypes:{}`,
-					`TypeRef.BoolTypes:{}.Bool:boolean`,
+					`# Root`,
+					`## 03-type/pattern`,
+					`Type: struct (PatternTypes)`,
+					`# TypeRef`,
+					`## PatternTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| code | string | yes | no |  |`,
+					`| count | integer | yes | no | ERROR: invalid pattern option |`,
+					`| date | string | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Bool:boolean`,
+					`# Root`,
+					`## 03-type/pattern`,
+					`Type: struct (PatternTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| code | string | yes | no |  |`,
+					`| count | integer | yes | no | ERROR: invalid pattern option |`,
+					`| date | string | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: boolean`,
+					`  title: pattern`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/boolean:`,
+					`  /03-type/pattern:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -497,24 +712,33 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/BoolTypes'`,
+					`                $ref: '#/components/schemas/PatternTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    BoolTypes:`,
+					`    PatternTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Bool:`,
-					`          type: boolean`,
+					`        code:`,
+					`          type: string`,
+					`          pattern: '^[A-Z]{2,3}(,[A-Z]{2,3})*$'`,
+					`          maxLength: 64`,
+					`        count:`,
+					`          description: 'ERROR=invalid pattern option'`,
+					`          type: integer`,
+					`          format: int64`,
+					`        date:`,
+					`          type: string`,
+					`          pattern: '^\d{4}-\d{2}-\d{2}$'`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: boolean`,
+					`  title: pattern`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/boolean:`,
+					`  /03-type/pattern:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -523,60 +747,107 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/BoolTypes'`,
+					`                description: 'From $ref: #/components/schemas/PatternTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Bool:`,
-					`                    type: boolean`,
+					`                  code:`,
+					`                    type: string`,
+					`                    pattern: '^[A-Z]{2,3}(,[A-Z]{2,3})*$'`,
+					`                    maxLength: 64`,
+					`                  count:`,
+					`                    description: 'ERROR=invalid pattern option'`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  date:`,
+					`                    type: string`,
+					`                    pattern: '^\d{4}-\d{2}-\d{2}$'`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:PatternTypes`,
+					`TypeRef.PatternTypes:{}`,
+					`TypeRef.PatternTypes:{}.Code:string`,
+					`TypeRef.PatternTypes:{}.!Count:integer! ERROR:invalid pattern option`,
+					`TypeRef.PatternTypes:{}.Date:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Code:string`,
+					`Root.{}.!Count:integer! ERROR:invalid pattern option`,
+					`Root.{}.Date:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface PatternTypes {`,
+					`  code: string;`,
+					`  // ERROR: invalid pattern option`,
+					`  count: number;`,
+					`  date: string;`,
+					`}`,
+				},
+				true: []string{
+					`export interface PatternTypes {`,
+					`  code: string;`,
+					`  // ERROR: invalid pattern option`,
+					`  count: number;`,
+					`  date: string;`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "integer",
-		Value: IntegerTypes{},
+		Name:  "custom-marshaler",
+		Value: CustomMarshalerTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:IntegerTypes`,
-					`TypeRef.IntegerTypes:{}`,
-					`TypeRef.IntegerTypes:{}.Int:integer`,
-					`TypeRef.IntegerTypes:{}.Int16:integer`,
-					`TypeRef.IntegerTypes:{}.Int32:integer`,
-					`TypeRef.IntegerTypes:{}.Int64:integer`,
-					`TypeRef.IntegerTypes:{}.Int8:integer`,
-					`TypeRef.IntegerTypes:{}.Uint:integer`,
-					`TypeRef.IntegerTypes:{}.Uint16:integer`,
-					`TypeRef.IntegerTypes:{}.Uint32:integer`,
-					`TypeRef.IntegerTypes:{}.Uint64:integer`,
-					`TypeRef.IntegerTypes:{}.Uint8:integer`,
-					`TypeRef.IntegerTypes:{}.Uintptr:integer`,
+					`# Root`,
+					`## 03-type/custom-marshaler`,
+					`Type: struct (CustomMarshalerTypes)`,
+					`# TypeRef`,
+					`## CustomMarshalerTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| name | string | yes | no |  |`,
+					`| price | struct (Money) | yes | no |  |`,
+					`| pricePtr | struct (Money) | no | yes |  |`,
+					`## Money`,
+					`Type: struct`,
+					`Error: custom json marshaler, schema may not match JSON`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| Cents | integer | yes | no |  |`,
+					`| Currency | string | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Int:integer`,
-					`Root.{}.Int16:integer`,
-					`Root.{}.Int32:integer`,
-					`Root.{}.Int64:integer`,
-					`Root.{}.Int8:integer`,
-					`Root.{}.Uint:integer`,
-					`Root.{}.Uint16:integer`,
-					`Root.{}.Uint32:integer`,
-					`Root.{}.Uint64:integer`,
-					`Root.{}.Uint8:integer`,
-					`Root.{}.Uintptr:integer`,
+					`# Root`,
+					`## 03-type/custom-marshaler`,
+					`Type: struct (CustomMarshalerTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| name | string | yes | no |  |`,
+					`| price | struct (Money) | yes | no | ERROR: custom json marshaler, schema may not match JSON |`,
+					`| price.Cents | integer | yes | no |  |`,
+					`| price.Currency | string | yes | no |  |`,
+					`| pricePtr | struct (Money) | no | yes | ERROR: custom json marshaler, schema may not match JSON |`,
+					`| pricePtr.Cents | integer | yes | no |  |`,
+					`| pricePtr.Currency | string | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: integer`,
+					`  title: custom-marshaler`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/integer:`,
+					`  /03-type/custom-marshaler:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -585,46 +856,40 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/IntegerTypes'`,
+					`                $ref: '#/components/schemas/CustomMarshalerTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    IntegerTypes:`,
+					`    CustomMarshalerTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Int:`,
-					`          type: integer`,
-					`        Int16:`,
-					`          type: integer`,
-					`        Int32:`,
-					`          type: integer`,
-					`        Int64:`,
-					`          type: integer`,
-					`          format: int64`,
-					`        Int8:`,
-					`          type: integer`,
-					`        Uint:`,
-					`          type: integer`,
-					`        Uint16:`,
-					`          type: integer`,
-					`        Uint32:`,
-					`          type: integer`,
-					`        Uint64:`,
+					`        name:`,
+					`          type: string`,
+					`        price:`,
+					`          $ref: '#/components/schemas/Money'`,
+					`        pricePtr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/Money'`,
+					`    Money:`,
+					`      description: 'ERROR=custom json marshaler, schema may not match JSON'`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Cents:`,
 					`          type: integer`,
 					`          format: int64`,
-					`        Uint8:`,
-					`          type: integer`,
-					`        Uintptr:`,
-					`          type: integer`,
+					`        Currency:`,
+					`          type: string`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: integer`,
+					`  title: custom-marshaler`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/integer:`,
+					`  /03-type/custom-marshaler:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -633,64 +898,130 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/IntegerTypes'`,
+					`                description: 'From $ref: #/components/schemas/CustomMarshalerTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Int:`,
-					`                    type: integer`,
-					`                  Int16:`,
-					`                    type: integer`,
-					`                  Int32:`,
-					`                    type: integer`,
-					`                  Int64:`,
-					`                    type: integer`,
-					`                    format: int64`,
-					`                  Int8:`,
-					`                    type: integer`,
-					`                  Uint:`,
-					`                    type: integer`,
-					`                  Uint16:`,
-					`                    type: integer`,
-					`                  Uint32:`,
-					`                    type: integer`,
-					`                  Uint64:`,
-					`                    type: integer`,
-					`                    format: int64`,
-					`                  Uint8:`,
-					`                    type: integer`,
-					`                  Uintptr:`,
-					`                    type: integer`,
+					`                  name:`,
+					`                    type: string`,
+					`                  price:`,
+					`                    description: 'From $ref: #/components/schemas/Money;ERROR=custom json marshaler, schema may not match JSON'`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      Cents:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      Currency:`,
+					`                        type: string`,
+					`                  pricePtr:`,
+					`                    description: 'From $ref: #/components/schemas/Money;ERROR=custom json marshaler, schema may not match JSON'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      Cents:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      Currency:`,
+					`                        type: string`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:CustomMarshalerTypes`,
+					`TypeRef.CustomMarshalerTypes:{}`,
+					`TypeRef.CustomMarshalerTypes:{}.Name:string`,
+					`TypeRef.CustomMarshalerTypes:{}.Price:{}:Money`,
+					`TypeRef.CustomMarshalerTypes:{}.PricePtr:{}:Money`,
+					`TypeRef.!Money:{}! ERROR:custom json marshaler, schema may not match JSON`,
+					`TypeRef.!Money:{}!.Cents:integer`,
+					`TypeRef.!Money:{}!.Currency:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Name:string`,
+					`Root.{}.!Price:{}! ERROR:custom json marshaler, schema may not match JSON`,
+					`Root.{}.!Price:{}!.Cents:integer`,
+					`Root.{}.!Price:{}!.Currency:string`,
+					`Root.{}.!PricePtr:{}! ERROR:custom json marshaler, schema may not match JSON`,
+					`Root.{}.!PricePtr:{}!.Cents:integer`,
+					`Root.{}.!PricePtr:{}!.Currency:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface CustomMarshalerTypes {`,
+					`  name: string;`,
+					`  price: Money;`,
+					`  pricePtr?: Money;`,
+					`}`,
+					`// ERROR: custom json marshaler, schema may not match JSON`,
+					`export interface Money {`,
+					`  Cents: number;`,
+					`  Currency: string;`,
+					`}`,
+				},
+				true: []string{
+					`export interface CustomMarshalerTypes {`,
+					`  name: string;`,
+					`  // ERROR: custom json marshaler, schema may not match JSON`,
+					`  price: {`,
+					`    Cents: number;`,
+					`    Currency: string;`,
+					`  };`,
+					`  // ERROR: custom json marshaler, schema may not match JSON`,
+					`  pricePtr?: {`,
+					`    Cents: number;`,
+					`    Currency: string;`,
+					`  };`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  `float`,
-		Value: FloatTypes{},
+		Name:  "key-pattern",
+		Value: KeyPatternTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:FloatTypes`,
-					`TypeRef.FloatTypes:{}`,
-					`TypeRef.FloatTypes:{}.Float32:float`,
-					`TypeRef.FloatTypes:{}.Float64:float`,
+					`# Root`,
+					`## 03-type/key-pattern`,
+					`Type: struct (KeyPatternTypes)`,
+					`# TypeRef`,
+					`## KeyPatternTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| bad | string | yes | no | ERROR: invalid keyPattern option |`,
+					`| extras | map | yes | no |  |`,
+					`| extras{*} | integer | - | no |  |`,
+					`| labels | map | yes | no |  |`,
+					`| labels{*} | string | - | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Float32:float`,
-					`Root.{}.Float64:float`,
+					`# Root`,
+					`## 03-type/key-pattern`,
+					`Type: struct (KeyPatternTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| bad | string | yes | no | ERROR: invalid keyPattern option |`,
+					`| extras | map | yes | no |  |`,
+					`| extras{*} | integer | - | no |  |`,
+					`| labels | map | yes | no |  |`,
+					`| labels{*} | string | - | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: float`,
+					`  title: key-pattern`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/float:`,
+					`  /03-type/key-pattern:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -699,27 +1030,36 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/FloatTypes'`,
+					`                $ref: '#/components/schemas/KeyPatternTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    FloatTypes:`,
+					`    KeyPatternTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Float32:`,
-					`          type: number`,
-					`        Float64:`,
-					`          type: number`,
-					`          format: double`,
+					`        bad:`,
+					`          description: 'ERROR=invalid keyPattern option'`,
+					`          type: string`,
+					`        extras:`,
+					`          type: object`,
+					`          additionalProperties:`,
+					`            type: integer`,
+					`            format: int64`,
+					`        labels:`,
+					`          type: object`,
+					`          minProperties: 1`,
+					`          maxProperties: 8`,
+					`          additionalProperties:`,
+					`            type: string`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: float`,
+					`  title: key-pattern`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/float:`,
+					`  /03-type/key-pattern:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -728,43 +1068,109 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/FloatTypes'`,
+					`                description: 'From $ref: #/components/schemas/KeyPatternTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Float32:`,
-					`                    type: number`,
-					`                  Float64:`,
-					`                    type: number`,
-					`                    format: double`,
+					`                  bad:`,
+					`                    description: 'ERROR=invalid keyPattern option'`,
+					`                    type: string`,
+					`                  extras:`,
+					`                    type: object`,
+					`                    additionalProperties:`,
+					`                      type: integer`,
+					`                      format: int64`,
+					`                  labels:`,
+					`                    type: object`,
+					`                    minProperties: 1`,
+					`                    maxProperties: 8`,
+					`                    additionalProperties:`,
+					`                      type: string`,
 				},
 			},
-		},
-	},
-	{
-		Name:  "string",
-		Value: StringTypes{},
-		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:StringTypes`,
-					`TypeRef.StringTypes:{}`,
-					`TypeRef.StringTypes:{}.String:string`,
+					`Root.{}:KeyPatternTypes`,
+					`TypeRef.KeyPatternTypes:{}`,
+					`TypeRef.KeyPatternTypes:{}.!Bad:string! ERROR:invalid keyPattern option`,
+					`TypeRef.KeyPatternTypes:{}.Extras:map{}`,
+					`TypeRef.KeyPatternTypes:{}.Extras:map{}.integer`,
+					`TypeRef.KeyPatternTypes:{}.Labels:map{}`,
+					`TypeRef.KeyPatternTypes:{}.Labels:map{}.string`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.String:string`,
+					`Root.{}.!Bad:string! ERROR:invalid keyPattern option`,
+					`Root.{}.Extras:map{}`,
+					`Root.{}.Extras:map{}.integer`,
+					`Root.{}.Labels:map{}`,
+					`Root.{}.Labels:map{}.string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface KeyPatternTypes {`,
+					`  // ERROR: invalid keyPattern option`,
+					`  bad: string;`,
+					`  extras: Record<string, number>;`,
+					`  labels: Record<string, string>;`,
+					`}`,
+				},
+				true: []string{
+					`export interface KeyPatternTypes {`,
+					`  // ERROR: invalid keyPattern option`,
+					`  bad: string;`,
+					`  extras: Record<string, number>;`,
+					`  labels: Record<string, string>;`,
+					`}`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "sql-null",
+		Value: SQLNullTypes{},
+		Want: map[string]fixtures.WantSet{
+			"markdown": map[bool][]string{
+				false: []string{
+					`# Root`,
+					`## 03-type/sql-null`,
+					`Type: struct (SQLNullTypes)`,
+					`# TypeRef`,
+					`## SQLNullTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| nullBool | boolean | no | yes |  |`,
+					`| nullFloat64 | float | no | yes |  |`,
+					`| nullInt32 | integer | no | yes |  |`,
+					`| nullInt64 | integer | no | yes |  |`,
+					`| nullString | string | no | yes |  |`,
+					`| nullTime | datetime | no | yes |  |`,
+				},
+				true: []string{
+					`# Root`,
+					`## 03-type/sql-null`,
+					`Type: struct (SQLNullTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| nullBool | boolean | no | yes |  |`,
+					`| nullFloat64 | float | no | yes |  |`,
+					`| nullInt32 | integer | no | yes |  |`,
+					`| nullInt64 | integer | no | yes |  |`,
+					`| nullString | string | no | yes |  |`,
+					`| nullTime | datetime | no | yes |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: string`,
+					`  title: sql-null`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/string:`,
+					`  /03-type/sql-null:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -773,24 +1179,44 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/StringTypes'`,
+					`                $ref: '#/components/schemas/SQLNullTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    StringTypes:`,
+					`    SQLNullTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        String:`,
+					`        nullBool:`,
+					`          nullable: true`,
+					`          type: boolean`,
+					`        nullFloat64:`,
+					`          nullable: true`,
+					`          type: number`,
+					`          format: double`,
+					`        nullInt32:`,
+					`          nullable: true`,
+					`          type: integer`,
+					`          format: int32`,
+					`        nullInt64:`,
+					`          nullable: true`,
+					`          type: integer`,
+					`          format: int64`,
+					`        nullString:`,
+					`          nullable: true`,
+					`          type: string`,
+					`        nullTime:`,
+					`          nullable: true`,
 					`          type: string`,
+					`          format: date-time`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: string`,
+					`  title: sql-null`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/string:`,
+					`  /03-type/sql-null:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -799,48 +1225,143 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/StringTypes'`,
+					`                description: 'From $ref: #/components/schemas/SQLNullTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  String:`,
+					`                  nullBool:`,
+					`                    nullable: true`,
+					`                    type: boolean`,
+					`                  nullFloat64:`,
+					`                    nullable: true`,
+					`                    type: number`,
+					`                    format: double`,
+					`                  nullInt32:`,
+					`                    nullable: true`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  nullInt64:`,
+					`                    nullable: true`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  nullString:`,
+					`                    nullable: true`,
+					`                    type: string`,
+					`                  nullTime:`,
+					`                    nullable: true`,
 					`                    type: string`,
+					`                    format: date-time`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:SQLNullTypes`,
+					`TypeRef.SQLNullTypes:{}`,
+					`TypeRef.SQLNullTypes:{}.NullBool:boolean`,
+					`TypeRef.SQLNullTypes:{}.NullFloat64:float`,
+					`TypeRef.SQLNullTypes:{}.NullInt32:integer`,
+					`TypeRef.SQLNullTypes:{}.NullInt64:integer`,
+					`TypeRef.SQLNullTypes:{}.NullString:string`,
+					`TypeRef.SQLNullTypes:{}.NullTime:datetime`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.NullBool:boolean`,
+					`Root.{}.NullFloat64:float`,
+					`Root.{}.NullInt32:integer`,
+					`Root.{}.NullInt64:integer`,
+					`Root.{}.NullString:string`,
+					`Root.{}.NullTime:datetime`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface SQLNullTypes {`,
+					`  nullBool?: boolean;`,
+					`  nullFloat64?: number;`,
+					`  nullInt32?: number;`,
+					`  nullInt64?: number;`,
+					`  nullString?: string;`,
+					`  nullTime?: string;`,
+					`}`,
+				},
+				true: []string{
+					`export interface SQLNullTypes {`,
+					`  nullBool?: boolean;`,
+					`  nullFloat64?: number;`,
+					`  nullInt32?: number;`,
+					`  nullInt64?: number;`,
+					`  nullString?: string;`,
+					`  nullTime?: string;`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "invalid",
-		Value: InvalidTypes{},
+		Name:  "union",
+		Value: ShapeTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:InvalidTypes`,
-					`TypeRef.InvalidTypes:{}`,
-					`TypeRef.InvalidTypes:{}.!Chan:invalid:chan! ERROR:kind not supported`,
-					`TypeRef.InvalidTypes:{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
-					`TypeRef.InvalidTypes:{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
-					`TypeRef.InvalidTypes:{}.!Func:invalid:func! ERROR:kind not supported`,
-					`TypeRef.InvalidTypes:{}."!UnsafePointer:invalid:unsafe.Pointer!" ERROR:kind not supported`,
+					`# Root`,
+					`## 03-type/union`,
+					`Type: struct (ShapeTypes)`,
+					`# TypeRef`,
+					`## Circle`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| radius | float | yes | no |  |`,
+					`## Shape`,
+					`Type: union`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| {?} | struct (Circle) | - | no |  |`,
+					`| {?} | struct (Square) | - | no |  |`,
+					`## ShapeTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| name | string | yes | no |  |`,
+					`| shape | union (Shape) | yes | no |  |`,
+					`| shapes | list | yes | no |  |`,
+					`| shapes[] | union (Shape) | - | no |  |`,
+					`## Square`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| side | float | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.!Chan:invalid:chan! ERROR:kind not supported`,
-					`Root.{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
-					`Root.{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
-					`Root.{}.!Func:invalid:func! ERROR:kind not supported`,
-					`Root.{}."!UnsafePointer:invalid:unsafe.Pointer!" ERROR:kind not supported`,
+					`# Root`,
+					`## 03-type/union`,
+					`Type: struct (ShapeTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| name | string | yes | no |  |`,
+					`| shape | union (Shape) | yes | no |  |`,
+					`| shape{?} | struct (Circle) | - | no |  |`,
+					`| shape{?}.radius | float | yes | no |  |`,
+					`| shape{?} | struct (Square) | - | no |  |`,
+					`| shape{?}.side | float | yes | no |  |`,
+					`| shapes | list | yes | no |  |`,
+					`| shapes[] | union (Shape) | - | no |  |`,
+					`| shapes[]{?} | struct (Circle) | - | no |  |`,
+					`| shapes[]{?}.radius | float | yes | no |  |`,
+					`| shapes[]{?} | struct (Square) | - | no |  |`,
+					`| shapes[]{?}.side | float | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: invalid`,
+					`  title: union`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/invalid:`,
+					`  /03-type/union:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -849,37 +1370,48 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/InvalidTypes'`,
+					`                $ref: '#/components/schemas/ShapeTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    InvalidTypes:`,
+					`    Circle:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Chan:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:chan'`,
-					`          type: string`,
-					`        Complex128:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
-					`          type: string`,
-					`        Complex64:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
-					`          type: string`,
-					`        Func:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:func'`,
-					`          type: string`,
-					`        UnsafePointer:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:unsafe.Pointer'`,
+					`        radius:`,
+					`          type: number`,
+					`          format: double`,
+					`    Shape:`,
+					`      oneOf:`,
+					`      - $ref: '#/components/schemas/Circle'`,
+					`      - $ref: '#/components/schemas/Square'`,
+					`    ShapeTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        name:`,
 					`          type: string`,
+					`        shape:`,
+					`          $ref: '#/components/schemas/Shape'`,
+					`        shapes:`,
+					`          type: array`,
+					`          items:`,
+					`            $ref: '#/components/schemas/Shape'`,
+					`    Square:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        side:`,
+					`          type: number`,
+					`          format: double`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: invalid`,
+					`  title: union`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/invalid:`,
+					`  /03-type/union:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -888,77 +1420,149 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/InvalidTypes'`,
+					`                description: 'From $ref: #/components/schemas/ShapeTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Chan:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:chan'`,
-					`                    type: string`,
-					`                  Complex128:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
-					`                    type: string`,
-					`                  Complex64:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
-					`                    type: string`,
-					`                  Func:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:func'`,
-					`                    type: string`,
-					`                  UnsafePointer:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:unsafe.Pointer'`,
+					`                  name:`,
 					`                    type: string`,
+					`                  shape:`,
+					`                    description: 'From $ref: #/components/schemas/Shape'`,
+					`                    oneOf:`,
+					`                    - description: 'From $ref: #/components/schemas/Circle'`,
+					`                      type: object`,
+					`                      additionalProperties: false`,
+					`                      properties:`,
+					`                        radius:`,
+					`                          type: number`,
+					`                          format: double`,
+					`                    - description: 'From $ref: #/components/schemas/Square'`,
+					`                      type: object`,
+					`                      additionalProperties: false`,
+					`                      properties:`,
+					`                        side:`,
+					`                          type: number`,
+					`                          format: double`,
+					`                  shapes:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      description: 'From $ref: #/components/schemas/Shape'`,
+					`                      oneOf:`,
+					`                      - description: 'From $ref: #/components/schemas/Circle'`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          radius:`,
+					`                            type: number`,
+					`                            format: double`,
+					`                      - description: 'From $ref: #/components/schemas/Square'`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          side:`,
+					`                            type: number`,
+					`                            format: double`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ShapeTypes`,
+					`TypeRef.Circle:{}`,
+					`TypeRef.Circle:{}.Radius:float`,
+					`TypeRef.Shape:union`,
+					`TypeRef.Shape:union.{}:Circle`,
+					`TypeRef.Shape:union.{}:Square`,
+					`TypeRef.ShapeTypes:{}`,
+					`TypeRef.ShapeTypes:{}.Name:string`,
+					`TypeRef.ShapeTypes:{}.Shape:union:Shape`,
+					`TypeRef.ShapeTypes:{}.Shapes:[]`,
+					`TypeRef.ShapeTypes:{}.Shapes:[].union:Shape`,
+					`TypeRef.Square:{}`,
+					`TypeRef.Square:{}.Side:float`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Name:string`,
+					`Root.{}.Shape:union`,
+					`Root.{}.Shape:union.{}`,
+					`Root.{}.Shape:union.{}.Radius:float`,
+					`Root.{}.Shape:union.{}`,
+					`Root.{}.Shape:union.{}.Side:float`,
+					`Root.{}.Shapes:[]`,
+					`Root.{}.Shapes:[].union`,
+					`Root.{}.Shapes:[].union.{}`,
+					`Root.{}.Shapes:[].union.{}.Radius:float`,
+					`Root.{}.Shapes:[].union.{}`,
+					`Root.{}.Shapes:[].union.{}.Side:float`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface Circle {`,
+					`  radius: number;`,
+					`}`,
+					`export type Shape = Circle | Square;`,
+					`export interface ShapeTypes {`,
+					`  name: string;`,
+					`  shape: Shape;`,
+					`  shapes: Shape[];`,
+					`}`,
+					`export interface Square {`,
+					`  side: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface ShapeTypes {`,
+					`  name: string;`,
+					`  shape: { radius: number; } | { side: number; };`,
+					`  shapes: ({ radius: number; } | { side: number; })[];`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "compound",
-		Value: CompoundTypes{},
+		Name:  "bytes",
+		Value: ByteTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:CompoundTypes`,
-					`TypeRef.CompoundTypes:{}`,
-					`TypeRef.CompoundTypes:{}.Array0:[]`,
-					`TypeRef.CompoundTypes:{}.Array0:[].string`,
-					`TypeRef.CompoundTypes:{}.Array3:[]`,
-					`TypeRef.CompoundTypes:{}.Array3:[].string`,
-					`TypeRef.CompoundTypes:{}.!Interface:invalid! ERROR:interface element is nil`,
-					`TypeRef.CompoundTypes:{}.!Map:map{}! ERROR:map key type must be string`,
-					`TypeRef.CompoundTypes:{}.PrivatePtr:{}:PrivateStruct`,
-					`TypeRef.CompoundTypes:{}.Ptr:{}:StringStruct`,
-					`TypeRef.CompoundTypes:{}.Slice:[]`,
-					`TypeRef.CompoundTypes:{}.Slice:[].!invalid! ERROR:interface element is nil`,
-					`TypeRef.CompoundTypes:{}.!Struct:{}! ERROR:empty struct not supported`,
-					`TypeRef.!PrivateStruct:{}! ERROR:struct has no exported fields`,
-					`TypeRef.StringStruct:{}`,
-					`TypeRef.StringStruct:{}.Value:string`,
+					`# Root`,
+					`## 03-type/bytes`,
+					`Type: struct (ByteTypes)`,
+					`# TypeRef`,
+					`## ByteTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| array | list | yes | no |  |`,
+					`| array[] | integer | - | no |  |`,
+					`| data | string | yes | no |  |`,
+					`| dataPtr | string | no | yes |  |`,
+					`| raw | any | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Array0:[]`,
-					`Root.{}.Array0:[].string`,
-					`Root.{}.Array3:[]`,
-					`Root.{}.Array3:[].string`,
-					`Root.{}.!Interface:invalid! ERROR:interface element is nil`,
-					`Root.{}.!Map:map{}! ERROR:map key type must be string`,
-					`Root.{}.!PrivatePtr:{}! ERROR:struct has no exported fields`,
-					`Root.{}.Ptr:{}`,
-					`Root.{}.Ptr:{}.Value:string`,
-					`Root.{}.Slice:[]`,
-					`Root.{}.Slice:[].!invalid! ERROR:interface element is nil`,
-					`Root.{}.!Struct:{}! ERROR:empty struct not supported`,
+					`# Root`,
+					`## 03-type/bytes`,
+					`Type: struct (ByteTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| array | list | yes | no |  |`,
+					`| array[] | integer | - | no |  |`,
+					`| data | string | yes | no |  |`,
+					`| dataPtr | string | no | yes |  |`,
+					`| raw | any | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: compound`,
+					`  title: bytes`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/compound:`,
+					`  /03-type/bytes:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -967,60 +1571,37 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/CompoundTypes'`,
+					`                $ref: '#/components/schemas/ByteTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    CompoundTypes:`,
+					`    ByteTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Array0:`,
-					`          type: array`,
-					`          items:`,
-					`            type: string`,
-					`        Array3:`,
+					`        array:`,
 					`          type: array`,
+					`          minItems: 4`,
+					`          maxItems: 4`,
 					`          items:`,
-					`            type: string`,
-					`        Interface:`,
-					`          description: 'ERROR=interface element is nil'`,
+					`            type: integer`,
+					`            format: int32`,
+					`        data:`,
 					`          type: string`,
-					`        Map:`,
-					`          description: 'ERROR=map key type must be string'`,
-					`          type: object`,
-					`          additionalProperties: false`,
-					`        PrivatePtr:`,
-					`          $ref: '#/components/schemas/PrivateStruct'`,
-					`        Ptr:`,
-					`          $ref: '#/components/schemas/StringStruct'`,
-					`        Slice:`,
-					`          type: array`,
-					`          items:`,
-					`            description: 'ERROR=interface element is nil'`,
-					`            type: string`,
-					`        Struct:`,
-					`          description: 'ERROR=empty struct not supported'`,
-					`          type: object`,
-					`          additionalProperties: false`,
-					`    PrivateStruct:`,
-					`      description: 'ERROR=struct has no exported fields'`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`    StringStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        Value:`,
+					`          format: byte`,
+					`        dataPtr:`,
+					`          nullable: true`,
 					`          type: string`,
+					`          format: byte`,
+					`        raw: {}`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: compound`,
+					`  title: bytes`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/compound:`,
+					`  /03-type/bytes:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1029,73 +1610,124 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/CompoundTypes'`,
+					`                description: 'From $ref: #/components/schemas/ByteTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Array0:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
-					`                  Array3:`,
+					`                  array:`,
 					`                    type: array`,
+					`                    minItems: 4`,
+					`                    maxItems: 4`,
 					`                    items:`,
-					`                      type: string`,
-					`                  Interface:`,
-					`                    description: 'ERROR=interface element is nil'`,
+					`                      type: integer`,
+					`                      format: int32`,
+					`                  data:`,
 					`                    type: string`,
-					`                  Map:`,
-					`                    description: 'ERROR=map key type must be string'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                  PrivatePtr:`,
-					`                    description: 'From $ref: #/components/schemas/PrivateStruct;ERROR=struct has no exported fields'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                  Ptr:`,
-					`                    description: 'From $ref: #/components/schemas/StringStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      Value:`,
-					`                        type: string`,
-					`                  Slice:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      description: 'ERROR=interface element is nil'`,
-					`                      type: string`,
-					`                  Struct:`,
-					`                    description: 'ERROR=empty struct not supported'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
+					`                    format: byte`,
+					`                  dataPtr:`,
+					`                    nullable: true`,
+					`                    type: string`,
+					`                    format: byte`,
+					`                  raw: {}`,
 				},
 			},
-		},
-	},
-	{
-		Name:  "special",
-		Value: SpecialTypes{},
-		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:SpecialTypes`,
-					`TypeRef.SpecialTypes:{}`,
-					`TypeRef.SpecialTypes:{}.DateTime:datetime`,
+					`Root.{}:ByteTypes`,
+					`TypeRef.ByteTypes:{}`,
+					`TypeRef.ByteTypes:{}.Array:[]`,
+					`TypeRef.ByteTypes:{}.Array:[].integer`,
+					`TypeRef.ByteTypes:{}.Data:string`,
+					`TypeRef.ByteTypes:{}.DataPtr:string`,
+					`TypeRef.ByteTypes:{}.Raw:any`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.DateTime:datetime`,
+					`Root.{}.Array:[]`,
+					`Root.{}.Array:[].integer`,
+					`Root.{}.Data:string`,
+					`Root.{}.DataPtr:string`,
+					`Root.{}.Raw:any`,
 				},
 			},
-			"openapi": map[bool][]string{
+			"typescript": map[bool][]string{
 				false: []string{
-					`openapi: 3.0.0`,
+					`export interface ByteTypes {`,
+					`  array: number[];`,
+					`  data: string;`,
+					`  dataPtr?: string;`,
+					`  raw: unknown;`,
+					`}`,
+				},
+				true: []string{
+					`export interface ByteTypes {`,
+					`  array: number[];`,
+					`  data: string;`,
+					`  dataPtr?: string;`,
+					`  raw: unknown;`,
+					`}`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "inline",
+		Value: InlineTypes{},
+		Want: map[string]fixtures.WantSet{
+			"markdown": map[bool][]string{
+				false: []string{
+					`# Root`,
+					`## 03-type/inline`,
+					`Type: struct (InlineTypes)`,
+					`# TypeRef`,
+					`## InlineExtraTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| extras | map | yes | no |  |`,
+					`| extras{*} | invalid | - | no | ERROR: interface element is nil |`,
+					`| id | integer | yes | no |  |`,
+					`| name | string | no | no |  |`,
+					`## InlineTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| extras | struct (InlineExtraTypes) | yes | no |  |`,
+					`| values | struct (InlineValueTypes) | yes | no |  |`,
+					`## InlineValueTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| kind | string | yes | no |  |`,
+					`| labels | map | yes | no |  |`,
+					`| labels{*} | string | - | no |  |`,
+				},
+				true: []string{
+					`# Root`,
+					`## 03-type/inline`,
+					`Type: struct (InlineTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| extras | struct (InlineExtraTypes) | yes | no |  |`,
+					`| extras.extras | map | yes | no |  |`,
+					`| extras.extras{*} | invalid | - | no | ERROR: interface element is nil |`,
+					`| extras.id | integer | yes | no |  |`,
+					`| extras.name | string | no | no |  |`,
+					`| values | struct (InlineValueTypes) | yes | no |  |`,
+					`| values.kind | string | yes | no |  |`,
+					`| values.labels | map | yes | no |  |`,
+					`| values.labels{*} | string | - | no |  |`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
 					`info:`,
-					`  title: special`,
+					`  title: inline`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/special:`,
+					`  /03-type/inline:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1104,25 +1736,42 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/SpecialTypes'`,
+					`                $ref: '#/components/schemas/InlineTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    SpecialTypes:`,
+					`    InlineExtraTypes:`,
+					`      type: object`,
+					`      additionalProperties: true`,
+					`      properties:`,
+					`        id:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        name:`,
+					`          type: string`,
+					`    InlineTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        DateTime:`,
+					`        extras:`,
+					`          $ref: '#/components/schemas/InlineExtraTypes'`,
+					`        values:`,
+					`          $ref: '#/components/schemas/InlineValueTypes'`,
+					`    InlineValueTypes:`,
+					`      type: object`,
+					`      additionalProperties:`,
+					`        type: string`,
+					`      properties:`,
+					`        kind:`,
 					`          type: string`,
-					`          format: date-time`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: special`,
+					`  title: inline`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/special:`,
+					`  /03-type/inline:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1131,123 +1780,135 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/SpecialTypes'`,
+					`                description: 'From $ref: #/components/schemas/InlineTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  DateTime:`,
-					`                    type: string`,
-					`                    format: date-time`,
+					`                  extras:`,
+					`                    description: 'From $ref: #/components/schemas/InlineExtraTypes'`,
+					`                    type: object`,
+					`                    additionalProperties: true`,
+					`                    properties:`,
+					`                      id:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      name:`,
+					`                        type: string`,
+					`                  values:`,
+					`                    description: 'From $ref: #/components/schemas/InlineValueTypes'`,
+					`                    type: object`,
+					`                    additionalProperties:`,
+					`                      type: string`,
+					`                    properties:`,
+					`                      kind:`,
+					`                        type: string`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:InlineTypes`,
+					`TypeRef.InlineExtraTypes:{}`,
+					`TypeRef.InlineExtraTypes:{}.Extras:map{}`,
+					`TypeRef.InlineExtraTypes:{}.Extras:map{}.!invalid! ERROR:interface element is nil`,
+					`TypeRef.InlineExtraTypes:{}.ID:integer`,
+					`TypeRef.InlineExtraTypes:{}.Name:string`,
+					`TypeRef.InlineTypes:{}`,
+					`TypeRef.InlineTypes:{}.Extras:{}:InlineExtraTypes`,
+					`TypeRef.InlineTypes:{}.Values:{}:InlineValueTypes`,
+					`TypeRef.InlineValueTypes:{}`,
+					`TypeRef.InlineValueTypes:{}.Kind:string`,
+					`TypeRef.InlineValueTypes:{}.Labels:map{}`,
+					`TypeRef.InlineValueTypes:{}.Labels:map{}.string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Extras:{}`,
+					`Root.{}.Extras:{}.Extras:map{}`,
+					`Root.{}.Extras:{}.Extras:map{}.!invalid! ERROR:interface element is nil`,
+					`Root.{}.Extras:{}.ID:integer`,
+					`Root.{}.Extras:{}.Name:string`,
+					`Root.{}.Values:{}`,
+					`Root.{}.Values:{}.Kind:string`,
+					`Root.{}.Values:{}.Labels:map{}`,
+					`Root.{}.Values:{}.Labels:map{}.string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface InlineExtraTypes {`,
+					`  // ERROR: interface element is nil`,
+					`  extras: Record<string, unknown>;`,
+					`  id: number;`,
+					`  name?: string;`,
+					`}`,
+					`export interface InlineTypes {`,
+					`  extras: InlineExtraTypes;`,
+					`  values: InlineValueTypes;`,
+					`}`,
+					`export interface InlineValueTypes {`,
+					`  kind: string;`,
+					`  labels: Record<string, string>;`,
+					`}`,
+				},
+				true: []string{
+					`export interface InlineTypes {`,
+					`  extras: {`,
+					`    // ERROR: interface element is nil`,
+					`    extras: Record<string, unknown>;`,
+					`    id: number;`,
+					`    name?: string;`,
+					`  };`,
+					`  values: {`,
+					`    kind: string;`,
+					`    labels: Record<string, string>;`,
+					`  };`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "redefined",
-		Value: RedefineStruct{},
+		Name:  "string-encoded",
+		Value: StringEncodedTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:RedefineStruct`,
-					`TypeRef.MyArray0:[]`,
-					`TypeRef.MyArray0:[].string`,
-					`TypeRef.MyArray3:[]`,
-					`TypeRef.MyArray3:[].string`,
-					`TypeRef.MyBool:boolean`,
-					`TypeRef.MyDateTime:datetime`,
-					`TypeRef.MyFloat32:float`,
-					`TypeRef.MyFloat64:float`,
-					`TypeRef.MyInt:integer`,
-					`TypeRef.MyInt16:integer`,
-					`TypeRef.MyInt32:integer`,
-					`TypeRef.MyInt64:integer`,
-					`TypeRef.MyInt8:integer`,
-					`TypeRef.!MyInterface:invalid! ERROR:interface element is nil`,
-					`TypeRef.!MyMap:map{}! ERROR:map key type must be string`,
-					`TypeRef.MySlice:[]`,
-					`TypeRef.MySlice:[].!invalid! ERROR:interface element is nil`,
-					`TypeRef.MyString:string`,
-					`TypeRef.!MyStruct:{}! ERROR:empty struct not supported`,
-					`TypeRef.MyUint:integer`,
-					`TypeRef.MyUint16:integer`,
-					`TypeRef.MyUint32:integer`,
-					`TypeRef.MyUint64:integer`,
-					`TypeRef.MyUint8:integer`,
-					`TypeRef.MyUintptr:integer`,
-					`TypeRef.!PrivateStruct:{}! ERROR:struct has no exported fields`,
-					`TypeRef.RedefineStruct:{}`,
-					`TypeRef.RedefineStruct:{}.Array0:[]:MyArray0`,
-					`TypeRef.RedefineStruct:{}.Array3:[]:MyArray3`,
-					`TypeRef.RedefineStruct:{}.Bool:boolean:MyBool`,
-					`TypeRef.RedefineStruct:{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
-					`TypeRef.RedefineStruct:{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
-					`TypeRef.RedefineStruct:{}.DateTime:datetime:MyDateTime`,
-					`TypeRef.RedefineStruct:{}.Float32:float:MyFloat32`,
-					`TypeRef.RedefineStruct:{}.Float64:float:MyFloat64`,
-					`TypeRef.RedefineStruct:{}.Int:integer:MyInt`,
-					`TypeRef.RedefineStruct:{}.Int16:integer:MyInt16`,
-					`TypeRef.RedefineStruct:{}.Int32:integer:MyInt32`,
-					`TypeRef.RedefineStruct:{}.Int64:integer:MyInt64`,
-					`TypeRef.RedefineStruct:{}.Int8:integer:MyInt8`,
-					`TypeRef.RedefineStruct:{}.Interface:invalid:MyInterface`,
-					`TypeRef.RedefineStruct:{}.Map:map{}:MyMap`,
-					`TypeRef.RedefineStruct:{}.PrivatePtr:{}:PrivateStruct`,
-					`TypeRef.RedefineStruct:{}.Ptr:{}:StringStruct`,
-					`TypeRef.RedefineStruct:{}.Slice:[]:MySlice`,
-					`TypeRef.RedefineStruct:{}.String:string:MyString`,
-					`TypeRef.RedefineStruct:{}.Struct:{}:MyStruct`,
-					`TypeRef.RedefineStruct:{}.Uint:integer:MyUint`,
-					`TypeRef.RedefineStruct:{}.Uint16:integer:MyUint16`,
-					`TypeRef.RedefineStruct:{}.Uint32:integer:MyUint32`,
-					`TypeRef.RedefineStruct:{}.Uint64:integer:MyUint64`,
-					`TypeRef.RedefineStruct:{}.Uint8:integer:MyUint8`,
-					`TypeRef.RedefineStruct:{}.Uintptr:integer:MyUintptr`,
-					`TypeRef.StringStruct:{}`,
-					`TypeRef.StringStruct:{}.Value:string`,
+					`# Root`,
+					`## 03-type/string-encoded`,
+					`Type: struct (StringEncodedTypes)`,
+					`# TypeRef`,
+					`## StringEncodedTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| active | boolean | yes | no |  |`,
+					`| count | integer | no | no |  |`,
+					`| id | integer | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Array0:[]`,
-					`Root.{}.Array0:[].string`,
-					`Root.{}.Array3:[]`,
-					`Root.{}.Array3:[].string`,
-					`Root.{}.Bool:boolean`,
-					`Root.{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
-					`Root.{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
-					`Root.{}.DateTime:datetime`,
-					`Root.{}.Float32:float`,
-					`Root.{}.Float64:float`,
-					`Root.{}.Int:integer`,
-					`Root.{}.Int16:integer`,
-					`Root.{}.Int32:integer`,
-					`Root.{}.Int64:integer`,
-					`Root.{}.Int8:integer`,
-					`Root.{}.!Interface:invalid! ERROR:interface element is nil`,
-					`Root.{}.!Map:map{}! ERROR:map key type must be string`,
-					`Root.{}.!PrivatePtr:{}! ERROR:struct has no exported fields`,
-					`Root.{}.Ptr:{}`,
-					`Root.{}.Ptr:{}.Value:string`,
-					`Root.{}.Slice:[]`,
-					`Root.{}.Slice:[].!invalid! ERROR:interface element is nil`,
-					`Root.{}.String:string`,
-					`Root.{}.!Struct:{}! ERROR:empty struct not supported`,
-					`Root.{}.Uint:integer`,
-					`Root.{}.Uint16:integer`,
-					`Root.{}.Uint32:integer`,
-					`Root.{}.Uint64:integer`,
-					`Root.{}.Uint8:integer`,
-					`Root.{}.Uintptr:integer`,
+					`# Root`,
+					`## 03-type/string-encoded`,
+					`Type: struct (StringEncodedTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| active | boolean | yes | no |  |`,
+					`| count | integer | no | no |  |`,
+					`| id | integer | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: redefined`,
+					`  title: string-encoded`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/redefined:`,
+					`  /03-type/string-encoded:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1256,146 +1917,35 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/RedefineStruct'`,
+					`                $ref: '#/components/schemas/StringEncodedTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    MyArray0:`,
-					`      type: array`,
-					`      items:`,
-					`        type: string`,
-					`    MyArray3:`,
-					`      type: array`,
-					`      items:`,
-					`        type: string`,
-					`    MyBool:`,
-					`      type: boolean`,
-					`    MyDateTime:`,
-					`      type: string`,
-					`      format: date-time`,
-					`    MyFloat32:`,
-					`      type: number`,
-					`    MyFloat64:`,
-					`      type: number`,
-					`      format: double`,
-					`    MyInt:`,
-					`      type: integer`,
-					`    MyInt16:`,
-					`      type: integer`,
-					`    MyInt32:`,
-					`      type: integer`,
-					`    MyInt64:`,
-					`      type: integer`,
-					`      format: int64`,
-					`    MyInt8:`,
-					`      type: integer`,
-					`    MyInterface:`,
-					`      description: 'ERROR=interface element is nil'`,
-					`      type: string`,
-					`    MyMap:`,
-					`      description: 'ERROR=map key type must be string'`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`    MySlice:`,
-					`      type: array`,
-					`      items:`,
-					`        description: 'ERROR=interface element is nil'`,
-					`        type: string`,
-					`    MyString:`,
-					`      type: string`,
-					`    MyStruct:`,
-					`      description: 'ERROR=empty struct not supported'`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`    MyUint:`,
-					`      type: integer`,
-					`    MyUint16:`,
-					`      type: integer`,
-					`    MyUint32:`,
-					`      type: integer`,
-					`    MyUint64:`,
-					`      type: integer`,
-					`      format: int64`,
-					`    MyUint8:`,
-					`      type: integer`,
-					`    MyUintptr:`,
-					`      type: integer`,
-					`    PrivateStruct:`,
-					`      description: 'ERROR=struct has no exported fields'`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`    RedefineStruct:`,
+					`    StringEncodedTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Array0:`,
-					`          $ref: '#/components/schemas/MyArray0'`,
-					`        Array3:`,
-					`          $ref: '#/components/schemas/MyArray3'`,
-					`        Bool:`,
-					`          $ref: '#/components/schemas/MyBool'`,
-					`        Complex128:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`        active:`,
 					`          type: string`,
-					`        Complex64:`,
-					`          description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`        count:`,
 					`          type: string`,
-					`        DateTime:`,
-					`          $ref: '#/components/schemas/MyDateTime'`,
-					`        Float32:`,
-					`          $ref: '#/components/schemas/MyFloat32'`,
-					`        Float64:`,
-					`          $ref: '#/components/schemas/MyFloat64'`,
-					`        Int:`,
-					`          $ref: '#/components/schemas/MyInt'`,
-					`        Int16:`,
-					`          $ref: '#/components/schemas/MyInt16'`,
-					`        Int32:`,
-					`          $ref: '#/components/schemas/MyInt32'`,
-					`        Int64:`,
-					`          $ref: '#/components/schemas/MyInt64'`,
-					`        Int8:`,
-					`          $ref: '#/components/schemas/MyInt8'`,
-					`        Interface:`,
-					`          $ref: '#/components/schemas/MyInterface'`,
-					`        Map:`,
-					`          $ref: '#/components/schemas/MyMap'`,
-					`        PrivatePtr:`,
-					`          $ref: '#/components/schemas/PrivateStruct'`,
-					`        Ptr:`,
-					`          $ref: '#/components/schemas/StringStruct'`,
-					`        Slice:`,
-					`          $ref: '#/components/schemas/MySlice'`,
-					`        String:`,
-					`          $ref: '#/components/schemas/MyString'`,
-					`        Struct:`,
-					`          $ref: '#/components/schemas/MyStruct'`,
-					`        Uint:`,
-					`          $ref: '#/components/schemas/MyUint'`,
-					`        Uint16:`,
-					`          $ref: '#/components/schemas/MyUint16'`,
-					`        Uint32:`,
-					`          $ref: '#/components/schemas/MyUint32'`,
-					`        Uint64:`,
-					`          $ref: '#/components/schemas/MyUint64'`,
-					`        Uint8:`,
-					`          $ref: '#/components/schemas/MyUint8'`,
-					`        Uintptr:`,
-					`          $ref: '#/components/schemas/MyUintptr'`,
-					`    StringStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        Value:`,
+					`          format: int64`,
+					`        id:`,
 					`          type: string`,
+					`          format: int64`,
+					`        name:`,
+					`          type: string`,
+					`        ratio:`,
+					`          type: string`,
+					`          format: double`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: redefined`,
+					`  title: string-encoded`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /03-type/redefined:`,
+					`  /03-type/string-encoded:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1404,172 +1954,106 @@ var typeTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/RedefineStruct'`,
+					`                description: 'From $ref: #/components/schemas/StringEncodedTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Array0:`,
-					`                    description: 'From $ref: #/components/schemas/MyArray0'`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
-					`                  Array3:`,
-					`                    description: 'From $ref: #/components/schemas/MyArray3'`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
-					`                  Bool:`,
-					`                    description: 'From $ref: #/components/schemas/MyBool'`,
-					`                    type: boolean`,
-					`                  Complex128:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`                  active:`,
 					`                    type: string`,
-					`                  Complex64:`,
-					`                    description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`                  count:`,
 					`                    type: string`,
-					`                  DateTime:`,
-					`                    description: 'From $ref: #/components/schemas/MyDateTime'`,
+					`                    format: int64`,
+					`                  id:`,
 					`                    type: string`,
-					`                    format: date-time`,
-					`                  Float32:`,
-					`                    description: 'From $ref: #/components/schemas/MyFloat32'`,
-					`                    type: number`,
-					`                  Float64:`,
-					`                    description: 'From $ref: #/components/schemas/MyFloat64'`,
-					`                    type: number`,
-					`                    format: double`,
-					`                  Int:`,
-					`                    description: 'From $ref: #/components/schemas/MyInt'`,
-					`                    type: integer`,
-					`                  Int16:`,
-					`                    description: 'From $ref: #/components/schemas/MyInt16'`,
-					`                    type: integer`,
-					`                  Int32:`,
-					`                    description: 'From $ref: #/components/schemas/MyInt32'`,
-					`                    type: integer`,
-					`                  Int64:`,
-					`                    description: 'From $ref: #/components/schemas/MyInt64'`,
-					`                    type: integer`,
 					`                    format: int64`,
-					`                  Int8:`,
-					`                    description: 'From $ref: #/components/schemas/MyInt8'`,
-					`                    type: integer`,
-					`                  Interface:`,
-					`                    description: 'From $ref: #/components/schemas/MyInterface;ERROR=interface element is nil'`,
+					`                  name:`,
 					`                    type: string`,
-					`                  Map:`,
-					`                    description: 'From $ref: #/components/schemas/MyMap;ERROR=map key type must be string'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                  PrivatePtr:`,
-					`                    description: 'From $ref: #/components/schemas/PrivateStruct;ERROR=struct has no exported fields'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                  Ptr:`,
-					`                    description: 'From $ref: #/components/schemas/StringStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      Value:`,
-					`                        type: string`,
-					`                  Slice:`,
-					`                    description: 'From $ref: #/components/schemas/MySlice'`,
-					`                    type: array`,
-					`                    items:`,
-					`                      description: 'ERROR=interface element is nil'`,
-					`                      type: string`,
-					`                  String:`,
-					`                    description: 'From $ref: #/components/schemas/MyString'`,
+					`                  ratio:`,
 					`                    type: string`,
-					`                  Struct:`,
-					`                    description: 'From $ref: #/components/schemas/MyStruct;ERROR=empty struct not supported'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                  Uint:`,
-					`                    description: 'From $ref: #/components/schemas/MyUint'`,
-					`                    type: integer`,
-					`                  Uint16:`,
-					`                    description: 'From $ref: #/components/schemas/MyUint16'`,
-					`                    type: integer`,
-					`                  Uint32:`,
-					`                    description: 'From $ref: #/components/schemas/MyUint32'`,
-					`                    type: integer`,
-					`                  Uint64:`,
-					`                    description: 'From $ref: #/components/schemas/MyUint64'`,
-					`                    type: integer`,
-					`                    format: int64`,
-					`                  Uint8:`,
-					`                    description: 'From $ref: #/components/schemas/MyUint8'`,
-					`                    type: integer`,
-					`                  Uintptr:`,
-					`                    description: 'From $ref: #/components/schemas/MyUintptr'`,
-					`                    type: integer`,
+					`                    format: double`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:StringEncodedTypes`,
+					`TypeRef.StringEncodedTypes:{}`,
+					`TypeRef.StringEncodedTypes:{}.Active:boolean`,
+					`TypeRef.StringEncodedTypes:{}.Count:integer`,
+					`TypeRef.StringEncodedTypes:{}.ID:integer`,
+					`TypeRef.StringEncodedTypes:{}.Name:string`,
+					`TypeRef.StringEncodedTypes:{}.Ratio:float`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Active:boolean`,
+					`Root.{}.Count:integer`,
+					`Root.{}.ID:integer`,
+					`Root.{}.Name:string`,
+					`Root.{}.Ratio:float`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface StringEncodedTypes {`,
+					`  active: boolean;`,
+					`  count?: number;`,
+					`  id: number;`,
+					`  name: string;`,
+					`  ratio: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface StringEncodedTypes {`,
+					`  active: boolean;`,
+					`  count?: number;`,
+					`  id: number;`,
+					`  name: string;`,
+					`  ratio: number;`,
+					`}`,
 				},
 			},
 		},
 	},
-}
-
-type ArrayStruct struct {
-	Array0   [0]string
-	Array3   [3]string
-	Array2_3 [2][3]string
-}
-
-type SliceStruct struct {
-	Slice  []string
-	Array2 [][]string
-}
-
-var jsonArrayTest = `
-{
-	"Array0": [],
-	"Array3": ["a","b","c"],
-	"Array2_3": [
-		[1,2,3],
-		[2,3,4]
-	]
-}
-`
-
-// Array tests.
-var listTests = []fixtures.TestCase{
 	{
-		Name:  "arrays",
-		Value: &ArrayStruct{},
+		Name:  "constraints",
+		Value: ConstraintTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:ArrayStruct`,
-					`TypeRef.ArrayStruct:{}`,
-					`TypeRef.ArrayStruct:{}.Array0:[]`,
-					`TypeRef.ArrayStruct:{}.Array0:[].string`,
-					`TypeRef.ArrayStruct:{}.Array2_3:[]`,
-					`TypeRef.ArrayStruct:{}.Array2_3:[].[]`,
-					`TypeRef.ArrayStruct:{}.Array2_3:[].[].string`,
-					`TypeRef.ArrayStruct:{}.Array3:[]`,
-					`TypeRef.ArrayStruct:{}.Array3:[].string`,
+					`# Root`,
+					`## 03-type/constraints`,
+					`Type: struct (ConstraintTypes)`,
+					`# TypeRef`,
+					`## ConstraintTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| invalid | string | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| percent | integer | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Array0:[]`,
-					`Root.{}.Array0:[].string`,
-					`Root.{}.Array2_3:[]`,
-					`Root.{}.Array2_3:[].[]`,
-					`Root.{}.Array2_3:[].[].string`,
-					`Root.{}.Array3:[]`,
-					`Root.{}.Array3:[].string`,
+					`# Root`,
+					`## 03-type/constraints`,
+					`Type: struct (ConstraintTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| invalid | string | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| percent | integer | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: arrays`,
+					`  title: constraints`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/arrays:`,
+					`  /03-type/constraints:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1578,36 +2062,37 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/ArrayStruct'`,
+					`                $ref: '#/components/schemas/ConstraintTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    ArrayStruct:`,
+					`    ConstraintTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Array0:`,
-					`          type: array`,
-					`          items:`,
-					`            type: string`,
-					`        Array2_3:`,
-					`          type: array`,
-					`          items:`,
-					`            type: array`,
-					`            items:`,
-					`              type: string`,
-					`        Array3:`,
-					`          type: array`,
-					`          items:`,
-					`            type: string`,
+					`        invalid:`,
+					`          type: string`,
+					`        name:`,
+					`          type: string`,
+					`          minLength: 1`,
+					`          maxLength: 255`,
+					`        percent:`,
+					`          type: integer`,
+					`          format: int64`,
+					`          minimum: 0`,
+					`          maximum: 100`,
+					`        ratio:`,
+					`          type: number`,
+					`          format: double`,
+					`          minimum: -1.5`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: arrays`,
+					`  title: constraints`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/arrays:`,
+					`  /03-type/constraints:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1616,63 +2101,108 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/ArrayStruct'`,
+					`                description: 'From $ref: #/components/schemas/ConstraintTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Array0:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
-					`                  Array2_3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: array`,
-					`                      items:`,
-					`                        type: string`,
-					`                  Array3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
+					`                  invalid:`,
+					`                    type: string`,
+					`                  name:`,
+					`                    type: string`,
+					`                    minLength: 1`,
+					`                    maxLength: 255`,
+					`                  percent:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                    minimum: 0`,
+					`                    maximum: 100`,
+					`                  ratio:`,
+					`                    type: number`,
+					`                    format: double`,
+					`                    minimum: -1.5`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ConstraintTypes`,
+					`TypeRef.ConstraintTypes:{}`,
+					`TypeRef.ConstraintTypes:{}.Invalid:string`,
+					`TypeRef.ConstraintTypes:{}.Name:string`,
+					`TypeRef.ConstraintTypes:{}.Percent:integer`,
+					`TypeRef.ConstraintTypes:{}.Ratio:float`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Invalid:string`,
+					`Root.{}.Name:string`,
+					`Root.{}.Percent:integer`,
+					`Root.{}.Ratio:float`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface ConstraintTypes {`,
+					`  invalid: string;`,
+					`  name: string;`,
+					`  percent: number;`,
+					`  ratio: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface ConstraintTypes {`,
+					`  invalid: string;`,
+					`  name: string;`,
+					`  percent: number;`,
+					`  ratio: number;`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "json-array",
-		Value: fromJSON([]byte(jsonArrayTest)),
+		Name:  "examples",
+		Value: ExampleTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}`,
-					`Root.{}.Array0:[]`,
-					`Root.{}.Array0:[].!invalid! ERROR:interface element is nil`,
-					`Root.{}.Array2_3:[]`,
-					`Root.{}.Array2_3:[].[]`,
-					`Root.{}.Array2_3:[].[].float`,
-					`Root.{}.Array3:[]`,
-					`Root.{}.Array3:[].string`,
+					`# Root`,
+					`## 03-type/examples`,
+					`Type: struct (ExampleTypes)`,
+					`# TypeRef`,
+					`## ExampleTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| active | boolean | yes | no |  |`,
+					`| code | string | yes | no |  |`,
+					`| count | integer | yes | no |  |`,
+					`| created | datetime | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Array0:[]`,
-					`Root.{}.Array0:[].!invalid! ERROR:interface element is nil`,
-					`Root.{}.Array2_3:[]`,
-					`Root.{}.Array2_3:[].[]`,
-					`Root.{}.Array2_3:[].[].float`,
-					`Root.{}.Array3:[]`,
-					`Root.{}.Array3:[].string`,
+					`# Root`,
+					`## 03-type/examples`,
+					`Type: struct (ExampleTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| active | boolean | yes | no |  |`,
+					`| code | string | yes | no |  |`,
+					`| count | integer | yes | no |  |`,
+					`| created | datetime | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| ratio | float | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-array`,
+					`  title: examples`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/json-array:`,
+					`  /03-type/examples:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1681,34 +2211,43 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                type: object`,
-					`                additionalProperties: false`,
-					`                properties:`,
-					`                  Array0:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      description: 'ERROR=interface element is nil'`,
-					`                      type: string`,
-					`                  Array2_3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: array`,
-					`                      items:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                  Array3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
+					`                $ref: '#/components/schemas/ExampleTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    ExampleTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        active:`,
+					`          type: boolean`,
+					`          example: true`,
+					`        code:`,
+					`          type: string`,
+					`          example: '42'`,
+					`        count:`,
+					`          type: integer`,
+					`          format: int64`,
+					`          example: 42`,
+					`        created:`,
+					`          type: string`,
+					`          format: date-time`,
+					`          example: '2021-01-02T03:04:05Z'`,
+					`        name:`,
+					`          type: string`,
+					`          example: 'Jane Doe'`,
+					`        ratio:`,
+					`          type: number`,
+					`          format: double`,
+					`          example: 0.5`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-array`,
+					`  title: examples`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/json-array:`,
+					`  /03-type/examples:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1717,61 +2256,130 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/ExampleTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Array0:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      description: 'ERROR=interface element is nil'`,
-					`                      type: string`,
-					`                  Array2_3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: array`,
-					`                      items:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                  Array3:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
+					`                  active:`,
+					`                    type: boolean`,
+					`                    example: true`,
+					`                  code:`,
+					`                    type: string`,
+					`                    example: '42'`,
+					`                  count:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                    example: 42`,
+					`                  created:`,
+					`                    type: string`,
+					`                    format: date-time`,
+					`                    example: '2021-01-02T03:04:05Z'`,
+					`                  name:`,
+					`                    type: string`,
+					`                    example: 'Jane Doe'`,
+					`                  ratio:`,
+					`                    type: number`,
+					`                    format: double`,
+					`                    example: 0.5`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ExampleTypes`,
+					`TypeRef.ExampleTypes:{}`,
+					`TypeRef.ExampleTypes:{}.Active:boolean`,
+					`TypeRef.ExampleTypes:{}.Code:string`,
+					`TypeRef.ExampleTypes:{}.Count:integer`,
+					`TypeRef.ExampleTypes:{}.Created:datetime`,
+					`TypeRef.ExampleTypes:{}.Name:string`,
+					`TypeRef.ExampleTypes:{}.Ratio:float`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Active:boolean`,
+					`Root.{}.Code:string`,
+					`Root.{}.Count:integer`,
+					`Root.{}.Created:datetime`,
+					`Root.{}.Name:string`,
+					`Root.{}.Ratio:float`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface ExampleTypes {`,
+					`  active: boolean;`,
+					`  code: string;`,
+					`  count: number;`,
+					`  created: string;`,
+					`  name: string;`,
+					`  ratio: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface ExampleTypes {`,
+					`  active: boolean;`,
+					`  code: string;`,
+					`  count: number;`,
+					`  created: string;`,
+					`  name: string;`,
+					`  ratio: number;`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "slices",
-		Value: &SliceStruct{},
+		Name:  "deprecated",
+		Value: DeprecatedTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:SliceStruct`,
-					`TypeRef.SliceStruct:{}`,
-					`TypeRef.SliceStruct:{}.Array2:[]`,
-					`TypeRef.SliceStruct:{}.Array2:[].[]`,
-					`TypeRef.SliceStruct:{}.Array2:[].[].string`,
-					`TypeRef.SliceStruct:{}.Slice:[]`,
-					`TypeRef.SliceStruct:{}.Slice:[].string`,
+					`# Root`,
+					`## 03-type/deprecated`,
+					`Type: struct (DeprecatedTypes)`,
+					`# TypeRef`,
+					`## BasicStruct`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| BoolVal | boolean | yes | no |  |`,
+					`| Float64Val | float | yes | no |  |`,
+					`| IntVal | integer | yes | no |  |`,
+					`| StringVal | string | yes | no |  |`,
+					`## DeprecatedTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| current | integer | yes | no |  |`,
+					`| ~~legacy~~ | struct (BasicStruct) | no | yes |  |`,
+					`| name | string | yes | no |  |`,
+					`| ~~oldName~~ | string | no | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.Array2:[]`,
-					`Root.{}.Array2:[].[]`,
-					`Root.{}.Array2:[].[].string`,
-					`Root.{}.Slice:[]`,
-					`Root.{}.Slice:[].string`,
+					`# Root`,
+					`## 03-type/deprecated`,
+					`Type: struct (DeprecatedTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| current | integer | yes | no |  |`,
+					`| ~~legacy~~ | struct (BasicStruct) | no | yes |  |`,
+					`| legacy.BoolVal | boolean | yes | no |  |`,
+					`| legacy.Float64Val | float | yes | no |  |`,
+					`| legacy.IntVal | integer | yes | no |  |`,
+					`| legacy.StringVal | string | yes | no |  |`,
+					`| name | string | yes | no |  |`,
+					`| ~~oldName~~ | string | no | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: slices`,
+					`  title: deprecated`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/slices:`,
+					`  /03-type/deprecated:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1780,32 +2388,50 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/SliceStruct'`,
+					`                $ref: '#/components/schemas/DeprecatedTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    SliceStruct:`,
+					`    BasicStruct:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        Array2:`,
-					`          type: array`,
-					`          items:`,
-					`            type: array`,
-					`            items:`,
-					`              type: string`,
-					`        Slice:`,
-					`          type: array`,
-					`          items:`,
-					`            type: string`,
+					`        BoolVal:`,
+					`          type: boolean`,
+					`        Float64Val:`,
+					`          type: number`,
+					`          format: double`,
+					`        IntVal:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        StringVal:`,
+					`          type: string`,
+					`    DeprecatedTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        current:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        legacy:`,
+					`          description: 'Use name instead.'`,
+					`          nullable: true`,
+					`          deprecated: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BasicStruct'`,
+					`        name:`,
+					`          type: string`,
+					`        oldName:`,
+					`          deprecated: true`,
+					`          type: string`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: slices`,
+					`  title: deprecated`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /04-list/slices:`,
+					`  /03-type/deprecated:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1814,109 +2440,144 @@ var listTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/SliceStruct'`,
+					`                description: 'From $ref: #/components/schemas/DeprecatedTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  Array2:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: array`,
-					`                      items:`,
+					`                  current:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  legacy:`,
+					`                    description: 'Use name instead.;From $ref: #/components/schemas/BasicStruct'`,
+					`                    nullable: true`,
+					`                    deprecated: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      Float64Val:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      StringVal:`,
 					`                        type: string`,
-					`                  Slice:`,
-					`                    type: array`,
-					`                    items:`,
-					`                      type: string`,
+					`                  name:`,
+					`                    type: string`,
+					`                  oldName:`,
+					`                    deprecated: true`,
+					`                    type: string`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:DeprecatedTypes`,
+					`TypeRef.BasicStruct:{}`,
+					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+					`TypeRef.BasicStruct:{}.Float64Val:float`,
+					`TypeRef.BasicStruct:{}.IntVal:integer`,
+					`TypeRef.BasicStruct:{}.StringVal:string`,
+					`TypeRef.DeprecatedTypes:{}`,
+					`TypeRef.DeprecatedTypes:{}.Current:integer`,
+					`TypeRef.DeprecatedTypes:{}.Legacy:{}:BasicStruct DEPRECATED`,
+					`TypeRef.DeprecatedTypes:{}.Name:string`,
+					`TypeRef.DeprecatedTypes:{}.OldName:string DEPRECATED`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Current:integer`,
+					`Root.{}.Legacy:{} DEPRECATED`,
+					`Root.{}.Legacy:{}.BoolVal:boolean`,
+					`Root.{}.Legacy:{}.Float64Val:float`,
+					`Root.{}.Legacy:{}.IntVal:integer`,
+					`Root.{}.Legacy:{}.StringVal:string`,
+					`Root.{}.Name:string`,
+					`Root.{}.OldName:string DEPRECATED`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface BasicStruct {`,
+					`  BoolVal: boolean;`,
+					`  Float64Val: number;`,
+					`  IntVal: number;`,
+					`  StringVal: string;`,
+					`}`,
+					`export interface DeprecatedTypes {`,
+					`  current: number;`,
+					`  /** @deprecated */`,
+					`  legacy?: BasicStruct;`,
+					`  name: string;`,
+					`  /** @deprecated */`,
+					`  oldName?: string;`,
+					`}`,
+				},
+				true: []string{
+					`export interface DeprecatedTypes {`,
+					`  current: number;`,
+					`  /** @deprecated */`,
+					`  legacy?: {`,
+					`    BoolVal: boolean;`,
+					`    Float64Val: number;`,
+					`    IntVal: number;`,
+					`    StringVal: string;`,
+					`  };`,
+					`  name: string;`,
+					`  /** @deprecated */`,
+					`  oldName?: string;`,
+					`}`,
 				},
 			},
 		},
 	},
-}
-
-type MapTestsStruct struct {
-	MapOK struct {
-		StringVal string
-		IntVal    float64
-		FloatVal  float32
-		BoolVal   bool
-		ListVal   []float64
-		MapVal    struct {
-			Key1 string
-			Key2 struct {
-				DeepKey1 string
-				DeepKey2 float64
-			}
-		}
-	}
-}
-
-var jsonMapTests = `
-{
-	"MapOK": {
-		"StringVal": "Hello",
-		"IntVal": 123,
-		"FloatVal": 234.345,
-		"BoolVal": true,
-		"ListVal": [2,3,4,5],
-		"MapVal": {
-			"Key1": "Hey",
-			"Key2": {
-				"DeepKey1": "Hi",
-				"DeepKey2": 234
-			}
-		}
-	}
-}
-`
-
-var compoundTests = []fixtures.TestCase{
 	{
-		Name:  "golang-map",
-		Value: MapTestsStruct{},
+		Name:  "text-marshaler",
+		Value: TextTypes{},
 		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
+			"markdown": map[bool][]string{
 				false: []string{
-					`Root.{}:MapTestsStruct`,
-					`TypeRef.MapTestsStruct:{}`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.BoolVal:boolean`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.FloatVal:float`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.IntVal:float`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.ListVal:[]`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.ListVal:[].float`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key1:string`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
-					`TypeRef.MapTestsStruct:{}.MapOK:{}.StringVal:string`,
+					`# Root`,
+					`## 03-type/text-marshaler`,
+					`Type: struct (TextTypes)`,
+					`# TypeRef`,
+					`## TextTypes`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| Code | string | yes | no |  |`,
+					`| Created | datetime | yes | no |  |`,
+					`| ID | string | yes | no |  |`,
+					`| IDList | list | yes | no |  |`,
+					`| IDList[] | string | - | no |  |`,
+					`| IDPtr | string | no | yes |  |`,
+					`| IP | string | yes | no |  |`,
 				},
 				true: []string{
-					`Root.{}`,
-					`Root.{}.MapOK:{}`,
-					`Root.{}.MapOK:{}.BoolVal:boolean`,
-					`Root.{}.MapOK:{}.FloatVal:float`,
-					`Root.{}.MapOK:{}.IntVal:float`,
-					`Root.{}.MapOK:{}.ListVal:[]`,
-					`Root.{}.MapOK:{}.ListVal:[].float`,
-					`Root.{}.MapOK:{}.MapVal:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
-					`Root.{}.MapOK:{}.StringVal:string`,
+					`# Root`,
+					`## 03-type/text-marshaler`,
+					`Type: struct (TextTypes)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| Code | string | yes | no |  |`,
+					`| Created | datetime | yes | no |  |`,
+					`| ID | string | yes | no |  |`,
+					`| IDList | list | yes | no |  |`,
+					`| IDList[] | string | - | no |  |`,
+					`| IDPtr | string | no | yes |  |`,
+					`| IP | string | yes | no |  |`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: golang-map`,
+					`  title: text-marshaler`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /05-compound/golang-map:`,
+					`  /03-type/text-marshaler:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1925,55 +2586,38 @@ var compoundTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/MapTestsStruct'`,
+					`                $ref: '#/components/schemas/TextTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    MapTestsStruct:`,
+					`    TextTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        MapOK:`,
-					`          type: object`,
-					`          additionalProperties: false`,
-					`          properties:`,
-					`            BoolVal:`,
-					`              type: boolean`,
-					`            FloatVal:`,
-					`              type: number`,
-					`            IntVal:`,
-					`              type: number`,
-					`              format: double`,
-					`            ListVal:`,
-					`              type: array`,
-					`              items:`,
-					`                type: number`,
-					`                format: double`,
-					`            MapVal:`,
-					`              type: object`,
-					`              additionalProperties: false`,
-					`              properties:`,
-					`                Key1:`,
-					`                  type: string`,
-					`                Key2:`,
-					`                  type: object`,
-					`                  additionalProperties: false`,
-					`                  properties:`,
-					`                    DeepKey1:`,
-					`                      type: string`,
-					`                    DeepKey2:`,
-					`                      type: number`,
-					`                      format: double`,
-					`            StringVal:`,
-					`              type: string`,
+					`        Code:`,
+					`          type: string`,
+					`        Created:`,
+					`          type: string`,
+					`          format: date-time`,
+					`        ID:`,
+					`          type: string`,
+					`        IDList:`,
+					`          type: array`,
+					`          items:`,
+					`            type: string`,
+					`        IDPtr:`,
+					`          nullable: true`,
+					`          type: string`,
+					`        IP:`,
+					`          type: string`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: golang-map`,
+					`  title: text-marshaler`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /05-compound/golang-map:`,
+					`  /03-type/text-marshaler:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -1982,92 +2626,99 @@ var compoundTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/MapTestsStruct'`,
+					`                description: 'From $ref: #/components/schemas/TextTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  MapOK:`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      FloatVal:`,
-					`                        type: number`,
-					`                      IntVal:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      ListVal:`,
-					`                        type: array`,
-					`                        items:`,
-					`                          type: number`,
-					`                          format: double`,
-					`                      MapVal:`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          Key1:`,
-					`                            type: string`,
-					`                          Key2:`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              DeepKey1:`,
-					`                                type: string`,
-					`                              DeepKey2:`,
-					`                                type: number`,
-					`                                format: double`,
-					`                      StringVal:`,
-					`                        type: string`,
+					`                  Code:`,
+					`                    type: string`,
+					`                  Created:`,
+					`                    type: string`,
+					`                    format: date-time`,
+					`                  ID:`,
+					`                    type: string`,
+					`                  IDList:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      type: string`,
+					`                  IDPtr:`,
+					`                    nullable: true`,
+					`                    type: string`,
+					`                  IP:`,
+					`                    type: string`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:TextTypes`,
+					`TypeRef.TextTypes:{}`,
+					`TypeRef.TextTypes:{}.Code:string`,
+					`TypeRef.TextTypes:{}.Created:datetime`,
+					`TypeRef.TextTypes:{}.ID:string`,
+					`TypeRef.TextTypes:{}.IDList:[]`,
+					`TypeRef.TextTypes:{}.IDList:[].string`,
+					`TypeRef.TextTypes:{}.IDPtr:string`,
+					`TypeRef.TextTypes:{}.IP:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Code:string`,
+					`Root.{}.Created:datetime`,
+					`Root.{}.ID:string`,
+					`Root.{}.IDList:[]`,
+					`Root.{}.IDList:[].string`,
+					`Root.{}.IDPtr:string`,
+					`Root.{}.IP:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface TextTypes {`,
+					`  Code: string;`,
+					`  Created: string;`,
+					`  ID: string;`,
+					`  IDList: string[];`,
+					`  IDPtr?: string;`,
+					`  IP: string;`,
+					`}`,
+				},
+				true: []string{
+					`export interface TextTypes {`,
+					`  Code: string;`,
+					`  Created: string;`,
+					`  ID: string;`,
+					`  IDList: string[];`,
+					`  IDPtr?: string;`,
+					`  IP: string;`,
+					`}`,
 				},
 			},
 		},
 	},
 	{
-		Name:  "json-map",
-		Value: fromJSON([]byte(jsonMapTests)),
+		Name:  "boolean",
+		Value: BoolTypes{},
 		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}`,
-					`Root.{}.MapOK:{}`,
-					`Root.{}.MapOK:{}.BoolVal:boolean`,
-					`Root.{}.MapOK:{}.FloatVal:float`,
-					`Root.{}.MapOK:{}.IntVal:float`,
-					`Root.{}.MapOK:{}.ListVal:[]`,
-					`Root.{}.MapOK:{}.ListVal:[].float`,
-					`Root.{}.MapOK:{}.MapVal:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
-					`Root.{}.MapOK:{}.StringVal:string`,
+					`Root.{}:BoolTypes`,
+					`TypeRef.BoolTypes:{}`,
+					`TypeRef.BoolTypes:{}.Bool:boolean`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.MapOK:{}`,
-					`Root.{}.MapOK:{}.BoolVal:boolean`,
-					`Root.{}.MapOK:{}.FloatVal:float`,
-					`Root.{}.MapOK:{}.IntVal:float`,
-					`Root.{}.MapOK:{}.ListVal:[]`,
-					`Root.{}.MapOK:{}.ListVal:[].float`,
-					`Root.{}.MapOK:{}.MapVal:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
-					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
-					`Root.{}.MapOK:{}.StringVal:string`,
+					`Root.{}.Bool:boolean`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-map`,
+					`  title: boolean`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /05-compound/json-map:`,
+					`  /03-type/boolean:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2076,52 +2727,24 @@ var compoundTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                type: object`,
-					`                additionalProperties: false`,
-					`                properties:`,
-					`                  MapOK:`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      FloatVal:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      IntVal:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      ListVal:`,
-					`                        type: array`,
-					`                        items:`,
-					`                          type: number`,
-					`                          format: double`,
-					`                      MapVal:`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          Key1:`,
-					`                            type: string`,
-					`                          Key2:`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              DeepKey1:`,
-					`                                type: string`,
-					`                              DeepKey2:`,
-					`                                type: number`,
-					`                                format: double`,
-					`                      StringVal:`,
-					`                        type: string`,
+					`                $ref: '#/components/schemas/BoolTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    BoolTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Bool:`,
+					`          type: boolean`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-map`,
+					`  title: boolean`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /05-compound/json-map:`,
+					`  /03-type/boolean:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2130,135 +2753,60 @@ var compoundTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/BoolTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  MapOK:`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      FloatVal:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      IntVal:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      ListVal:`,
-					`                        type: array`,
-					`                        items:`,
-					`                          type: number`,
-					`                          format: double`,
-					`                      MapVal:`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          Key1:`,
-					`                            type: string`,
-					`                          Key2:`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              DeepKey1:`,
-					`                                type: string`,
-					`                              DeepKey2:`,
-					`                                type: number`,
-					`                                format: double`,
-					`                      StringVal:`,
-					`                        type: string`,
+					`                  Bool:`,
+					`                    type: boolean`,
 				},
 			},
 		},
 	},
-}
-
-type ReferenceTestsStruct struct {
-	InterfaceVal interface{}
-	PtrVal       *BasicStruct
-	PtrPtrVal    **BasicStruct
-}
-
-var referenceTests = []fixtures.TestCase{
 	{
-		Name:  "reference-tests-empty",
-		Value: ReferenceTestsStruct{},
+		Name:  "integer",
+		Value: IntegerTypes{},
 		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:ReferenceTestsStruct`,
-					`TypeRef.BasicStruct:{}`,
-					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
-					`TypeRef.BasicStruct:{}.Float64Val:float`,
-					`TypeRef.BasicStruct:{}.IntVal:integer`,
-					`TypeRef.BasicStruct:{}.StringVal:string`,
-					`TypeRef.ReferenceTestsStruct:{}`,
-					`TypeRef.ReferenceTestsStruct:{}.!InterfaceVal:invalid! ERROR:interface element is nil`,
-					`TypeRef.ReferenceTestsStruct:{}.PtrPtrVal:{}:BasicStruct`,
-					`TypeRef.ReferenceTestsStruct:{}.PtrVal:{}:BasicStruct`,
+					`Root.{}:IntegerTypes`,
+					`TypeRef.IntegerTypes:{}`,
+					`TypeRef.IntegerTypes:{}.Int:integer`,
+					`TypeRef.IntegerTypes:{}.Int16:integer`,
+					`TypeRef.IntegerTypes:{}.Int32:integer`,
+					`TypeRef.IntegerTypes:{}.Int64:integer`,
+					`TypeRef.IntegerTypes:{}.Int8:integer`,
+					`TypeRef.IntegerTypes:{}.Uint:integer`,
+					`TypeRef.IntegerTypes:{}.Uint16:integer`,
+					`TypeRef.IntegerTypes:{}.Uint32:integer`,
+					`TypeRef.IntegerTypes:{}.Uint64:integer`,
+					`TypeRef.IntegerTypes:{}.Uint8:integer`,
+					`TypeRef.IntegerTypes:{}.Uintptr:integer`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.!InterfaceVal:invalid! ERROR:interface element is nil`,
-					`Root.{}.PtrPtrVal:{}`,
-					`Root.{}.PtrPtrVal:{}.BoolVal:boolean`,
-					`Root.{}.PtrPtrVal:{}.Float64Val:float`,
-					`Root.{}.PtrPtrVal:{}.IntVal:integer`,
-					`Root.{}.PtrPtrVal:{}.StringVal:string`,
-					`Root.{}.PtrVal:{}`,
-					`Root.{}.PtrVal:{}.BoolVal:boolean`,
-					`Root.{}.PtrVal:{}.Float64Val:float`,
-					`Root.{}.PtrVal:{}.IntVal:integer`,
-					`Root.{}.PtrVal:{}.StringVal:string`,
-				},
-			},
-		},
-	},
-	{
-		Name:  "reference-tests-init",
-		Value: ReferenceTestsStruct{InterfaceVal: &BasicStruct{}},
-		Want: map[string]fixtures.WantSet{
-			"simple": map[bool][]string{
-				false: []string{
-					`Root.{}:ReferenceTestsStruct`,
-					`TypeRef.BasicStruct:{}`,
-					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
-					`TypeRef.BasicStruct:{}.Float64Val:float`,
-					`TypeRef.BasicStruct:{}.IntVal:integer`,
-					`TypeRef.BasicStruct:{}.StringVal:string`,
-					`TypeRef.ReferenceTestsStruct:{}`,
-					`TypeRef.ReferenceTestsStruct:{}.InterfaceVal:{}:BasicStruct`,
-					`TypeRef.ReferenceTestsStruct:{}.PtrPtrVal:{}:BasicStruct`,
-					`TypeRef.ReferenceTestsStruct:{}.PtrVal:{}:BasicStruct`,
-				},
-				true: []string{
-					`Root.{}`,
-					`Root.{}.InterfaceVal:{}`,
-					`Root.{}.InterfaceVal:{}.BoolVal:boolean`,
-					`Root.{}.InterfaceVal:{}.Float64Val:float`,
-					`Root.{}.InterfaceVal:{}.IntVal:integer`,
-					`Root.{}.InterfaceVal:{}.StringVal:string`,
-					`Root.{}.PtrPtrVal:{}`,
-					`Root.{}.PtrPtrVal:{}.BoolVal:boolean`,
-					`Root.{}.PtrPtrVal:{}.Float64Val:float`,
-					`Root.{}.PtrPtrVal:{}.IntVal:integer`,
-					`Root.{}.PtrPtrVal:{}.StringVal:string`,
-					`Root.{}.PtrVal:{}`,
-					`Root.{}.PtrVal:{}.BoolVal:boolean`,
-					`Root.{}.PtrVal:{}.Float64Val:float`,
-					`Root.{}.PtrVal:{}.IntVal:integer`,
-					`Root.{}.PtrVal:{}.StringVal:string`,
+					`Root.{}.Int:integer`,
+					`Root.{}.Int16:integer`,
+					`Root.{}.Int32:integer`,
+					`Root.{}.Int64:integer`,
+					`Root.{}.Int8:integer`,
+					`Root.{}.Uint:integer`,
+					`Root.{}.Uint16:integer`,
+					`Root.{}.Uint32:integer`,
+					`Root.{}.Uint64:integer`,
+					`Root.{}.Uint8:integer`,
+					`Root.{}.Uintptr:integer`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: reference-tests-init`,
+					`  title: integer`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /06-reference/reference-tests-init:`,
+					`  /03-type/integer:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2267,41 +2815,55 @@ var referenceTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/ReferenceTestsStruct'`,
+					`                $ref: '#/components/schemas/IntegerTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    BasicStruct:`,
+					`    IntegerTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        BoolVal:`,
-					`          type: boolean`,
-					`        Float64Val:`,
-					`          type: number`,
-					`          format: double`,
-					`        IntVal:`,
+					`        Int:`,
 					`          type: integer`,
-					`        StringVal:`,
-					`          type: string`,
-					`    ReferenceTestsStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        InterfaceVal:`,
-					`          $ref: '#/components/schemas/BasicStruct'`,
-					`        PtrPtrVal:`,
-					`          $ref: '#/components/schemas/BasicStruct'`,
-					`        PtrVal:`,
-					`          $ref: '#/components/schemas/BasicStruct'`,
+					`          format: int64`,
+					`        Int16:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Int32:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Int64:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        Int8:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Uint:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        Uint16:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Uint32:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Uint64:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        Uint8:`,
+					`          type: integer`,
+					`          format: int32`,
+					`        Uintptr:`,
+					`          type: integer`,
+					`          format: int64`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: reference-tests-init`,
+					`  title: integer`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /06-reference/reference-tests-init:`,
+					`  /03-type/integer:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2310,146 +2872,73 @@ var referenceTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/ReferenceTestsStruct'`,
+					`                description: 'From $ref: #/components/schemas/IntegerTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  InterfaceVal:`,
-					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      Float64Val:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      IntVal:`,
-					`                        type: integer`,
-					`                      StringVal:`,
-					`                        type: string`,
-					`                  PtrPtrVal:`,
-					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      Float64Val:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      IntVal:`,
-					`                        type: integer`,
-					`                      StringVal:`,
-					`                        type: string`,
-					`                  PtrVal:`,
-					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      BoolVal:`,
-					`                        type: boolean`,
-					`                      Float64Val:`,
-					`                        type: number`,
-					`                        format: double`,
-					`                      IntVal:`,
-					`                        type: integer`,
-					`                      StringVal:`,
-					`                        type: string`,
+					`                  Int:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Int16:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Int32:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Int64:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Int8:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uint:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Uint16:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uint32:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uint64:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Uint8:`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uintptr:`,
+					`                    type: integer`,
+					`                    format: int64`,
 				},
 			},
 		},
 	},
-}
-
-// Test cyclical relationships:
-// A --> B --> C --> A
-type AStruct struct {
-	AName  string   `json:"aName,omitempty"`
-	AChild *BStruct `json:"aChild"`
-}
-
-type BStruct struct {
-	BName  string   `json:"bName"`
-	BChild *CStruct `json:"bChild"`
-}
-
-type CStruct struct {
-	CName  string   `json:"cName"`
-	CChild *AStruct `json:"cChild"`
-}
-
-type BadType interface{}
-
-type CycleTest struct {
-	Level  int      `json:"-"`
-	CycleA AStruct  `json:"cycleA"`
-	CycleB *BStruct `json:"cycleB"`
-	CycleC struct {
-		C CStruct `json:"c"`
-	}
-}
-
-var cycleTests = []fixtures.TestCase{
 	{
-		Name:  "cycle-test",
-		Value: &CycleTest{},
+		Name:  `float`,
+		Value: FloatTypes{},
 		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:CycleTest`,
-					`TypeRef.AStruct:{}`,
-					`TypeRef.AStruct:{}.AChild:{}:BStruct`,
-					`TypeRef.AStruct:{}.AName:string`,
-					`TypeRef.BStruct:{}`,
-					`TypeRef.BStruct:{}.BChild:{}:CStruct`,
-					`TypeRef.BStruct:{}.BName:string`,
-					`TypeRef.CStruct:{}`,
-					`TypeRef.CStruct:{}.CChild:{}:AStruct`,
-					`TypeRef.CStruct:{}.CName:string`,
-					`TypeRef.CycleTest:{}`,
-					`TypeRef.CycleTest:{}.CycleA:{}:AStruct`,
-					`TypeRef.CycleTest:{}.CycleB:{}:BStruct`,
-					`TypeRef.CycleTest:{}.CycleC:{}`,
-					`TypeRef.CycleTest:{}.CycleC:{}.C:{}:CStruct`,
-					`TypeRef.CycleTest:{}.Level:integer`,
+					`Root.{}:FloatTypes`,
+					`TypeRef.FloatTypes:{}`,
+					`TypeRef.FloatTypes:{}.Float32:float`,
+					`TypeRef.FloatTypes:{}.Float64:float`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.CycleA:{}`,
-					`Root.{}.CycleA:{}.AChild:{}`,
-					`Root.{}.CycleA:{}.AChild:{}.BChild:{}`,
-					`Root.{}.CycleA:{}.AChild:{}.BChild:{}.!CChild:{}:AStruct! ERROR:cyclical reference`,
-					`Root.{}.CycleA:{}.AChild:{}.BChild:{}.CName:string`,
-					`Root.{}.CycleA:{}.AChild:{}.BName:string`,
-					`Root.{}.CycleA:{}.AName:string`,
-					`Root.{}.CycleB:{}`,
-					`Root.{}.CycleB:{}.BChild:{}`,
-					`Root.{}.CycleB:{}.BChild:{}.CChild:{}`,
-					`Root.{}.CycleB:{}.BChild:{}.CChild:{}.!AChild:{}:BStruct! ERROR:cyclical reference`,
-					`Root.{}.CycleB:{}.BChild:{}.CChild:{}.AName:string`,
-					`Root.{}.CycleB:{}.BChild:{}.CName:string`,
-					`Root.{}.CycleB:{}.BName:string`,
-					`Root.{}.CycleC:{}`,
-					`Root.{}.CycleC:{}.C:{}`,
-					`Root.{}.CycleC:{}.C:{}.CChild:{}`,
-					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}`,
-					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}.!BChild:{}:CStruct! ERROR:cyclical reference`,
-					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}.BName:string`,
-					`Root.{}.CycleC:{}.C:{}.CChild:{}.AName:string`,
-					`Root.{}.CycleC:{}.C:{}.CName:string`,
-					`Root.{}.Level:integer`,
+					`Root.{}.Float32:float`,
+					`Root.{}.Float64:float`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: cycle-test`,
+					`  title: float`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /07-cycle/cycle-test:`,
+					`  /03-type/float:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2458,56 +2947,28 @@ var cycleTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/CycleTest'`,
+					`                $ref: '#/components/schemas/FloatTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    AStruct:`,
+					`    FloatTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        aChild:`,
-					`          $ref: '#/components/schemas/BStruct'`,
-					`        aName:`,
-					`          type: string`,
-					`    BStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        bChild:`,
-					`          $ref: '#/components/schemas/CStruct'`,
-					`        bName:`,
-					`          type: string`,
-					`    CStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        cChild:`,
-					`          $ref: '#/components/schemas/AStruct'`,
-					`        cName:`,
-					`          type: string`,
-					`    CycleTest:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        cycleA:`,
-					`          $ref: '#/components/schemas/AStruct'`,
-					`        cycleB:`,
-					`          $ref: '#/components/schemas/BStruct'`,
-					`        CycleC:`,
-					`          type: object`,
-					`          additionalProperties: false`,
-					`          properties:`,
-					`            c:`,
-					`              $ref: '#/components/schemas/CStruct'`,
+					`        Float32:`,
+					`          type: number`,
+					`          format: float`,
+					`        Float64:`,
+					`          type: number`,
+					`          format: double`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: cycle-test`,
+					`  title: float`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /07-cycle/cycle-test:`,
+					`  /03-type/float:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2516,133 +2977,44 @@ var cycleTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/CycleTest'`,
+					`                description: 'From $ref: #/components/schemas/FloatTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  cycleA:`,
-					`                    description: 'From $ref: #/components/schemas/AStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      aChild:`,
-					`                        description: 'From $ref: #/components/schemas/BStruct'`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          bChild:`,
-					`                            description: 'From $ref: #/components/schemas/CStruct'`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              cChild:`,
-					`                                description: 'From $ref: #/components/schemas/AStruct;ERROR=cyclical reference'`,
-					`                                type: object`,
-					`                                additionalProperties: false`,
-					`                              cName:`,
-					`                                type: string`,
-					`                          bName:`,
-					`                            type: string`,
-					`                      aName:`,
-					`                        type: string`,
-					`                  cycleB:`,
-					`                    description: 'From $ref: #/components/schemas/BStruct'`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      bChild:`,
-					`                        description: 'From $ref: #/components/schemas/CStruct'`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          cChild:`,
-					`                            description: 'From $ref: #/components/schemas/AStruct'`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              aChild:`,
-					`                                description: 'From $ref: #/components/schemas/BStruct;ERROR=cyclical reference'`,
-					`                                type: object`,
-					`                                additionalProperties: false`,
-					`                              aName:`,
-					`                                type: string`,
-					`                          cName:`,
-					`                            type: string`,
-					`                      bName:`,
-					`                        type: string`,
-					`                  CycleC:`,
-					`                    type: object`,
-					`                    additionalProperties: false`,
-					`                    properties:`,
-					`                      c:`,
-					`                        description: 'From $ref: #/components/schemas/CStruct'`,
-					`                        type: object`,
-					`                        additionalProperties: false`,
-					`                        properties:`,
-					`                          cChild:`,
-					`                            description: 'From $ref: #/components/schemas/AStruct'`,
-					`                            type: object`,
-					`                            additionalProperties: false`,
-					`                            properties:`,
-					`                              aChild:`,
-					`                                description: 'From $ref: #/components/schemas/BStruct'`,
-					`                                type: object`,
-					`                                additionalProperties: false`,
-					`                                properties:`,
-					`                                  bChild:`,
-					`                                    description: 'From $ref: #/components/schemas/CStruct;ERROR=cyclical reference'`,
-					`                                    type: object`,
-					`                                    additionalProperties: false`,
-					`                                  bName:`,
-					`                                    type: string`,
-					`                              aName:`,
-					`                                type: string`,
-					`                          cName:`,
-					`                            type: string`,
+					`                  Float32:`,
+					`                    type: number`,
+					`                    format: float`,
+					`                  Float64:`,
+					`                    type: number`,
+					`                    format: double`,
 				},
 			},
 		},
 	},
-}
-
-type JSONTagTests struct {
-	NoTag      string
-	ExcludeTag string `json:"-"`
-	RenameOne  string `json:"renameOne"`
-	RenameTwo  string `json:"something"`
-}
-
-var jsonTagTests = []fixtures.TestCase{
 	{
-		Name:  "json-tags",
-		Value: JSONTagTests{},
+		Name:  "string",
+		Value: StringTypes{},
 		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:JSONTagTests`,
-					`TypeRef.JSONTagTests:{}`,
-					`TypeRef.JSONTagTests:{}.ExcludeTag:string`,
-					`TypeRef.JSONTagTests:{}.NoTag:string`,
-					`TypeRef.JSONTagTests:{}.RenameOne:string`,
-					`TypeRef.JSONTagTests:{}.RenameTwo:string`,
+					`Root.{}:StringTypes`,
+					`TypeRef.StringTypes:{}`,
+					`TypeRef.StringTypes:{}.String:string`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.ExcludeTag:string`,
-					`Root.{}.NoTag:string`,
-					`Root.{}.RenameOne:string`,
-					`Root.{}.RenameTwo:string`,
+					`Root.{}.String:string`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-tags`,
+					`  title: string`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /08-json-tag/json-tags:`,
+					`  /03-type/string:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2651,28 +3023,24 @@ var jsonTagTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/JSONTagTests'`,
+					`                $ref: '#/components/schemas/StringTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    JSONTagTests:`,
+					`    StringTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        NoTag:`,
-					`          type: string`,
-					`        renameOne:`,
-					`          type: string`,
-					`        something:`,
+					`        String:`,
 					`          type: string`,
 				},
 				true: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: json-tags`,
+					`  title: string`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /08-json-tag/json-tags:`,
+					`  /03-type/string:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2681,67 +3049,48 @@ var jsonTagTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/JSONTagTests'`,
+					`                description: 'From $ref: #/components/schemas/StringTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  NoTag:`,
-					`                    type: string`,
-					`                  renameOne:`,
-					`                    type: string`,
-					`                  something:`,
+					`                  String:`,
 					`                    type: string`,
 				},
 			},
 		},
 	},
-}
-
-var nestedTests = []fixtures.TestCase{
 	{
-		Name:  "nested-struct",
-		Value: OuterStruct{},
+		Name:  "invalid",
+		Value: InvalidTypes{},
 		Want: map[string]fixtures.WantSet{
 			"simple": map[bool][]string{
 				false: []string{
-					`Root.{}:OuterStruct`,
-					`TypeRef.BasicStruct:{}`,
-					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
-					`TypeRef.BasicStruct:{}.Float64Val:float`,
-					`TypeRef.BasicStruct:{}.IntVal:integer`,
-					`TypeRef.BasicStruct:{}.StringVal:string`,
-					`TypeRef.InnerStruct:{}`,
-					`TypeRef.InnerStruct:{}.ListOfStrings:[]`,
-					`TypeRef.InnerStruct:{}.ListOfStrings:[].string`,
-					`TypeRef.InnerStruct:{}.ListOfStructs:[]`,
-					`TypeRef.InnerStruct:{}.ListOfStructs:[].{}:BasicStruct`,
-					`TypeRef.OuterStruct:{}`,
-					`TypeRef.OuterStruct:{}.ID:integer`,
-					`TypeRef.OuterStruct:{}.Inner:{}:InnerStruct`,
+					`Root.{}:InvalidTypes`,
+					`TypeRef.InvalidTypes:{}`,
+					`TypeRef.InvalidTypes:{}.!Chan:invalid:chan! ERROR:kind not supported`,
+					`TypeRef.InvalidTypes:{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
+					`TypeRef.InvalidTypes:{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
+					`TypeRef.InvalidTypes:{}.!Func:invalid:func! ERROR:kind not supported`,
+					`TypeRef.InvalidTypes:{}."!UnsafePointer:invalid:unsafe.Pointer!" ERROR:kind not supported`,
 				},
 				true: []string{
 					`Root.{}`,
-					`Root.{}.ID:integer`,
-					`Root.{}.Inner:{}`,
-					`Root.{}.Inner:{}.ListOfStrings:[]`,
-					`Root.{}.Inner:{}.ListOfStrings:[].string`,
-					`Root.{}.Inner:{}.ListOfStructs:[]`,
-					`Root.{}.Inner:{}.ListOfStructs:[].{}`,
-					`Root.{}.Inner:{}.ListOfStructs:[].{}.BoolVal:boolean`,
-					`Root.{}.Inner:{}.ListOfStructs:[].{}.Float64Val:float`,
-					`Root.{}.Inner:{}.ListOfStructs:[].{}.IntVal:integer`,
-					`Root.{}.Inner:{}.ListOfStructs:[].{}.StringVal:string`,
+					`Root.{}.!Chan:invalid:chan! ERROR:kind not supported`,
+					`Root.{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
+					`Root.{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
+					`Root.{}.!Func:invalid:func! ERROR:kind not supported`,
+					`Root.{}."!UnsafePointer:invalid:unsafe.Pointer!" ERROR:kind not supported`,
 				},
 			},
 			"openapi": map[bool][]string{
 				false: []string{
 					`openapi: 3.0.0`,
 					`info:`,
-					`  title: nested-struct`,
+					`  title: invalid`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /09-nested/nested-struct:`,
+					`  /03-type/invalid:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2750,51 +3099,37 @@ var nestedTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                $ref: '#/components/schemas/OuterStruct'`,
+					`                $ref: '#/components/schemas/InvalidTypes'`,
 					`components:`,
 					`  schemas:`,
-					`    BasicStruct:`,
+					`    InvalidTypes:`,
 					`      type: object`,
 					`      additionalProperties: false`,
 					`      properties:`,
-					`        BoolVal:`,
-					`          type: boolean`,
-					`        Float64Val:`,
-					`          type: number`,
-					`          format: double`,
-					`        IntVal:`,
-					`          type: integer`,
-					`        StringVal:`,
+					`        Chan:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:chan'`,
 					`          type: string`,
-					`    InnerStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        listOfStrings:`,
-					`          type: array`,
-					`          items:`,
-					`            type: string`,
-					`        listOfStructs:`,
-					`          type: array`,
-					`          items:`,
-					`            $ref: '#/components/schemas/BasicStruct'`,
-					`    OuterStruct:`,
-					`      type: object`,
-					`      additionalProperties: false`,
-					`      properties:`,
-					`        id:`,
-					`          type: integer`,
-					`        inner:`,
-					`          $ref: '#/components/schemas/InnerStruct'`,
-				},
-				true: []string{
-					`openapi: 3.0.0`,
-					`info:`,
-					`  title: nested-struct`,
+					`        Complex128:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`          type: string`,
+					`        Complex64:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`          type: string`,
+					`        Func:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:func'`,
+					`          type: string`,
+					`        UnsafePointer:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:unsafe.Pointer'`,
+					`          type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: invalid`,
 					`  version: v1.0.0`,
 					``,
 					`paths:`,
-					`  /09-nested/nested-struct:`,
+					`  /03-type/invalid:`,
 					`    get:`,
 					`      summary: Return data.`,
 					`      responses:`,
@@ -2803,377 +3138,7025 @@ var nestedTests = []fixtures.TestCase{
 					`          content:`,
 					`            application/json:`,
 					`              schema:`,
-					`                description: 'From $ref: #/components/schemas/OuterStruct'`,
+					`                description: 'From $ref: #/components/schemas/InvalidTypes'`,
 					`                type: object`,
 					`                additionalProperties: false`,
 					`                properties:`,
-					`                  id:`,
-					`                    type: integer`,
-					`                  inner:`,
-					`                    description: 'From $ref: #/components/schemas/InnerStruct'`,
+					`                  Chan:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:chan'`,
+					`                    type: string`,
+					`                  Complex128:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`                    type: string`,
+					`                  Complex64:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`                    type: string`,
+					`                  Func:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:func'`,
+					`                    type: string`,
+					`                  UnsafePointer:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:unsafe.Pointer'`,
+					`                    type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "compound",
+		Value: CompoundTypes{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:CompoundTypes`,
+					`TypeRef.CompoundTypes:{}`,
+					`TypeRef.CompoundTypes:{}.Array0:[]`,
+					`TypeRef.CompoundTypes:{}.Array0:[].string`,
+					`TypeRef.CompoundTypes:{}.Array3:[]`,
+					`TypeRef.CompoundTypes:{}.Array3:[].string`,
+					`TypeRef.CompoundTypes:{}.!Interface:invalid! ERROR:interface element is nil`,
+					`TypeRef.CompoundTypes:{}.!Map:map{}! ERROR:map key type must be string`,
+					`TypeRef.CompoundTypes:{}.PrivatePtr:{}:PrivateStruct`,
+					`TypeRef.CompoundTypes:{}.Ptr:{}:StringStruct`,
+					`TypeRef.CompoundTypes:{}.Slice:[]`,
+					`TypeRef.CompoundTypes:{}.Slice:[].!invalid! ERROR:interface element is nil`,
+					`TypeRef.CompoundTypes:{}.!Struct:{}! ERROR:empty struct not supported`,
+					`TypeRef.!PrivateStruct:{}! ERROR:struct has no exported fields`,
+					`TypeRef.StringStruct:{}`,
+					`TypeRef.StringStruct:{}.Value:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Array0:[]`,
+					`Root.{}.Array0:[].string`,
+					`Root.{}.Array3:[]`,
+					`Root.{}.Array3:[].string`,
+					`Root.{}.!Interface:invalid! ERROR:interface element is nil`,
+					`Root.{}.!Map:map{}! ERROR:map key type must be string`,
+					`Root.{}.!PrivatePtr:{}! ERROR:struct has no exported fields`,
+					`Root.{}.Ptr:{}`,
+					`Root.{}.Ptr:{}.Value:string`,
+					`Root.{}.Slice:[]`,
+					`Root.{}.Slice:[].!invalid! ERROR:interface element is nil`,
+					`Root.{}.!Struct:{}! ERROR:empty struct not supported`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: compound`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/compound:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/CompoundTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    CompoundTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Array0:`,
+					`          type: array`,
+					`          minItems: 0`,
+					`          maxItems: 0`,
+					`          items:`,
+					`            type: string`,
+					`        Array3:`,
+					`          type: array`,
+					`          minItems: 3`,
+					`          maxItems: 3`,
+					`          items:`,
+					`            type: string`,
+					`        Interface:`,
+					`          description: 'ERROR=interface element is nil'`,
+					`          type: string`,
+					`        Map:`,
+					`          description: 'ERROR=map key type must be string'`,
+					`          type: object`,
+					`          additionalProperties: true`,
+					`        PrivatePtr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/PrivateStruct'`,
+					`        Ptr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/StringStruct'`,
+					`        Slice:`,
+					`          type: array`,
+					`          items:`,
+					`            description: 'ERROR=interface element is nil'`,
+					`            type: string`,
+					`        Struct:`,
+					`          description: 'ERROR=empty struct not supported'`,
+					`          type: object`,
+					`          additionalProperties: false`,
+					`    PrivateStruct:`,
+					`      description: 'ERROR=struct has no exported fields'`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`    StringStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Value:`,
+					`          type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: compound`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/compound:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/CompoundTypes'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array0:`,
+					`                    type: array`,
+					`                    minItems: 0`,
+					`                    maxItems: 0`,
+					`                    items:`,
+					`                      type: string`,
+					`                  Array3:`,
+					`                    type: array`,
+					`                    minItems: 3`,
+					`                    maxItems: 3`,
+					`                    items:`,
+					`                      type: string`,
+					`                  Interface:`,
+					`                    description: 'ERROR=interface element is nil'`,
+					`                    type: string`,
+					`                  Map:`,
+					`                    description: 'ERROR=map key type must be string'`,
+					`                    type: object`,
+					`                    additionalProperties: true`,
+					`                  PrivatePtr:`,
+					`                    description: 'From $ref: #/components/schemas/PrivateStruct;ERROR=struct has no exported fields'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                  Ptr:`,
+					`                    description: 'From $ref: #/components/schemas/StringStruct'`,
+					`                    nullable: true`,
 					`                    type: object`,
 					`                    additionalProperties: false`,
 					`                    properties:`,
-					`                      listOfStrings:`,
-					`                        type: array`,
-					`                        items:`,
-					`                          type: string`,
-					`                      listOfStructs:`,
-					`                        type: array`,
-					`                        items:`,
-					`                          description: 'From $ref: #/components/schemas/BasicStruct'`,
-					`                          type: object`,
-					`                          additionalProperties: false`,
-					`                          properties:`,
-					`                            BoolVal:`,
-					`                              type: boolean`,
-					`                            Float64Val:`,
-					`                              type: number`,
-					`                              format: double`,
-					`                            IntVal:`,
-					`                              type: integer`,
-					`                            StringVal:`,
-					`                              type: string`,
+					`                      Value:`,
+					`                        type: string`,
+					`                  Slice:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      description: 'ERROR=interface element is nil'`,
+					`                      type: string`,
+					`                  Struct:`,
+					`                    description: 'ERROR=empty struct not supported'`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "special",
+		Value: SpecialTypes{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:SpecialTypes`,
+					`TypeRef.SpecialTypes:{}`,
+					`TypeRef.SpecialTypes:{}.DateTime:datetime`,
+					`TypeRef.SpecialTypes:{}.Duration:duration`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.DateTime:datetime`,
+					`Root.{}.Duration:duration`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface SpecialTypes {`,
+					`  DateTime: string;`,
+					`  Duration: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface SpecialTypes {`,
+					`  DateTime: string;`,
+					`  Duration: number;`,
+					`}`,
 				},
 			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: special`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/special:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/SpecialTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    SpecialTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        DateTime:`,
+					`          type: string`,
+					`          format: date-time`,
+					`        Duration:`,
+					`          type: integer`,
+					`          format: int64`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: special`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/special:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/SpecialTypes'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  DateTime:`,
+					`                    type: string`,
+					`                    format: date-time`,
+					`                  Duration:`,
+					`                    type: integer`,
+					`                    format: int64`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "special-pointer",
+		Value: NullableSpecialTypes{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:NullableSpecialTypes`,
+					`TypeRef.NullableSpecialTypes:{}`,
+					`TypeRef.NullableSpecialTypes:{}.DateTime:datetime`,
+					`TypeRef.NullableSpecialTypes:{}.Duration:duration`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.DateTime:datetime`,
+					`Root.{}.Duration:duration`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface NullableSpecialTypes {`,
+					`  dateTime?: string;`,
+					`  duration?: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface NullableSpecialTypes {`,
+					`  dateTime?: string;`,
+					`  duration?: number;`,
+					`}`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: special-pointer`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/special-pointer:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/NullableSpecialTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    NullableSpecialTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        dateTime:`,
+					`          nullable: true`,
+					`          type: string`,
+					`          format: date-time`,
+					`        duration:`,
+					`          nullable: true`,
+					`          type: integer`,
+					`          format: int64`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: special-pointer`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/special-pointer:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/NullableSpecialTypes'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  dateTime:`,
+					`                    nullable: true`,
+					`                    type: string`,
+					`                    format: date-time`,
+					`                  duration:`,
+					`                    nullable: true`,
+					`                    type: integer`,
+					`                    format: int64`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "duration",
+		Value: DurationTypes{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:DurationTypes`,
+					`TypeRef.DurationTypes:{}`,
+					`TypeRef.DurationTypes:{}.Intervals:[]`,
+					`TypeRef.DurationTypes:{}.Intervals:[].duration`,
+					`TypeRef.DurationTypes:{}.Limits:map{}`,
+					`TypeRef.DurationTypes:{}.Limits:map{}.duration`,
+					`TypeRef.DurationTypes:{}.Retry:duration`,
+					`TypeRef.DurationTypes:{}.Timeout:duration`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Intervals:[]`,
+					`Root.{}.Intervals:[].duration`,
+					`Root.{}.Limits:map{}`,
+					`Root.{}.Limits:map{}.duration`,
+					`Root.{}.Retry:duration`,
+					`Root.{}.Timeout:duration`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface DurationTypes {`,
+					`  intervals: number[];`,
+					`  limits: Record<string, number>;`,
+					`  retry?: number;`,
+					`  timeout: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface DurationTypes {`,
+					`  intervals: number[];`,
+					`  limits: Record<string, number>;`,
+					`  retry?: number;`,
+					`  timeout: number;`,
+					`}`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: duration`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/duration:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/DurationTypes'`,
+					`components:`,
+					`  schemas:`,
+					`    DurationTypes:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        intervals:`,
+					`          type: array`,
+					`          items:`,
+					`            type: integer`,
+					`            format: int64`,
+					`        limits:`,
+					`          type: object`,
+					`          additionalProperties:`,
+					`            type: integer`,
+					`            format: int64`,
+					`        retry:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        timeout:`,
+					`          type: integer`,
+					`          format: int64`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: duration`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/duration:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/DurationTypes'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  intervals:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      type: integer`,
+					`                      format: int64`,
+					`                  limits:`,
+					`                    type: object`,
+					`                    additionalProperties:`,
+					`                      type: integer`,
+					`                      format: int64`,
+					`                  retry:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  timeout:`,
+					`                    type: integer`,
+					`                    format: int64`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "redefined",
+		Value: RedefineStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:RedefineStruct`,
+					`TypeRef.MyArray0:[]`,
+					`TypeRef.MyArray0:[].string`,
+					`TypeRef.MyArray3:[]`,
+					`TypeRef.MyArray3:[].string`,
+					`TypeRef.MyBool:boolean`,
+					`TypeRef.MyDateTime:datetime`,
+					`TypeRef.MyFloat32:float`,
+					`TypeRef.MyFloat64:float`,
+					`TypeRef.MyInt:integer`,
+					`TypeRef.MyInt16:integer`,
+					`TypeRef.MyInt32:integer`,
+					`TypeRef.MyInt64:integer`,
+					`TypeRef.MyInt8:integer`,
+					`TypeRef.!MyInterface:invalid! ERROR:interface element is nil`,
+					`TypeRef.!MyMap:map{}! ERROR:map key type must be string`,
+					`TypeRef.MySlice:[]`,
+					`TypeRef.MySlice:[].!invalid! ERROR:interface element is nil`,
+					`TypeRef.MyString:string`,
+					`TypeRef.!MyStruct:{}! ERROR:empty struct not supported`,
+					`TypeRef.MyUint:integer`,
+					`TypeRef.MyUint16:integer`,
+					`TypeRef.MyUint32:integer`,
+					`TypeRef.MyUint64:integer`,
+					`TypeRef.MyUint8:integer`,
+					`TypeRef.MyUintptr:integer`,
+					`TypeRef.!PrivateStruct:{}! ERROR:struct has no exported fields`,
+					`TypeRef.RedefineStruct:{}`,
+					`TypeRef.RedefineStruct:{}.Array0:[]:MyArray0`,
+					`TypeRef.RedefineStruct:{}.Array3:[]:MyArray3`,
+					`TypeRef.RedefineStruct:{}.Bool:boolean:MyBool`,
+					`TypeRef.RedefineStruct:{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
+					`TypeRef.RedefineStruct:{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
+					`TypeRef.RedefineStruct:{}.DateTime:datetime:MyDateTime`,
+					`TypeRef.RedefineStruct:{}.Float32:float:MyFloat32`,
+					`TypeRef.RedefineStruct:{}.Float64:float:MyFloat64`,
+					`TypeRef.RedefineStruct:{}.Int:integer:MyInt`,
+					`TypeRef.RedefineStruct:{}.Int16:integer:MyInt16`,
+					`TypeRef.RedefineStruct:{}.Int32:integer:MyInt32`,
+					`TypeRef.RedefineStruct:{}.Int64:integer:MyInt64`,
+					`TypeRef.RedefineStruct:{}.Int8:integer:MyInt8`,
+					`TypeRef.RedefineStruct:{}.Interface:invalid:MyInterface`,
+					`TypeRef.RedefineStruct:{}.Map:map{}:MyMap`,
+					`TypeRef.RedefineStruct:{}.PrivatePtr:{}:PrivateStruct`,
+					`TypeRef.RedefineStruct:{}.Ptr:{}:StringStruct`,
+					`TypeRef.RedefineStruct:{}.Slice:[]:MySlice`,
+					`TypeRef.RedefineStruct:{}.String:string:MyString`,
+					`TypeRef.RedefineStruct:{}.Struct:{}:MyStruct`,
+					`TypeRef.RedefineStruct:{}.Uint:integer:MyUint`,
+					`TypeRef.RedefineStruct:{}.Uint16:integer:MyUint16`,
+					`TypeRef.RedefineStruct:{}.Uint32:integer:MyUint32`,
+					`TypeRef.RedefineStruct:{}.Uint64:integer:MyUint64`,
+					`TypeRef.RedefineStruct:{}.Uint8:integer:MyUint8`,
+					`TypeRef.RedefineStruct:{}.Uintptr:integer:MyUintptr`,
+					`TypeRef.StringStruct:{}`,
+					`TypeRef.StringStruct:{}.Value:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Array0:[]`,
+					`Root.{}.Array0:[].string`,
+					`Root.{}.Array3:[]`,
+					`Root.{}.Array3:[].string`,
+					`Root.{}.Bool:boolean`,
+					`Root.{}.!Complex128:invalid:complex128! ERROR:kind not supported`,
+					`Root.{}.!Complex64:invalid:complex64! ERROR:kind not supported`,
+					`Root.{}.DateTime:datetime`,
+					`Root.{}.Float32:float`,
+					`Root.{}.Float64:float`,
+					`Root.{}.Int:integer`,
+					`Root.{}.Int16:integer`,
+					`Root.{}.Int32:integer`,
+					`Root.{}.Int64:integer`,
+					`Root.{}.Int8:integer`,
+					`Root.{}.!Interface:invalid! ERROR:interface element is nil`,
+					`Root.{}.!Map:map{}! ERROR:map key type must be string`,
+					`Root.{}.!PrivatePtr:{}! ERROR:struct has no exported fields`,
+					`Root.{}.Ptr:{}`,
+					`Root.{}.Ptr:{}.Value:string`,
+					`Root.{}.Slice:[]`,
+					`Root.{}.Slice:[].!invalid! ERROR:interface element is nil`,
+					`Root.{}.String:string`,
+					`Root.{}.!Struct:{}! ERROR:empty struct not supported`,
+					`Root.{}.Uint:integer`,
+					`Root.{}.Uint16:integer`,
+					`Root.{}.Uint32:integer`,
+					`Root.{}.Uint64:integer`,
+					`Root.{}.Uint8:integer`,
+					`Root.{}.Uintptr:integer`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: redefined`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/redefined:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/RedefineStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    MyArray0:`,
+					`      type: array`,
+					`      minItems: 0`,
+					`      maxItems: 0`,
+					`      items:`,
+					`        type: string`,
+					`    MyArray3:`,
+					`      type: array`,
+					`      minItems: 3`,
+					`      maxItems: 3`,
+					`      items:`,
+					`        type: string`,
+					`    MyBool:`,
+					`      type: boolean`,
+					`    MyDateTime:`,
+					`      type: string`,
+					`      format: date-time`,
+					`    MyFloat32:`,
+					`      type: number`,
+					`      format: float`,
+					`    MyFloat64:`,
+					`      type: number`,
+					`      format: double`,
+					`    MyInt:`,
+					`      type: integer`,
+					`      format: int64`,
+					`    MyInt16:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyInt32:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyInt64:`,
+					`      type: integer`,
+					`      format: int64`,
+					`    MyInt8:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyInterface:`,
+					`      description: 'ERROR=interface element is nil'`,
+					`      type: string`,
+					`    MyMap:`,
+					`      description: 'ERROR=map key type must be string'`,
+					`      type: object`,
+					`      additionalProperties: true`,
+					`    MySlice:`,
+					`      type: array`,
+					`      items:`,
+					`        description: 'ERROR=interface element is nil'`,
+					`        type: string`,
+					`    MyString:`,
+					`      type: string`,
+					`    MyStruct:`,
+					`      description: 'ERROR=empty struct not supported'`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`    MyUint:`,
+					`      type: integer`,
+					`      format: int64`,
+					`    MyUint16:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyUint32:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyUint64:`,
+					`      type: integer`,
+					`      format: int64`,
+					`    MyUint8:`,
+					`      type: integer`,
+					`      format: int32`,
+					`    MyUintptr:`,
+					`      type: integer`,
+					`      format: int64`,
+					`    PrivateStruct:`,
+					`      description: 'ERROR=struct has no exported fields'`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`    RedefineStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Array0:`,
+					`          $ref: '#/components/schemas/MyArray0'`,
+					`        Array3:`,
+					`          $ref: '#/components/schemas/MyArray3'`,
+					`        Bool:`,
+					`          $ref: '#/components/schemas/MyBool'`,
+					`        Complex128:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`          type: string`,
+					`        Complex64:`,
+					`          description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`          type: string`,
+					`        DateTime:`,
+					`          $ref: '#/components/schemas/MyDateTime'`,
+					`        Float32:`,
+					`          $ref: '#/components/schemas/MyFloat32'`,
+					`        Float64:`,
+					`          $ref: '#/components/schemas/MyFloat64'`,
+					`        Int:`,
+					`          $ref: '#/components/schemas/MyInt'`,
+					`        Int16:`,
+					`          $ref: '#/components/schemas/MyInt16'`,
+					`        Int32:`,
+					`          $ref: '#/components/schemas/MyInt32'`,
+					`        Int64:`,
+					`          $ref: '#/components/schemas/MyInt64'`,
+					`        Int8:`,
+					`          $ref: '#/components/schemas/MyInt8'`,
+					`        Interface:`,
+					`          $ref: '#/components/schemas/MyInterface'`,
+					`        Map:`,
+					`          $ref: '#/components/schemas/MyMap'`,
+					`        PrivatePtr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/PrivateStruct'`,
+					`        Ptr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/StringStruct'`,
+					`        Slice:`,
+					`          $ref: '#/components/schemas/MySlice'`,
+					`        String:`,
+					`          $ref: '#/components/schemas/MyString'`,
+					`        Struct:`,
+					`          $ref: '#/components/schemas/MyStruct'`,
+					`        Uint:`,
+					`          $ref: '#/components/schemas/MyUint'`,
+					`        Uint16:`,
+					`          $ref: '#/components/schemas/MyUint16'`,
+					`        Uint32:`,
+					`          $ref: '#/components/schemas/MyUint32'`,
+					`        Uint64:`,
+					`          $ref: '#/components/schemas/MyUint64'`,
+					`        Uint8:`,
+					`          $ref: '#/components/schemas/MyUint8'`,
+					`        Uintptr:`,
+					`          $ref: '#/components/schemas/MyUintptr'`,
+					`    StringStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Value:`,
+					`          type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: redefined`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /03-type/redefined:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/RedefineStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array0:`,
+					`                    description: 'From $ref: #/components/schemas/MyArray0'`,
+					`                    type: array`,
+					`                    minItems: 0`,
+					`                    maxItems: 0`,
+					`                    items:`,
+					`                      type: string`,
+					`                  Array3:`,
+					`                    description: 'From $ref: #/components/schemas/MyArray3'`,
+					`                    type: array`,
+					`                    minItems: 3`,
+					`                    maxItems: 3`,
+					`                    items:`,
+					`                      type: string`,
+					`                  Bool:`,
+					`                    description: 'From $ref: #/components/schemas/MyBool'`,
+					`                    type: boolean`,
+					`                  Complex128:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:complex128'`,
+					`                    type: string`,
+					`                  Complex64:`,
+					`                    description: 'ERROR=kind not supported;Kind=invalid:complex64'`,
+					`                    type: string`,
+					`                  DateTime:`,
+					`                    description: 'From $ref: #/components/schemas/MyDateTime'`,
+					`                    type: string`,
+					`                    format: date-time`,
+					`                  Float32:`,
+					`                    description: 'From $ref: #/components/schemas/MyFloat32'`,
+					`                    type: number`,
+					`                    format: float`,
+					`                  Float64:`,
+					`                    description: 'From $ref: #/components/schemas/MyFloat64'`,
+					`                    type: number`,
+					`                    format: double`,
+					`                  Int:`,
+					`                    description: 'From $ref: #/components/schemas/MyInt'`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Int16:`,
+					`                    description: 'From $ref: #/components/schemas/MyInt16'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Int32:`,
+					`                    description: 'From $ref: #/components/schemas/MyInt32'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Int64:`,
+					`                    description: 'From $ref: #/components/schemas/MyInt64'`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Int8:`,
+					`                    description: 'From $ref: #/components/schemas/MyInt8'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Interface:`,
+					`                    description: 'From $ref: #/components/schemas/MyInterface;ERROR=interface element is nil'`,
+					`                    type: string`,
+					`                  Map:`,
+					`                    description: 'From $ref: #/components/schemas/MyMap;ERROR=map key type must be string'`,
+					`                    type: object`,
+					`                    additionalProperties: true`,
+					`                  PrivatePtr:`,
+					`                    description: 'From $ref: #/components/schemas/PrivateStruct;ERROR=struct has no exported fields'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                  Ptr:`,
+					`                    description: 'From $ref: #/components/schemas/StringStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      Value:`,
+					`                        type: string`,
+					`                  Slice:`,
+					`                    description: 'From $ref: #/components/schemas/MySlice'`,
+					`                    type: array`,
+					`                    items:`,
+					`                      description: 'ERROR=interface element is nil'`,
+					`                      type: string`,
+					`                  String:`,
+					`                    description: 'From $ref: #/components/schemas/MyString'`,
+					`                    type: string`,
+					`                  Struct:`,
+					`                    description: 'From $ref: #/components/schemas/MyStruct;ERROR=empty struct not supported'`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                  Uint:`,
+					`                    description: 'From $ref: #/components/schemas/MyUint'`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Uint16:`,
+					`                    description: 'From $ref: #/components/schemas/MyUint16'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uint32:`,
+					`                    description: 'From $ref: #/components/schemas/MyUint32'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uint64:`,
+					`                    description: 'From $ref: #/components/schemas/MyUint64'`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  Uint8:`,
+					`                    description: 'From $ref: #/components/schemas/MyUint8'`,
+					`                    type: integer`,
+					`                    format: int32`,
+					`                  Uintptr:`,
+					`                    description: 'From $ref: #/components/schemas/MyUintptr'`,
+					`                    type: integer`,
+					`                    format: int64`,
+				},
+			},
+		},
+	},
+}
+
+type ArrayStruct struct {
+	Array0   [0]string
+	Array3   [3]string
+	Array2_3 [2][3]string
+}
+
+type SliceStruct struct {
+	Slice  []string
+	Array2 [][]string
+}
+
+var jsonArrayTest = `
+{
+	"Array0": [],
+	"Array3": ["a","b","c"],
+	"Array2_3": [
+		[1,2,3],
+		[2,3,4]
+	]
+}
+`
+
+// Array tests.
+var listTests = []fixtures.TestCase{
+	{
+		Name:  "arrays",
+		Value: &ArrayStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ArrayStruct`,
+					`TypeRef.ArrayStruct:{}`,
+					`TypeRef.ArrayStruct:{}.Array0:[]`,
+					`TypeRef.ArrayStruct:{}.Array0:[].string`,
+					`TypeRef.ArrayStruct:{}.Array2_3:[]`,
+					`TypeRef.ArrayStruct:{}.Array2_3:[].[]`,
+					`TypeRef.ArrayStruct:{}.Array2_3:[].[].string`,
+					`TypeRef.ArrayStruct:{}.Array3:[]`,
+					`TypeRef.ArrayStruct:{}.Array3:[].string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Array0:[]`,
+					`Root.{}.Array0:[].string`,
+					`Root.{}.Array2_3:[]`,
+					`Root.{}.Array2_3:[].[]`,
+					`Root.{}.Array2_3:[].[].string`,
+					`Root.{}.Array3:[]`,
+					`Root.{}.Array3:[].string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: arrays`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/arrays:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                nullable: true`,
+					`                allOf:`,
+					`                - $ref: '#/components/schemas/ArrayStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    ArrayStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Array0:`,
+					`          type: array`,
+					`          minItems: 0`,
+					`          maxItems: 0`,
+					`          items:`,
+					`            type: string`,
+					`        Array2_3:`,
+					`          type: array`,
+					`          minItems: 2`,
+					`          maxItems: 2`,
+					`          items:`,
+					`            type: array`,
+					`            minItems: 3`,
+					`            maxItems: 3`,
+					`            items:`,
+					`              type: string`,
+					`        Array3:`,
+					`          type: array`,
+					`          minItems: 3`,
+					`          maxItems: 3`,
+					`          items:`,
+					`            type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: arrays`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/arrays:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/ArrayStruct'`,
+					`                nullable: true`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array0:`,
+					`                    type: array`,
+					`                    minItems: 0`,
+					`                    maxItems: 0`,
+					`                    items:`,
+					`                      type: string`,
+					`                  Array2_3:`,
+					`                    type: array`,
+					`                    minItems: 2`,
+					`                    maxItems: 2`,
+					`                    items:`,
+					`                      type: array`,
+					`                      minItems: 3`,
+					`                      maxItems: 3`,
+					`                      items:`,
+					`                        type: string`,
+					`                  Array3:`,
+					`                    type: array`,
+					`                    minItems: 3`,
+					`                    maxItems: 3`,
+					`                    items:`,
+					`                      type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "json-array",
+		Value: fromJSON([]byte(jsonArrayTest)),
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}`,
+					`Root.{}.Array0:[]`,
+					`Root.{}.Array0:[].!invalid! ERROR:interface element is nil`,
+					`Root.{}.Array2_3:[]`,
+					`Root.{}.Array2_3:[].[]`,
+					`Root.{}.Array2_3:[].[].float`,
+					`Root.{}.Array3:[]`,
+					`Root.{}.Array3:[].string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Array0:[]`,
+					`Root.{}.Array0:[].!invalid! ERROR:interface element is nil`,
+					`Root.{}.Array2_3:[]`,
+					`Root.{}.Array2_3:[].[]`,
+					`Root.{}.Array2_3:[].[].float`,
+					`Root.{}.Array3:[]`,
+					`Root.{}.Array3:[].string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-array`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/json-array:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array0:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      description: 'ERROR=interface element is nil'`,
+					`                      type: string`,
+					`                  Array2_3:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      nullable: true`,
+					`                      type: array`,
+					`                      items:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                  Array3:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      nullable: true`,
+					`                      type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-array`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/json-array:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array0:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      description: 'ERROR=interface element is nil'`,
+					`                      type: string`,
+					`                  Array2_3:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      nullable: true`,
+					`                      type: array`,
+					`                      items:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                  Array3:`,
+					`                    nullable: true`,
+					`                    type: array`,
+					`                    items:`,
+					`                      nullable: true`,
+					`                      type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "slices",
+		Value: &SliceStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:SliceStruct`,
+					`TypeRef.SliceStruct:{}`,
+					`TypeRef.SliceStruct:{}.Array2:[]`,
+					`TypeRef.SliceStruct:{}.Array2:[].[]`,
+					`TypeRef.SliceStruct:{}.Array2:[].[].string`,
+					`TypeRef.SliceStruct:{}.Slice:[]`,
+					`TypeRef.SliceStruct:{}.Slice:[].string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Array2:[]`,
+					`Root.{}.Array2:[].[]`,
+					`Root.{}.Array2:[].[].string`,
+					`Root.{}.Slice:[]`,
+					`Root.{}.Slice:[].string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: slices`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/slices:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                nullable: true`,
+					`                allOf:`,
+					`                - $ref: '#/components/schemas/SliceStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    SliceStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        Array2:`,
+					`          type: array`,
+					`          items:`,
+					`            type: array`,
+					`            items:`,
+					`              type: string`,
+					`        Slice:`,
+					`          type: array`,
+					`          items:`,
+					`            type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: slices`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /04-list/slices:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/SliceStruct'`,
+					`                nullable: true`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  Array2:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      type: array`,
+					`                      items:`,
+					`                        type: string`,
+					`                  Slice:`,
+					`                    type: array`,
+					`                    items:`,
+					`                      type: string`,
+				},
+			},
+		},
+	},
+}
+
+type MapTestsStruct struct {
+	MapOK struct {
+		StringVal string
+		IntVal    float64
+		FloatVal  float32
+		BoolVal   bool
+		ListVal   []float64
+		MapVal    struct {
+			Key1 string
+			Key2 struct {
+				DeepKey1 string
+				DeepKey2 float64
+			}
+		}
+	}
+}
+
+var jsonMapTests = `
+{
+	"MapOK": {
+		"StringVal": "Hello",
+		"IntVal": 123,
+		"FloatVal": 234.345,
+		"BoolVal": true,
+		"ListVal": [2,3,4,5],
+		"MapVal": {
+			"Key1": "Hey",
+			"Key2": {
+				"DeepKey1": "Hi",
+				"DeepKey2": 234
+			}
+		}
+	}
+}
+`
+
+type MapValueStruct struct {
+	IntMap       map[string]int64
+	StructPtrMap map[string]*BasicStruct
+	AnyMap       map[string]interface{}
+}
+
+var compoundTests = []fixtures.TestCase{
+	{
+		Name:  "map-values",
+		Value: MapValueStruct{},
+		Want: map[string]fixtures.WantSet{
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: map-values`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/map-values:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/MapValueStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    BasicStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        BoolVal:`,
+					`          type: boolean`,
+					`        Float64Val:`,
+					`          type: number`,
+					`          format: double`,
+					`        IntVal:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        StringVal:`,
+					`          type: string`,
+					`    MapValueStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        AnyMap:`,
+					`          type: object`,
+					`          additionalProperties: true`,
+					`        IntMap:`,
+					`          type: object`,
+					`          additionalProperties:`,
+					`            type: integer`,
+					`            format: int64`,
+					`        StructPtrMap:`,
+					`          type: object`,
+					`          additionalProperties:`,
+					`            nullable: true`,
+					`            allOf:`,
+					`            - $ref: '#/components/schemas/BasicStruct'`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: map-values`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/map-values:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/MapValueStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  AnyMap:`,
+					`                    type: object`,
+					`                    additionalProperties: true`,
+					`                  IntMap:`,
+					`                    type: object`,
+					`                    additionalProperties:`,
+					`                      type: integer`,
+					`                      format: int64`,
+					`                  StructPtrMap:`,
+					`                    type: object`,
+					`                    additionalProperties:`,
+					`                      description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                      nullable: true`,
+					`                      type: object`,
+					`                      additionalProperties: false`,
+					`                      properties:`,
+					`                        BoolVal:`,
+					`                          type: boolean`,
+					`                        Float64Val:`,
+					`                          type: number`,
+					`                          format: double`,
+					`                        IntVal:`,
+					`                          type: integer`,
+					`                          format: int64`,
+					`                        StringVal:`,
+					`                          type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "golang-map",
+		Value: MapTestsStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:MapTestsStruct`,
+					`TypeRef.MapTestsStruct:{}`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.BoolVal:boolean`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.FloatVal:float`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.IntVal:float`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.ListVal:[]`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.ListVal:[].float`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key1:string`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
+					`TypeRef.MapTestsStruct:{}.MapOK:{}.StringVal:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.MapOK:{}`,
+					`Root.{}.MapOK:{}.BoolVal:boolean`,
+					`Root.{}.MapOK:{}.FloatVal:float`,
+					`Root.{}.MapOK:{}.IntVal:float`,
+					`Root.{}.MapOK:{}.ListVal:[]`,
+					`Root.{}.MapOK:{}.ListVal:[].float`,
+					`Root.{}.MapOK:{}.MapVal:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
+					`Root.{}.MapOK:{}.StringVal:string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: golang-map`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/golang-map:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/MapTestsStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    MapTestsStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        MapOK:`,
+					`          type: object`,
+					`          additionalProperties: false`,
+					`          properties:`,
+					`            BoolVal:`,
+					`              type: boolean`,
+					`            FloatVal:`,
+					`              type: number`,
+					`              format: float`,
+					`            IntVal:`,
+					`              type: number`,
+					`              format: double`,
+					`            ListVal:`,
+					`              type: array`,
+					`              items:`,
+					`                type: number`,
+					`                format: double`,
+					`            MapVal:`,
+					`              type: object`,
+					`              additionalProperties: false`,
+					`              properties:`,
+					`                Key1:`,
+					`                  type: string`,
+					`                Key2:`,
+					`                  type: object`,
+					`                  additionalProperties: false`,
+					`                  properties:`,
+					`                    DeepKey1:`,
+					`                      type: string`,
+					`                    DeepKey2:`,
+					`                      type: number`,
+					`                      format: double`,
+					`            StringVal:`,
+					`              type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: golang-map`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/golang-map:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/MapTestsStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  MapOK:`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      FloatVal:`,
+					`                        type: number`,
+					`                        format: float`,
+					`                      IntVal:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      ListVal:`,
+					`                        type: array`,
+					`                        items:`,
+					`                          type: number`,
+					`                          format: double`,
+					`                      MapVal:`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          Key1:`,
+					`                            type: string`,
+					`                          Key2:`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              DeepKey1:`,
+					`                                type: string`,
+					`                              DeepKey2:`,
+					`                                type: number`,
+					`                                format: double`,
+					`                      StringVal:`,
+					`                        type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "json-map",
+		Value: fromJSON([]byte(jsonMapTests)),
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}`,
+					`Root.{}.MapOK:{}`,
+					`Root.{}.MapOK:{}.BoolVal:boolean`,
+					`Root.{}.MapOK:{}.FloatVal:float`,
+					`Root.{}.MapOK:{}.IntVal:float`,
+					`Root.{}.MapOK:{}.ListVal:[]`,
+					`Root.{}.MapOK:{}.ListVal:[].float`,
+					`Root.{}.MapOK:{}.MapVal:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
+					`Root.{}.MapOK:{}.StringVal:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.MapOK:{}`,
+					`Root.{}.MapOK:{}.BoolVal:boolean`,
+					`Root.{}.MapOK:{}.FloatVal:float`,
+					`Root.{}.MapOK:{}.IntVal:float`,
+					`Root.{}.MapOK:{}.ListVal:[]`,
+					`Root.{}.MapOK:{}.ListVal:[].float`,
+					`Root.{}.MapOK:{}.MapVal:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey1:string`,
+					`Root.{}.MapOK:{}.MapVal:{}.Key2:{}.DeepKey2:float`,
+					`Root.{}.MapOK:{}.StringVal:string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-map`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/json-map:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  MapOK:`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        nullable: true`,
+					`                        type: boolean`,
+					`                      FloatVal:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      ListVal:`,
+					`                        nullable: true`,
+					`                        type: array`,
+					`                        items:`,
+					`                          nullable: true`,
+					`                          type: number`,
+					`                          format: double`,
+					`                      MapVal:`,
+					`                        nullable: true`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          Key1:`,
+					`                            nullable: true`,
+					`                            type: string`,
+					`                          Key2:`,
+					`                            nullable: true`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              DeepKey1:`,
+					`                                nullable: true`,
+					`                                type: string`,
+					`                              DeepKey2:`,
+					`                                nullable: true`,
+					`                                type: number`,
+					`                                format: double`,
+					`                      StringVal:`,
+					`                        nullable: true`,
+					`                        type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-map`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /05-compound/json-map:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  MapOK:`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        nullable: true`,
+					`                        type: boolean`,
+					`                      FloatVal:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        nullable: true`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      ListVal:`,
+					`                        nullable: true`,
+					`                        type: array`,
+					`                        items:`,
+					`                          nullable: true`,
+					`                          type: number`,
+					`                          format: double`,
+					`                      MapVal:`,
+					`                        nullable: true`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          Key1:`,
+					`                            nullable: true`,
+					`                            type: string`,
+					`                          Key2:`,
+					`                            nullable: true`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              DeepKey1:`,
+					`                                nullable: true`,
+					`                                type: string`,
+					`                              DeepKey2:`,
+					`                                nullable: true`,
+					`                                type: number`,
+					`                                format: double`,
+					`                      StringVal:`,
+					`                        nullable: true`,
+					`                        type: string`,
+				},
+			},
+		},
+	},
+}
+
+type ReferenceTestsStruct struct {
+	InterfaceVal interface{}
+	PtrVal       *BasicStruct
+	PtrPtrVal    **BasicStruct
+}
+
+type NullablePtrStruct struct {
+	BasicPtr  *BasicStruct
+	StringPtr *string
+}
+
+type EmbeddedBase struct {
+	ID      int    `json:"id"`
+	Version string `json:"version"`
+}
+
+// EmbeddedPromoted has fields of EmbeddedBase promoted, Version is shadowed.
+type EmbeddedPromoted struct {
+	EmbeddedBase
+	Data    string `json:"data"`
+	Version int    `json:"version"`
+}
+
+// EmbeddedNamed keeps EmbeddedBase nested because of the json name.
+type EmbeddedNamed struct {
+	*EmbeddedBase `json:"base"`
+	Data          string `json:"data"`
+}
+
+var referenceTests = []fixtures.TestCase{
+	{
+		Name:  "embedded-promoted",
+		Value: EmbeddedPromoted{},
+		Want: map[string]fixtures.WantSet{
+			"markdown": map[bool][]string{
+				false: []string{
+					`# Root`,
+					`## 06-reference/embedded-promoted`,
+					`Type: struct (EmbeddedPromoted)`,
+					`# TypeRef`,
+					`## EmbeddedPromoted`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| data | string | yes | no |  |`,
+					`| id | integer | yes | no |  |`,
+					`| version | integer | yes | no |  |`,
+				},
+				true: []string{
+					`# Root`,
+					`## 06-reference/embedded-promoted`,
+					`Type: struct (EmbeddedPromoted)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| data | string | yes | no |  |`,
+					`| id | integer | yes | no |  |`,
+					`| version | integer | yes | no |  |`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: embedded-promoted`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/embedded-promoted:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/EmbeddedPromoted'`,
+					`components:`,
+					`  schemas:`,
+					`    EmbeddedPromoted:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        data:`,
+					`          type: string`,
+					`        id:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        version:`,
+					`          type: integer`,
+					`          format: int64`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: embedded-promoted`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/embedded-promoted:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/EmbeddedPromoted'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  data:`,
+					`                    type: string`,
+					`                  id:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  version:`,
+					`                    type: integer`,
+					`                    format: int64`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:EmbeddedPromoted`,
+					`TypeRef.EmbeddedPromoted:{}`,
+					`TypeRef.EmbeddedPromoted:{}.Data:string`,
+					`TypeRef.EmbeddedPromoted:{}.ID:integer`,
+					`TypeRef.EmbeddedPromoted:{}.Version:integer`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Data:string`,
+					`Root.{}.ID:integer`,
+					`Root.{}.Version:integer`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface EmbeddedPromoted {`,
+					`  data: string;`,
+					`  id: number;`,
+					`  version: number;`,
+					`}`,
+				},
+				true: []string{
+					`export interface EmbeddedPromoted {`,
+					`  data: string;`,
+					`  id: number;`,
+					`  version: number;`,
+					`}`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "embedded-named",
+		Value: EmbeddedNamed{},
+		Want: map[string]fixtures.WantSet{
+			"markdown": map[bool][]string{
+				false: []string{
+					`# Root`,
+					`## 06-reference/embedded-named`,
+					`Type: struct (EmbeddedNamed)`,
+					`# TypeRef`,
+					`## EmbeddedBase`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| id | integer | yes | no |  |`,
+					`| version | string | yes | no |  |`,
+					`## EmbeddedNamed`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| data | string | yes | no |  |`,
+					`| base | struct (EmbeddedBase) | no | yes |  |`,
+				},
+				true: []string{
+					`# Root`,
+					`## 06-reference/embedded-named`,
+					`Type: struct (EmbeddedNamed)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| data | string | yes | no |  |`,
+					`| base | struct (EmbeddedBase) | no | yes |  |`,
+					`| base.id | integer | yes | no |  |`,
+					`| base.version | string | yes | no |  |`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: embedded-named`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/embedded-named:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/EmbeddedNamed'`,
+					`components:`,
+					`  schemas:`,
+					`    EmbeddedBase:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        id:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        version:`,
+					`          type: string`,
+					`    EmbeddedNamed:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        data:`,
+					`          type: string`,
+					`        base:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/EmbeddedBase'`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: embedded-named`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/embedded-named:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/EmbeddedNamed'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  data:`,
+					`                    type: string`,
+					`                  base:`,
+					`                    description: 'From $ref: #/components/schemas/EmbeddedBase'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      id:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      version:`,
+					`                        type: string`,
+				},
+			},
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:EmbeddedNamed`,
+					`TypeRef.EmbeddedBase:{}`,
+					`TypeRef.EmbeddedBase:{}.ID:integer`,
+					`TypeRef.EmbeddedBase:{}.Version:string`,
+					`TypeRef.EmbeddedNamed:{}`,
+					`TypeRef.EmbeddedNamed:{}.Data:string`,
+					`TypeRef.EmbeddedNamed:{}.EmbeddedBase:{}:EmbeddedBase`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.Data:string`,
+					`Root.{}.EmbeddedBase:{}`,
+					`Root.{}.EmbeddedBase:{}.ID:integer`,
+					`Root.{}.EmbeddedBase:{}.Version:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface EmbeddedBase {`,
+					`  id: number;`,
+					`  version: string;`,
+					`}`,
+					`export interface EmbeddedNamed {`,
+					`  data: string;`,
+					`  base?: EmbeddedBase;`,
+					`}`,
+				},
+				true: []string{
+					`export interface EmbeddedNamed {`,
+					`  data: string;`,
+					`  base?: {`,
+					`    id: number;`,
+					`    version: string;`,
+					`  };`,
+					`}`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "nullable-pointer",
+		Value: NullablePtrStruct{},
+		Want: map[string]fixtures.WantSet{
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: nullable-pointer`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/nullable-pointer:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/NullablePtrStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    BasicStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        BoolVal:`,
+					`          type: boolean`,
+					`        Float64Val:`,
+					`          type: number`,
+					`          format: double`,
+					`        IntVal:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        StringVal:`,
+					`          type: string`,
+					`    NullablePtrStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        BasicPtr:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BasicStruct'`,
+					`        StringPtr:`,
+					`          nullable: true`,
+					`          type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: nullable-pointer`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/nullable-pointer:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/NullablePtrStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  BasicPtr:`,
+					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      Float64Val:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      StringVal:`,
+					`                        type: string`,
+					`                  StringPtr:`,
+					`                    nullable: true`,
+					`                    type: string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "reference-tests-empty",
+		Value: ReferenceTestsStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ReferenceTestsStruct`,
+					`TypeRef.BasicStruct:{}`,
+					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+					`TypeRef.BasicStruct:{}.Float64Val:float`,
+					`TypeRef.BasicStruct:{}.IntVal:integer`,
+					`TypeRef.BasicStruct:{}.StringVal:string`,
+					`TypeRef.ReferenceTestsStruct:{}`,
+					`TypeRef.ReferenceTestsStruct:{}.!InterfaceVal:invalid! ERROR:interface element is nil`,
+					`TypeRef.ReferenceTestsStruct:{}.PtrPtrVal:{}:BasicStruct`,
+					`TypeRef.ReferenceTestsStruct:{}.PtrVal:{}:BasicStruct`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.!InterfaceVal:invalid! ERROR:interface element is nil`,
+					`Root.{}.PtrPtrVal:{}`,
+					`Root.{}.PtrPtrVal:{}.BoolVal:boolean`,
+					`Root.{}.PtrPtrVal:{}.Float64Val:float`,
+					`Root.{}.PtrPtrVal:{}.IntVal:integer`,
+					`Root.{}.PtrPtrVal:{}.StringVal:string`,
+					`Root.{}.PtrVal:{}`,
+					`Root.{}.PtrVal:{}.BoolVal:boolean`,
+					`Root.{}.PtrVal:{}.Float64Val:float`,
+					`Root.{}.PtrVal:{}.IntVal:integer`,
+					`Root.{}.PtrVal:{}.StringVal:string`,
+				},
+			},
+		},
+	},
+	{
+		Name:  "reference-tests-init",
+		Value: ReferenceTestsStruct{InterfaceVal: &BasicStruct{}},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:ReferenceTestsStruct`,
+					`TypeRef.BasicStruct:{}`,
+					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+					`TypeRef.BasicStruct:{}.Float64Val:float`,
+					`TypeRef.BasicStruct:{}.IntVal:integer`,
+					`TypeRef.BasicStruct:{}.StringVal:string`,
+					`TypeRef.ReferenceTestsStruct:{}`,
+					`TypeRef.ReferenceTestsStruct:{}.InterfaceVal:{}:BasicStruct`,
+					`TypeRef.ReferenceTestsStruct:{}.PtrPtrVal:{}:BasicStruct`,
+					`TypeRef.ReferenceTestsStruct:{}.PtrVal:{}:BasicStruct`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.InterfaceVal:{}`,
+					`Root.{}.InterfaceVal:{}.BoolVal:boolean`,
+					`Root.{}.InterfaceVal:{}.Float64Val:float`,
+					`Root.{}.InterfaceVal:{}.IntVal:integer`,
+					`Root.{}.InterfaceVal:{}.StringVal:string`,
+					`Root.{}.PtrPtrVal:{}`,
+					`Root.{}.PtrPtrVal:{}.BoolVal:boolean`,
+					`Root.{}.PtrPtrVal:{}.Float64Val:float`,
+					`Root.{}.PtrPtrVal:{}.IntVal:integer`,
+					`Root.{}.PtrPtrVal:{}.StringVal:string`,
+					`Root.{}.PtrVal:{}`,
+					`Root.{}.PtrVal:{}.BoolVal:boolean`,
+					`Root.{}.PtrVal:{}.Float64Val:float`,
+					`Root.{}.PtrVal:{}.IntVal:integer`,
+					`Root.{}.PtrVal:{}.StringVal:string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: reference-tests-init`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/reference-tests-init:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/ReferenceTestsStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    BasicStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        BoolVal:`,
+					`          type: boolean`,
+					`        Float64Val:`,
+					`          type: number`,
+					`          format: double`,
+					`        IntVal:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        StringVal:`,
+					`          type: string`,
+					`    ReferenceTestsStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        InterfaceVal:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BasicStruct'`,
+					`        PtrPtrVal:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BasicStruct'`,
+					`        PtrVal:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BasicStruct'`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: reference-tests-init`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /06-reference/reference-tests-init:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/ReferenceTestsStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  InterfaceVal:`,
+					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      Float64Val:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      StringVal:`,
+					`                        type: string`,
+					`                  PtrPtrVal:`,
+					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      Float64Val:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      StringVal:`,
+					`                        type: string`,
+					`                  PtrVal:`,
+					`                    description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      BoolVal:`,
+					`                        type: boolean`,
+					`                      Float64Val:`,
+					`                        type: number`,
+					`                        format: double`,
+					`                      IntVal:`,
+					`                        type: integer`,
+					`                        format: int64`,
+					`                      StringVal:`,
+					`                        type: string`,
+				},
+			},
+		},
+	},
+}
+
+// Test cyclical relationships:
+// A --> B --> C --> A
+type AStruct struct {
+	AName  string   `json:"aName,omitempty"`
+	AChild *BStruct `json:"aChild"`
+}
+
+type BStruct struct {
+	BName  string   `json:"bName"`
+	BChild *CStruct `json:"bChild"`
+}
+
+type CStruct struct {
+	CName  string   `json:"cName"`
+	CChild *AStruct `json:"cChild"`
+}
+
+type BadType interface{}
+
+type CycleTest struct {
+	Level  int      `json:"-"`
+	CycleA AStruct  `json:"cycleA"`
+	CycleB *BStruct `json:"cycleB"`
+	CycleC struct {
+		C CStruct `json:"c"`
+	}
+}
+
+var cycleTests = []fixtures.TestCase{
+	{
+		Name:  "cycle-test",
+		Value: &CycleTest{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:CycleTest`,
+					`TypeRef.AStruct:{}`,
+					`TypeRef.AStruct:{}.AChild:{}:BStruct`,
+					`TypeRef.AStruct:{}.AName:string`,
+					`TypeRef.BStruct:{}`,
+					`TypeRef.BStruct:{}.BChild:{}:CStruct`,
+					`TypeRef.BStruct:{}.BName:string`,
+					`TypeRef.CStruct:{}`,
+					`TypeRef.CStruct:{}.CChild:{}:AStruct`,
+					`TypeRef.CStruct:{}.CName:string`,
+					`TypeRef.CycleTest:{}`,
+					`TypeRef.CycleTest:{}.CycleA:{}:AStruct`,
+					`TypeRef.CycleTest:{}.CycleB:{}:BStruct`,
+					`TypeRef.CycleTest:{}.CycleC:{}`,
+					`TypeRef.CycleTest:{}.CycleC:{}.C:{}:CStruct`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.CycleA:{}`,
+					`Root.{}.CycleA:{}.AChild:{}`,
+					`Root.{}.CycleA:{}.AChild:{}.BChild:{}`,
+					`Root.{}.CycleA:{}.AChild:{}.BChild:{}.!CChild:{}:AStruct! ERROR:cyclical reference`,
+					`Root.{}.CycleA:{}.AChild:{}.BChild:{}.CName:string`,
+					`Root.{}.CycleA:{}.AChild:{}.BName:string`,
+					`Root.{}.CycleA:{}.AName:string`,
+					`Root.{}.CycleB:{}`,
+					`Root.{}.CycleB:{}.BChild:{}`,
+					`Root.{}.CycleB:{}.BChild:{}.CChild:{}`,
+					`Root.{}.CycleB:{}.BChild:{}.CChild:{}.!AChild:{}:BStruct! ERROR:cyclical reference`,
+					`Root.{}.CycleB:{}.BChild:{}.CChild:{}.AName:string`,
+					`Root.{}.CycleB:{}.BChild:{}.CName:string`,
+					`Root.{}.CycleB:{}.BName:string`,
+					`Root.{}.CycleC:{}`,
+					`Root.{}.CycleC:{}.C:{}`,
+					`Root.{}.CycleC:{}.C:{}.CChild:{}`,
+					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}`,
+					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}.!BChild:{}:CStruct! ERROR:cyclical reference`,
+					`Root.{}.CycleC:{}.C:{}.CChild:{}.AChild:{}.BName:string`,
+					`Root.{}.CycleC:{}.C:{}.CChild:{}.AName:string`,
+					`Root.{}.CycleC:{}.C:{}.CName:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface AStruct {`,
+					`  aChild?: BStruct;`,
+					`  aName?: string;`,
+					`}`,
+					`export interface BStruct {`,
+					`  bChild?: CStruct;`,
+					`  bName: string;`,
+					`}`,
+					`export interface CStruct {`,
+					`  cChild?: AStruct;`,
+					`  cName: string;`,
+					`}`,
+					`export interface CycleTest {`,
+					`  cycleA: AStruct;`,
+					`  cycleB?: BStruct;`,
+					`  CycleC: {`,
+					`    c: CStruct;`,
+					`  };`,
+					`}`,
+				},
+				true: []string{
+					`export interface CycleTest {`,
+					`  cycleA: {`,
+					`    aChild?: {`,
+					`      bChild?: {`,
+					`        // ERROR: cyclical reference`,
+					`        cChild?: AStruct;`,
+					`        cName: string;`,
+					`      };`,
+					`      bName: string;`,
+					`    };`,
+					`    aName?: string;`,
+					`  };`,
+					`  cycleB?: {`,
+					`    bChild?: {`,
+					`      cChild?: {`,
+					`        // ERROR: cyclical reference`,
+					`        aChild?: BStruct;`,
+					`        aName?: string;`,
+					`      };`,
+					`      cName: string;`,
+					`    };`,
+					`    bName: string;`,
+					`  };`,
+					`  CycleC: {`,
+					`    c: {`,
+					`      cChild?: {`,
+					`        aChild?: {`,
+					`          // ERROR: cyclical reference`,
+					`          bChild?: CStruct;`,
+					`          bName: string;`,
+					`        };`,
+					`        aName?: string;`,
+					`      };`,
+					`      cName: string;`,
+					`    };`,
+					`  };`,
+					`}`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: cycle-test`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /07-cycle/cycle-test:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                nullable: true`,
+					`                allOf:`,
+					`                - $ref: '#/components/schemas/CycleTest'`,
+					`components:`,
+					`  schemas:`,
+					`    AStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        aChild:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BStruct'`,
+					`        aName:`,
+					`          type: string`,
+					`    BStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        bChild:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/CStruct'`,
+					`        bName:`,
+					`          type: string`,
+					`    CStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        cChild:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/AStruct'`,
+					`        cName:`,
+					`          type: string`,
+					`    CycleTest:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        cycleA:`,
+					`          $ref: '#/components/schemas/AStruct'`,
+					`        cycleB:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/BStruct'`,
+					`        CycleC:`,
+					`          type: object`,
+					`          additionalProperties: false`,
+					`          properties:`,
+					`            c:`,
+					`              $ref: '#/components/schemas/CStruct'`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: cycle-test`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /07-cycle/cycle-test:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/CycleTest'`,
+					`                nullable: true`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  cycleA:`,
+					`                    description: 'From $ref: #/components/schemas/AStruct'`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      aChild:`,
+					`                        description: 'From $ref: #/components/schemas/BStruct'`,
+					`                        nullable: true`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          bChild:`,
+					`                            description: 'From $ref: #/components/schemas/CStruct'`,
+					`                            nullable: true`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              cChild:`,
+					`                                description: 'From $ref: #/components/schemas/AStruct;ERROR=cyclical reference'`,
+					`                                nullable: true`,
+					`                                type: object`,
+					`                                additionalProperties: false`,
+					`                              cName:`,
+					`                                type: string`,
+					`                          bName:`,
+					`                            type: string`,
+					`                      aName:`,
+					`                        type: string`,
+					`                  cycleB:`,
+					`                    description: 'From $ref: #/components/schemas/BStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      bChild:`,
+					`                        description: 'From $ref: #/components/schemas/CStruct'`,
+					`                        nullable: true`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          cChild:`,
+					`                            description: 'From $ref: #/components/schemas/AStruct'`,
+					`                            nullable: true`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              aChild:`,
+					`                                description: 'From $ref: #/components/schemas/BStruct;ERROR=cyclical reference'`,
+					`                                nullable: true`,
+					`                                type: object`,
+					`                                additionalProperties: false`,
+					`                              aName:`,
+					`                                type: string`,
+					`                          cName:`,
+					`                            type: string`,
+					`                      bName:`,
+					`                        type: string`,
+					`                  CycleC:`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      c:`,
+					`                        description: 'From $ref: #/components/schemas/CStruct'`,
+					`                        type: object`,
+					`                        additionalProperties: false`,
+					`                        properties:`,
+					`                          cChild:`,
+					`                            description: 'From $ref: #/components/schemas/AStruct'`,
+					`                            nullable: true`,
+					`                            type: object`,
+					`                            additionalProperties: false`,
+					`                            properties:`,
+					`                              aChild:`,
+					`                                description: 'From $ref: #/components/schemas/BStruct'`,
+					`                                nullable: true`,
+					`                                type: object`,
+					`                                additionalProperties: false`,
+					`                                properties:`,
+					`                                  bChild:`,
+					`                                    description: 'From $ref: #/components/schemas/CStruct;ERROR=cyclical reference'`,
+					`                                    nullable: true`,
+					`                                    type: object`,
+					`                                    additionalProperties: false`,
+					`                                  bName:`,
+					`                                    type: string`,
+					`                              aName:`,
+					`                                type: string`,
+					`                          cName:`,
+					`                            type: string`,
+				},
+			},
+		},
+	},
+}
+
+type JSONTagTests struct {
+	NoTag      string
+	ExcludeTag string `json:"-"`
+	RenameOne  string `json:"renameOne"`
+	RenameTwo  string `json:"something"`
+}
+
+var jsonTagTests = []fixtures.TestCase{
+	{
+		Name:  "json-tags",
+		Value: JSONTagTests{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:JSONTagTests`,
+					`TypeRef.JSONTagTests:{}`,
+					`TypeRef.JSONTagTests:{}.NoTag:string`,
+					`TypeRef.JSONTagTests:{}.RenameOne:string`,
+					`TypeRef.JSONTagTests:{}.RenameTwo:string`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.NoTag:string`,
+					`Root.{}.RenameOne:string`,
+					`Root.{}.RenameTwo:string`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-tags`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /08-json-tag/json-tags:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/JSONTagTests'`,
+					`components:`,
+					`  schemas:`,
+					`    JSONTagTests:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        NoTag:`,
+					`          type: string`,
+					`        renameOne:`,
+					`          type: string`,
+					`        something:`,
+					`          type: string`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: json-tags`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /08-json-tag/json-tags:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/JSONTagTests'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  NoTag:`,
+					`                    type: string`,
+					`                  renameOne:`,
+					`                    type: string`,
+					`                  something:`,
+					`                    type: string`,
+				},
+			},
+		},
+	},
+}
+
+var nestedTests = []fixtures.TestCase{
+	{
+		Name:  "nested-struct",
+		Value: OuterStruct{},
+		Want: map[string]fixtures.WantSet{
+			"simple": map[bool][]string{
+				false: []string{
+					`Root.{}:OuterStruct`,
+					`TypeRef.BasicStruct:{}`,
+					`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+					`TypeRef.BasicStruct:{}.Float64Val:float`,
+					`TypeRef.BasicStruct:{}.IntVal:integer`,
+					`TypeRef.BasicStruct:{}.StringVal:string`,
+					`TypeRef.InnerStruct:{}`,
+					`TypeRef.InnerStruct:{}.ListOfStrings:[]`,
+					`TypeRef.InnerStruct:{}.ListOfStrings:[].string`,
+					`TypeRef.InnerStruct:{}.ListOfStructs:[]`,
+					`TypeRef.InnerStruct:{}.ListOfStructs:[].{}:BasicStruct`,
+					`TypeRef.OuterStruct:{}`,
+					`TypeRef.OuterStruct:{}.ID:integer`,
+					`TypeRef.OuterStruct:{}.Inner:{}:InnerStruct`,
+				},
+				true: []string{
+					`Root.{}`,
+					`Root.{}.ID:integer`,
+					`Root.{}.Inner:{}`,
+					`Root.{}.Inner:{}.ListOfStrings:[]`,
+					`Root.{}.Inner:{}.ListOfStrings:[].string`,
+					`Root.{}.Inner:{}.ListOfStructs:[]`,
+					`Root.{}.Inner:{}.ListOfStructs:[].{}`,
+					`Root.{}.Inner:{}.ListOfStructs:[].{}.BoolVal:boolean`,
+					`Root.{}.Inner:{}.ListOfStructs:[].{}.Float64Val:float`,
+					`Root.{}.Inner:{}.ListOfStructs:[].{}.IntVal:integer`,
+					`Root.{}.Inner:{}.ListOfStructs:[].{}.StringVal:string`,
+				},
+			},
+			"typescript": map[bool][]string{
+				false: []string{
+					`export interface BasicStruct {`,
+					`  BoolVal: boolean;`,
+					`  Float64Val: number;`,
+					`  IntVal: number;`,
+					`  StringVal: string;`,
+					`}`,
+					`export interface InnerStruct {`,
+					`  listOfStrings: string[];`,
+					`  listOfStructs: BasicStruct[];`,
+					`}`,
+					`export interface OuterStruct {`,
+					`  id: number;`,
+					`  inner?: InnerStruct;`,
+					`}`,
+				},
+				true: []string{
+					`export interface OuterStruct {`,
+					`  id: number;`,
+					`  inner?: {`,
+					`    listOfStrings: string[];`,
+					`    listOfStructs: {`,
+					`      BoolVal: boolean;`,
+					`      Float64Val: number;`,
+					`      IntVal: number;`,
+					`      StringVal: string;`,
+					`    }[];`,
+					`  };`,
+					`}`,
+				},
+			},
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: nested-struct`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /09-nested/nested-struct:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/OuterStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    BasicStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        BoolVal:`,
+					`          type: boolean`,
+					`        Float64Val:`,
+					`          type: number`,
+					`          format: double`,
+					`        IntVal:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        StringVal:`,
+					`          type: string`,
+					`    InnerStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        listOfStrings:`,
+					`          type: array`,
+					`          items:`,
+					`            type: string`,
+					`        listOfStructs:`,
+					`          type: array`,
+					`          items:`,
+					`            nullable: true`,
+					`            allOf:`,
+					`            - $ref: '#/components/schemas/BasicStruct'`,
+					`    OuterStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        id:`,
+					`          type: integer`,
+					`          format: int64`,
+					`        inner:`,
+					`          nullable: true`,
+					`          allOf:`,
+					`          - $ref: '#/components/schemas/InnerStruct'`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: nested-struct`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /09-nested/nested-struct:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/OuterStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  id:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                  inner:`,
+					`                    description: 'From $ref: #/components/schemas/InnerStruct'`,
+					`                    nullable: true`,
+					`                    type: object`,
+					`                    additionalProperties: false`,
+					`                    properties:`,
+					`                      listOfStrings:`,
+					`                        type: array`,
+					`                        items:`,
+					`                          type: string`,
+					`                      listOfStructs:`,
+					`                        type: array`,
+					`                        items:`,
+					`                          description: 'From $ref: #/components/schemas/BasicStruct'`,
+					`                          nullable: true`,
+					`                          type: object`,
+					`                          additionalProperties: false`,
+					`                          properties:`,
+					`                            BoolVal:`,
+					`                              type: boolean`,
+					`                            Float64Val:`,
+					`                              type: number`,
+					`                              format: double`,
+					`                            IntVal:`,
+					`                              type: integer`,
+					`                              format: int64`,
+					`                            StringVal:`,
+					`                              type: string`,
+				},
+			},
+		},
+	},
+}
+
+var markdownTests = []fixtures.TestCase{
+	{
+		Name:  "main-struct",
+		Value: MainStruct{},
+		Want: map[string]fixtures.WantSet{
+			"markdown": map[bool][]string{
+				false: []string{
+					`# Root`,
+					`## 10-markdown/main-struct`,
+					`Type: struct (MainStruct)`,
+					`# TypeRef`,
+					`## GoodEntity`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| IntVal | integer | yes | no |  |`,
+					`| Message | string | yes | no |  |`,
+					`| Same | boolean | yes | no |  |`,
+					`## MainStruct`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| BoolVal | boolean | yes | no |  |`,
+					`| DuplicateOne | string | yes | no |  |`,
+					`| duplicateOne | string | yes | no | ERROR: duplicate map key |`,
+					`| FloatVal | float | yes | no |  |`,
+					`| intVal | integer | yes | no |  |`,
+					`| InterfaceVal | invalid | yes | no | ERROR: interface element is nil |`,
+					`| SliceVal | list | yes | no |  |`,
+					`| SliceVal[] | integer | - | no |  |`,
+					`| StringPtr | string | no | yes |  |`,
+					`| stringVal | string | no | no |  |`,
+					`| StructPtr | struct (GoodEntity) | no | yes |  |`,
+					`| StructVal | struct (OtherEntity) | yes | no |  |`,
+					`## OtherEntity`,
+					`Type: struct`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| AnonStruct | struct | yes | no |  |`,
+					`| AnonStruct.FieldOne | string | yes | no |  |`,
+					`| AnonStruct.FieldThree | float | yes | no |  |`,
+					`| AnonStruct.FieldTwo | integer | yes | no |  |`,
+					`| FloatVal | float | yes | no |  |`,
+					`| Good | struct (GoodEntity) | yes | no |  |`,
+					`| GoodPtr | struct (GoodEntity) | no | yes |  |`,
+					`| GoodPtrSlice | list | yes | no |  |`,
+					`| GoodPtrSlice[] | struct (GoodEntity) | - | yes |  |`,
+					`| GoodSlice | list | yes | no |  |`,
+					`| GoodSlice[] | struct (GoodEntity) | - | no |  |`,
+					`| IntVal | integer | yes | no |  |`,
+					`| MapNil | map | yes | no |  |`,
+					`| MapNil{*} | integer | - | no |  |`,
+					`| MapVal | map | yes | no |  |`,
+					`| MapVal{*} | integer | - | no |  |`,
+					`| Same | boolean | yes | no |  |`,
+					`| Simple | integer (SimpleInt) | yes | no |  |`,
+					`| Status | string | yes | no |  |`,
+					`## SimpleInt`,
+					`Type: integer`,
+				},
+				true: []string{
+					`# Root`,
+					`## 10-markdown/main-struct`,
+					`Type: struct (MainStruct)`,
+					`| Field | Type | Required | Nullable | Notes |`,
+					`| --- | --- | --- | --- | --- |`,
+					`| BoolVal | boolean | yes | no |  |`,
+					`| DuplicateOne | string | yes | no |  |`,
+					`| duplicateOne | string | yes | no | ERROR: duplicate map key |`,
+					`| FloatVal | float | yes | no |  |`,
+					`| intVal | integer | yes | no |  |`,
+					`| InterfaceVal | invalid | yes | no | ERROR: interface element is nil |`,
+					`| SliceVal | list | yes | no |  |`,
+					`| SliceVal[] | integer | - | no |  |`,
+					`| StringPtr | string | no | yes |  |`,
+					`| stringVal | string | no | no |  |`,
+					`| StructPtr | struct (GoodEntity) | no | yes |  |`,
+					`| StructPtr.IntVal | integer | yes | no |  |`,
+					`| StructPtr.Message | string | yes | no |  |`,
+					`| StructPtr.Same | boolean | yes | no |  |`,
+					`| StructVal | struct (OtherEntity) | yes | no |  |`,
+					`| StructVal.AnonStruct | struct | yes | no |  |`,
+					`| StructVal.AnonStruct.FieldOne | string | yes | no |  |`,
+					`| StructVal.AnonStruct.FieldThree | float | yes | no |  |`,
+					`| StructVal.AnonStruct.FieldTwo | integer | yes | no |  |`,
+					`| StructVal.FloatVal | float | yes | no |  |`,
+					`| StructVal.Good | struct (GoodEntity) | yes | no |  |`,
+					`| StructVal.Good.IntVal | integer | yes | no |  |`,
+					`| StructVal.Good.Message | string | yes | no |  |`,
+					`| StructVal.Good.Same | boolean | yes | no |  |`,
+					`| StructVal.GoodPtr | struct (GoodEntity) | no | yes |  |`,
+					`| StructVal.GoodPtr.IntVal | integer | yes | no |  |`,
+					`| StructVal.GoodPtr.Message | string | yes | no |  |`,
+					`| StructVal.GoodPtr.Same | boolean | yes | no |  |`,
+					`| StructVal.GoodPtrSlice | list | yes | no |  |`,
+					`| StructVal.GoodPtrSlice[] | struct (GoodEntity) | - | yes |  |`,
+					`| StructVal.GoodPtrSlice[].IntVal | integer | yes | no |  |`,
+					`| StructVal.GoodPtrSlice[].Message | string | yes | no |  |`,
+					`| StructVal.GoodPtrSlice[].Same | boolean | yes | no |  |`,
+					`| StructVal.GoodSlice | list | yes | no |  |`,
+					`| StructVal.GoodSlice[] | struct (GoodEntity) | - | no |  |`,
+					`| StructVal.GoodSlice[].IntVal | integer | yes | no |  |`,
+					`| StructVal.GoodSlice[].Message | string | yes | no |  |`,
+					`| StructVal.GoodSlice[].Same | boolean | yes | no |  |`,
+					`| StructVal.IntVal | integer | yes | no |  |`,
+					`| StructVal.MapNil | map | yes | no |  |`,
+					`| StructVal.MapNil{*} | integer | - | no |  |`,
+					`| StructVal.MapVal | map | yes | no |  |`,
+					`| StructVal.MapVal{*} | integer | - | no |  |`,
+					`| StructVal.Same | boolean | yes | no |  |`,
+					`| StructVal.Simple | integer (SimpleInt) | yes | no |  |`,
+					`| StructVal.Status | string | yes | no |  |`,
+				},
+			},
+		},
+	},
+}
+
+// StringStruct has one string field.
+type StringStruct struct {
+	Value string
+}
+
+// Private Struct only has private fields.
+// IgnoredStruct has no exported fields that are serialized.
+type IgnoredStruct struct {
+	Secret string `json:"-"`
+	note   string
+}
+
+type PrivateStruct struct {
+	boolVal    bool
+	intVal     int
+	float64Val float64
+	stringVal  string
+}
+
+// BasicStruct has one field for each basic type.
+type BasicStruct struct {
+	BoolVal    bool
+	IntVal     int
+	Float64Val float64
+	StringVal  string
+}
+
+type EnumStruct struct {
+	Color string `json:"color" b9schema:"enum=red|green|blue"`
+	Level int    `json:"level" b9schema:"enum=1|2|3"`
+}
+
+var b9schemaTagTests = []fixtures.TestCase{
+	{
+		Name:  "enum",
+		Value: EnumStruct{},
+		Want: map[string]fixtures.WantSet{
+			"openapi": map[bool][]string{
+				false: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: enum`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /11-b9schema-tag/enum:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                $ref: '#/components/schemas/EnumStruct'`,
+					`components:`,
+					`  schemas:`,
+					`    EnumStruct:`,
+					`      type: object`,
+					`      additionalProperties: false`,
+					`      properties:`,
+					`        color:`,
+					`          type: string`,
+					`          enum:`,
+					`          - 'red'`,
+					`          - 'green'`,
+					`          - 'blue'`,
+					`        level:`,
+					`          type: integer`,
+					`          format: int64`,
+					`          enum:`,
+					`          - 1`,
+					`          - 2`,
+					`          - 3`,
+				},
+				true: []string{
+					`openapi: 3.0.0`,
+					`info:`,
+					`  title: enum`,
+					`  version: v1.0.0`,
+					``,
+					`paths:`,
+					`  /11-b9schema-tag/enum:`,
+					`    get:`,
+					`      summary: Return data.`,
+					`      responses:`,
+					`        '200':`,
+					`          description: Success`,
+					`          content:`,
+					`            application/json:`,
+					`              schema:`,
+					`                description: 'From $ref: #/components/schemas/EnumStruct'`,
+					`                type: object`,
+					`                additionalProperties: false`,
+					`                properties:`,
+					`                  color:`,
+					`                    type: string`,
+					`                    enum:`,
+					`                    - 'red'`,
+					`                    - 'green'`,
+					`                    - 'blue'`,
+					`                  level:`,
+					`                    type: integer`,
+					`                    format: int64`,
+					`                    enum:`,
+					`                    - 1`,
+					`                    - 2`,
+					`                    - 3`,
+				},
+			},
+		},
+	},
+}
+
+type MainStruct struct {
+	StringVal string `json:"stringVal,omitempty"`
+	IntVal    int    `json:"intVal" datastore:",noindex"`
+	FloatVal  float64
+	BoolVal   bool
+
+	SliceVal []int
+
+	InterfaceVal interface{}
+
+	StructPtr *GoodEntity
+	StructVal OtherEntity
+
+	StringPtr *string
+
+	// Test duplicate JSON keys when capitalized.
+	DuplicateOne string
+	DuplicateTwo string `json:"duplicateOne"`
+
+	privateVal string
+}
+
+// define a struct for data storage
+type GoodEntity struct {
+	Message string
+	IntVal  int64
+	Same    bool
+
+	secret string
+}
+
+// Test named and un-named types.
+type SimpleString string
+type SimpleInt int64
+type SimpleFloat float64
+type SimpleBool bool
+type SimpleInterface interface{}
+type SimpleSlice []string
+type SimpleMap map[string]int64
+type SimpleStruct GoodEntity
+type SimpleStructSlice []GoodEntity
+type SimplePtr *GoodEntity
+type SimplePtrSlice []*GoodEntity
+
+type NamedEntity struct {
+	NamedString SimpleString `json:"namedString,omitempty"`
+	RealString  string
+
+	NamedInt SimpleInt
+	RealInt  int64
+
+	NamedFloat SimpleFloat
+	RealFloat  float64
+
+	NamedBool SimpleBool
+	RealBool  bool
+
+	NamedInterface SimpleInterface
+	RealInterface  interface{}
+
+	NamedSlice SimpleSlice
+	RealSlice  []string
+
+	NamedMap SimpleMap
+	RealMap  map[string]int64
+
+	NamedStruct SimpleStruct
+	RealStruct  GoodEntity
+
+	NamedStructSlice SimpleStructSlice
+	RealStructSlice  []GoodEntity
+
+	NamedPtr SimplePtr
+	RealPtr  *GoodEntity
+
+	NamedPtrSlice SimplePtrSlice
+	RealPtrSlice  []*GoodEntity
+}
+
+// define a different struct to test mismatched structs
+type OtherEntity struct {
+	Status   string
+	IntVal   int64
+	FloatVal float64
+	Same     bool
+	Simple   SimpleInt
+
+	MapNil map[string]int64
+	MapVal map[string]int64
+
+	Good         GoodEntity
+	GoodPtr      *GoodEntity
+	GoodSlice    []GoodEntity
+	GoodPtrSlice []*GoodEntity
+
+	AnonStruct struct {
+		FieldOne   string
+		FieldTwo   int32
+		FieldThree float32
+	}
+}
+
+// Test nested data structures.
+type OuterStruct struct {
+	ID    int          `json:"id"`
+	Inner *InnerStruct `json:"inner"`
+}
+
+type InnerStruct struct {
+	ListOfStrings []string       `json:"listOfStrings"`
+	ListOfStructs []*BasicStruct `json:"listOfStructs"`
+}
+
+// fromJSON converts a JSON string into an interface.
+func fromJSON(in []byte) interface{} {
+	var out interface{}
+
+	if err := json.Unmarshal(in, &out); err != nil {
+		err = fmt.Errorf("ERROR json.Unmarshal: %s\n%s", err, string(in))
+		fmt.Println(err)
+		return err
+	}
+
+	// // DEBUGXXXXX Print indented JSON string.
+	// if out != nil {
+	// 	if b, err := json.MarshalIndent(out, "", "  "); err == nil {
+	// 		fmt.Println(string(b))
+	// 	}
+	// }
+
+	return out
+}
+
+// makeJSON converts an interface to JSON.
+func makeJSON(x interface{}) interface{} {
+	var s = "hey"
+
+	x = &MainStruct{
+		StringVal: "hello",
+		IntVal:    123,
+		FloatVal:  234.345,
+		BoolVal:   true,
+		SliceVal:  []int{1, 2, 3},
+		StructPtr: &GoodEntity{
+			Message: "hi",
+			IntVal:  234,
+			Same:    true,
+			secret:  "eyes only",
+		},
+		StructVal: OtherEntity{
+			Status:   "ok",
+			IntVal:   789,
+			FloatVal: 789.123,
+			Same:     true,
+			MapVal:   map[string]int64{"one": 234, "two": 345, "three": 456},
+			Good: GoodEntity{
+				Message: "",
+				IntVal:  0,
+				Same:    false,
+				secret:  "",
+			},
+			GoodPtr: &GoodEntity{
+				Message: "hi",
+				IntVal:  234,
+				Same:    true,
+				secret:  "eyes only",
+			},
+			GoodSlice:    []GoodEntity{},
+			GoodPtrSlice: []*GoodEntity{},
+		},
+		StringPtr: &s,
+
+		DuplicateOne: "one",
+		DuplicateTwo: "two",
+
+		privateVal: "shh",
+	}
+
+	if b, err := json.Marshal(x); err != nil {
+		return fmt.Errorf("ERROR json.Marshal: %s", err)
+	} else {
+		return fromJSON(b)
+	}
+}
+
+// textRenderers build renderers for formats that are compared as plain text.
+var textRenderers = map[string]func(opt *renderer.Options) renderer.Renderer{
+	"markdown": func(opt *renderer.Options) renderer.Renderer { return markdown.NewMarkdownRenderer(opt) },
+	"simple":   func(opt *renderer.Options) renderer.Renderer { return simple.NewSimpleRenderer(opt) },
+	"typescript": func(opt *renderer.Options) renderer.Renderer {
+		return typescript.NewTypeScriptRenderer(opt)
+	},
+}
+
+func TestReflector_AllTests(t *testing.T) {
+	// Booleans for deref looping.
+	derefFlags := []bool{false, true}
+
+	// want represents strings for deref false/true
+	var want map[bool][]string
+	var format string
+	var gotStrings []string
+
+	r := reflector.NewReflector()
+	if err := r.RegisterInterfaceImplementations((*Shape)(nil), Circle{}, &Square{}); err != nil {
+		t.Fatalf("TEST_FAIL register Shape: err=%s", err)
+	}
+	opt := renderer.NewOptions()
+
+	// Build sorted list of text formats.
+	textFormats := []string{}
+	for k := range textRenderers {
+		textFormats = append(textFormats, k)
+	}
+	sort.Strings(textFormats)
+
+	// testName builds a test name string.
+	testName := func(group, name, format string, deref bool) string {
+		return fmt.Sprintf("%s/%s/%t/%s", group, name, deref, format)
+	}
+
+	// Build sorted list of test keys.
+	allKeys := []string{}
+	for k := range allTests {
+		allKeys = append(allKeys, k)
+	}
+	sort.Strings(allKeys)
+
+	for _, testGroup := range allKeys {
+		testCases := allTests[testGroup]
+
+		for _, test := range testCases {
+			r.Reset()
+			gotSchema := r.DeriveSchema(test.Value, testGroup+"/"+test.Name)
+
+			format = "reflector"
+			want = test.Want[format]
+			if want != nil {
+				for _, deref := range derefFlags {
+					wantStrings := want[deref]
+					if len(wantStrings) > 0 {
+						name := testName(testGroup, test.Name, format, deref)
+
+						if b, err := yaml.Marshal(gotSchema.CopyWithoutNative()); err != nil {
+							t.Errorf("TEST_FAIL %s: yaml err=%s", name, err)
+							continue
+						} else {
+							gotStrings = strings.Split(string(b), "\n")
+						}
+
+						util.CompareStrings(t, name, gotStrings, wantStrings)
+					}
+				}
+			}
+
+			for _, format = range textFormats {
+				want = test.Want[format]
+				if want == nil {
+					continue
+				}
+
+				for _, deref := range derefFlags {
+					wantStrings := want[deref]
+					if len(wantStrings) > 0 {
+						name := testName(testGroup, test.Name, format, deref)
+						opt.DeReference = deref
+						opt.Indent = 0
+
+						r := textRenderers[format](opt)
+						gotStrings, err := r.ProcessSchema(gotSchema)
+						if err != nil {
+							t.Errorf("TEST_FAIL %s: %q err=%s", name, format, err)
+							continue
+						}
+
+						util.CompareStrings(t, name, gotStrings, wantStrings)
+					}
+				}
+			}
+
+			format = "openapi"
+			want = test.Want[format]
+			if want != nil {
+				for _, deref := range derefFlags {
+					wantStrings := want[deref]
+					if len(wantStrings) > 0 {
+						name := testName(testGroup, test.Name, format, deref)
+						opt.DeReference = deref
+						opt.Indent = 0
+
+						r := openapi.NewOpenAPIRenderer(openapi.NewMetaData(test.Name, "v1.0.0"), opt)
+						gotStrings, err := r.ProcessSchema(gotSchema)
+
+						if err != nil {
+							t.Errorf("TEST_FAIL %s: %q err=%s", name, format, err)
+							continue
+						}
+
+						if !util.CompareStrings(t, name, gotStrings, wantStrings) {
+							continue
+						}
+
+						// Verify that YAML is valid.
+						yamlStr := strings.Join(gotStrings, "\n")
+						var yamlOut interface{}
+						if err := yaml.Unmarshal([]byte(yamlStr), &yamlOut); err != nil {
+							util.OutputErrStrings(t, name, gotStrings, fmt.Errorf("yaml err=%s", err))
+							continue
+						} else if b, err := json.Marshal(yamlOut); err != nil {
+							t.Errorf("TEST_FAIL %s: json err=%s", name, err)
+						} else {
+							_ = b
+							//t.Logf("TEST_OK %s: yaml/json=%s", test.Name, string(b))
+							//t.Logf("TEST_OK %s: yaml/json len=%d", test.Name, len(b))
+						}
+
+						// Verify that OpenAPI is valid.
+						if !validateOpenAPI(t, name, yamlStr) {
+							continue
+						}
+
+						t.Logf("TEST_OK %s", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReflector_OmitEmpty(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(MainStruct{}, "omitempty")
+	mainStruct := schema.TypeRef.ChildByName("MainStruct", nil)
+	if mainStruct == nil {
+		t.Fatalf("TEST_FAIL MainStruct not found")
+	}
+
+	testCases := []struct {
+		field   string
+		wantVal string
+		wantOk  bool
+	}{
+		{field: "StringVal", wantVal: "true", wantOk: true},
+		{field: "IntVal", wantVal: "false", wantOk: true},
+		{field: "FloatVal", wantVal: "", wantOk: false},
+	}
+
+	for _, test := range testCases {
+		field := mainStruct.ChildByName(test.field, nil)
+		if field == nil {
+			t.Errorf("TEST_FAIL %s: field not found", test.field)
+			continue
+		}
+
+		gotVal, gotOk := field.NativeDefault().Options.Lookup(reflector.OMITEMPTY_OPTION)
+		if gotVal != test.wantVal || gotOk != test.wantOk {
+			t.Errorf("TEST_FAIL %s: got=%q,%v want=%q,%v", test.field, gotVal, gotOk, test.wantVal, test.wantOk)
+		} else {
+			t.Logf("TEST_OK %s: got=%q,%v", test.field, gotVal, gotOk)
+		}
+	}
+}
+
+func TestReflector_DuplicateJSONKey(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(MainStruct{}, "duplicate")
+	mainStruct := schema.TypeRef.ChildByName("MainStruct", nil)
+	if mainStruct == nil {
+		t.Fatalf("TEST_FAIL MainStruct not found")
+	}
+
+	// Only the second field with the same json name has an error.
+	gotErrors := []string{}
+	for _, name := range []string{"DuplicateOne", "DuplicateTwo", "StringVal"} {
+		field := mainStruct.ChildByName(name, nil)
+		if field == nil {
+			t.Fatalf("TEST_FAIL %s: field not found", name)
+		}
+		gotErrors = append(gotErrors, field.Error)
+	}
+
+	wantErrors := []string{"", types.DuplicateMapKeyErr, ""}
+	if strings.Join(gotErrors, ",") != strings.Join(wantErrors, ",") {
+		t.Errorf("TEST_FAIL duplicate: got=%q want=%q", gotErrors, wantErrors)
+	} else {
+		t.Logf("TEST_OK duplicate: got=%q", gotErrors)
+	}
+}
+
+func TestReflector_DeriveSchemaFromType(t *testing.T) {
+	var errValue error
+
+	testCases := []struct {
+		name    string
+		typ     reflect.Type
+		value   interface{}
+		wantErr string
+	}{
+		{
+			name:  "struct",
+			typ:   reflect.TypeOf(GoodEntity{}),
+			value: GoodEntity{},
+		},
+		{
+			name:  "pointer",
+			typ:   reflect.TypeOf(&GoodEntity{}),
+			value: (*GoodEntity)(nil),
+		},
+		{
+			name:    "interface",
+			typ:     reflect.TypeOf(&errValue).Elem(),
+			wantErr: types.NilInterfaceErr,
+		},
+		{
+			name:    "nil",
+			typ:     nil,
+			wantErr: types.InvalidKindErr,
+		},
+	}
+
+	for _, test := range testCases {
+		gotSchema := reflector.NewReflector().DeriveSchemaFromType(test.typ, test.name)
+
+		if test.wantErr != "" {
+			gotErr := gotSchema.Root.Children[0].Error
+			if gotErr != test.wantErr {
+				t.Errorf("TEST_FAIL %s: got=%q want=%q", test.name, gotErr, test.wantErr)
+			} else {
+				t.Logf("TEST_OK %s: got=%q", test.name, gotErr)
+			}
+			continue
+		}
+
+		// Schema from type matches schema from a zero value.
+		wantSchema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(gotSchema)
+		wantStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(wantSchema)
+		util.CompareStrings(t, test.name, gotStrings, wantStrings)
+	}
+}
+
+func TestReflector_DeriveSchemaFromValue(t *testing.T) {
+	// Struct values match DeriveSchema.
+	value := &GoodEntity{Message: "hello"}
+	gotSchema := reflector.NewReflector().DeriveSchemaFromValue(reflect.ValueOf(value).Elem(), "value")
+	wantSchema := reflector.NewReflector().DeriveSchema(*value, "value")
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(gotSchema)
+	wantStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(wantSchema)
+	util.CompareStrings(t, "value", gotStrings, wantStrings)
+
+	// The zero Value is an invalid element.
+	gotSchema = reflector.NewReflector().DeriveSchemaFromValue(reflect.Value{}, "zero")
+	gotStrings, _ = simple.NewSimpleRenderer(nil).ProcessSchema(gotSchema)
+	wantStrings = []string{
+		`Root.!invalid:nil! ERROR:kind not supported`,
+	}
+	util.CompareStrings(t, "zero", gotStrings, wantStrings)
+}
+
+func TestReflector_DeriveSchemaFromJSON(t *testing.T) {
+	jsonStr := `{"IntVal": 123, "FloatVal": 234.345, "BigVal": 1e3, "IntList": [1, 2], "MixedList": [1, 2.5]}`
+
+	testCases := []struct {
+		name          string
+		useJSONNumber bool
+		want          []string
+	}{
+		{
+			name: "float",
+			want: []string{
+				`Root.{}`,
+				`Root.{}.BigVal:float`,
+				`Root.{}.FloatVal:float`,
+				`Root.{}.IntList:[]`,
+				`Root.{}.IntList:[].float`,
+				`Root.{}.IntVal:float`,
+				`Root.{}.MixedList:[]`,
+				`Root.{}.MixedList:[].float`,
+			},
+		},
+		{
+			name:          "json-number",
+			useJSONNumber: true,
+			want: []string{
+				`Root.{}`,
+				`Root.{}.BigVal:float`,
+				`Root.{}.FloatVal:float`,
+				`Root.{}.IntList:[]`,
+				`Root.{}.IntList:[].integer`,
+				`Root.{}.IntVal:integer`,
+				`Root.{}.MixedList:[]`,
+				`Root.{}.MixedList:[].float`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.UseJSONNumber = test.useJSONNumber
+
+		schema, err := r.DeriveSchemaFromJSON([]byte(jsonStr), test.name)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+
+	// Invalid JSON returns an error.
+	if _, err := reflector.NewReflector().DeriveSchemaFromJSON([]byte(`{`), "invalid"); err == nil {
+		t.Errorf("TEST_FAIL invalid: want error")
+	}
+}
+
+func TestSchema_Walk(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(&CycleTest{}, "walk")
+	r := simple.NewSimpleRenderer(nil)
+
+	// Count all nodes.
+	// - Root tree is the Root node plus the 23 nodes in the simple fixture plus the excluded Level field.
+	rootCount, typeRefCount := 0, 0
+	_ = schema.Root.Walk(func(node *types.TypeNode) error {
+		rootCount++
+		return nil
+	})
+	_ = schema.TypeRef.Walk(func(node *types.TypeNode) error {
+		typeRefCount++
+		return nil
+	})
+
+	schemaCount := 0
+	if err := schema.Walk(func(node *types.TypeNode) error {
+		schemaCount++
+		return nil
+	}); err != nil {
+		t.Errorf("TEST_FAIL count: err=%s", err)
+	}
+
+	if rootCount != 25 || typeRefCount != 16 || schemaCount != rootCount+typeRefCount {
+		t.Errorf("TEST_FAIL count: got=%d,%d,%d want=25,16,41", rootCount, typeRefCount, schemaCount)
+	} else {
+		t.Logf("TEST_OK count: got=%d,%d,%d", rootCount, typeRefCount, schemaCount)
+	}
+
+	// Nodes are visited depth-first in ChildKeys order.
+	gotStrings := []string{}
+	_ = schema.TypeRef.Walk(func(node *types.TypeNode) error {
+		gotStrings = append(gotStrings, strings.Join(r.Path(node), "."))
+		return nil
+	})
+	util.CompareStrings(t, "order", gotStrings, []string{
+		`TypeRef`,
+		`TypeRef.AStruct:{}`,
+		`TypeRef.AStruct:{}.AChild:{}:BStruct`,
+		`TypeRef.AStruct:{}.AName:string`,
+		`TypeRef.BStruct:{}`,
+		`TypeRef.BStruct:{}.BChild:{}:CStruct`,
+		`TypeRef.BStruct:{}.BName:string`,
+		`TypeRef.CStruct:{}`,
+		`TypeRef.CStruct:{}.CChild:{}:AStruct`,
+		`TypeRef.CStruct:{}.CName:string`,
+		`TypeRef.CycleTest:{}`,
+		`TypeRef.CycleTest:{}.CycleA:{}:AStruct`,
+		`TypeRef.CycleTest:{}.CycleB:{}:BStruct`,
+		`TypeRef.CycleTest:{}.CycleC:{}`,
+		`TypeRef.CycleTest:{}.CycleC:{}.C:{}:CStruct`,
+		`TypeRef.CycleTest:{}.Level:integer`,
+	})
+
+	// ErrStopWalk stops early without an error.
+	stopCount := 0
+	if err := schema.Walk(func(node *types.TypeNode) error {
+		stopCount++
+		if stopCount == 3 {
+			return types.ErrStopWalk
+		}
+		return nil
+	}); err != nil || stopCount != 3 {
+		t.Errorf("TEST_FAIL stop: got=%d,%v want=3,nil", stopCount, err)
+	} else {
+		t.Logf("TEST_OK stop: got=%d", stopCount)
+	}
+
+	// Other errors are returned.
+	errTest := fmt.Errorf("test error")
+	if err := schema.Walk(func(node *types.TypeNode) error {
+		return errTest
+	}); err != errTest {
+		t.Errorf("TEST_FAIL error: got=%v want=%v", err, errTest)
+	} else {
+		t.Logf("TEST_OK error: got=%v", err)
+	}
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	// Map keys are found in random order, marshaled children are sorted.
+	value := map[string]interface{}{}
+	for _, key := range strings.Split("a b c d e f g h i j k l m n o p", " ") {
+		value[key] = key
+	}
+
+	testCases := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "cycle", value: &CycleTest{}},
+		{name: "map", value: value},
+	}
+
+	for _, test := range testCases {
+		got := []string{}
+		for i := 0; i < 2; i++ {
+			b, err := json.Marshal(reflector.NewReflector().DeriveSchema(test.value, test.name))
+			if err != nil {
+				t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+			}
+			got = append(got, string(b))
+		}
+
+		if got[0] != got[1] {
+			t.Errorf("TEST_FAIL %s: got=%s want=%s", test.name, got[1], got[0])
+		} else {
+			t.Logf("TEST_OK %s", test.name)
+		}
+	}
+}
+
+func TestSchema_NewSchemaFromJSON(t *testing.T) {
+	r := reflector.NewReflector()
+	r.DeriveSchemaForOperation(&CycleTest{}, "/cycle", "")
+	r.DeriveSchemaForOperation(KeyPatternTypes{}, "/labels", "post")
+	schema := r.DeriveSchemaForOperation(map[string]interface{}{"a": 1, "b": []string{"c"}}, "/map", "")
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL marshal: err=%s", err)
+	}
+	loaded, err := types.NewSchemaFromJSON(b)
+	if err != nil {
+		t.Fatalf("TEST_FAIL load: err=%s", err)
+	}
+
+	// Loaded schemas render like the derived schema.
+	for _, deref := range []bool{false, true} {
+		renderers := map[string]func() renderer.Renderer{
+			"openapi": func() renderer.Renderer {
+				return openapi.NewOpenAPIRenderer(openapi.NewMetaData("load", "v1.0.0"), &renderer.Options{DeReference: deref})
+			},
+		}
+		for name, newRenderer := range textRenderers {
+			newRenderer := newRenderer
+			renderers[name] = func() renderer.Renderer { return newRenderer(&renderer.Options{DeReference: deref}) }
+		}
+
+		for name, newRenderer := range renderers {
+			testName := fmt.Sprintf("%s/%t", name, deref)
+
+			wantStrings, err := newRenderer().ProcessSchema(schema)
+			if err != nil {
+				t.Fatalf("TEST_FAIL %s: err=%s", testName, err)
+			}
+			gotStrings, err := newRenderer().ProcessSchema(loaded)
+			if err != nil {
+				t.Fatalf("TEST_FAIL %s: load err=%s", testName, err)
+			}
+			util.CompareStrings(t, testName, gotStrings, wantStrings)
+		}
+	}
+
+	// Invalid input returns errors.
+	for _, s := range []string{`{`, `{}`, `{"Root": {"Type": "root"}}`} {
+		if _, err := types.NewSchemaFromJSON([]byte(s)); err == nil {
+			t.Errorf("TEST_FAIL invalid %s: want error", s)
+		} else {
+			t.Logf("TEST_OK invalid %s: err=%s", s, err)
+		}
+	}
+}
+
+func TestReflector_DeriveSchemaInto(t *testing.T) {
+	r := reflector.NewReflector()
+	schema := types.NewSchema(reflector.NATIVE_DIALECT)
+
+	outer := r.DeriveSchemaInto(schema, OuterStruct{}, "/outer")
+	outer.Description = "Outer handler"
+	inner := r.DeriveSchemaInto(schema, InnerStruct{}, "/inner")
+
+	if outer.MetaKey != "/outer" || inner.MetaKey != "/inner" {
+		t.Errorf("TEST_FAIL into: got MetaKeys %q, %q", outer.MetaKey, inner.MetaKey)
+	}
+	if len(schema.Root.Children) != 2 || schema.Root.Children[0] != outer || schema.Root.Children[1] != inner {
+		t.Errorf("TEST_FAIL into: returned elements are not the root children")
+	}
+
+	// TypeRefs are shared by both root elements.
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:InnerStruct`,
+		`Root.{}:OuterStruct`,
+		`TypeRef.BasicStruct:{}`,
+		`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+		`TypeRef.BasicStruct:{}.Float64Val:float`,
+		`TypeRef.BasicStruct:{}.IntVal:integer`,
+		`TypeRef.BasicStruct:{}.StringVal:string`,
+		`TypeRef.InnerStruct:{}`,
+		`TypeRef.InnerStruct:{}.ListOfStrings:[]`,
+		`TypeRef.InnerStruct:{}.ListOfStrings:[].string`,
+		`TypeRef.InnerStruct:{}.ListOfStructs:[]`,
+		`TypeRef.InnerStruct:{}.ListOfStructs:[].{}:BasicStruct`,
+		`TypeRef.OuterStruct:{}`,
+		`TypeRef.OuterStruct:{}.ID:integer`,
+		`TypeRef.OuterStruct:{}.Inner:{}:InnerStruct`,
+	}
+	util.CompareStrings(t, "into", gotStrings, wantStrings)
+
+	// The Reflector's own schema is not changed.
+	if len(r.Schema.Root.Children) != 0 || len(r.Schema.TypeRef.Children) != 0 {
+		t.Errorf("TEST_FAIL into: reflector schema was changed")
+	}
+}
+
+func TestReflector_DeriveSchemaWithName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		typeName string
+		wantRef  string
+		wantRefs []string
+	}{
+		{
+			name:     "anonymous-struct",
+			value:    struct{ Key string }{},
+			typeName: "Anon",
+			wantRef:  "Anon",
+			wantRefs: []string{"Anon"},
+		},
+		{
+			name:     "json-object",
+			value:    fromJSON([]byte(`{"key1":"Hello"}`)),
+			typeName: "Foo",
+			wantRef:  "Foo",
+			wantRefs: []string{"Foo"},
+		},
+		{
+			name:     "named-override",
+			value:    GoodEntity{},
+			typeName: "Renamed",
+			wantRef:  "Renamed",
+			wantRefs: []string{"Renamed"},
+		},
+		{
+			name:     "pointer-override",
+			value:    &GoodEntity{},
+			typeName: "Renamed",
+			wantRef:  "Renamed",
+			wantRefs: []string{"Renamed"},
+		},
+		{
+			name:     "empty-name",
+			value:    GoodEntity{},
+			wantRef:  "GoodEntity",
+			wantRefs: []string{"GoodEntity"},
+		},
+		{
+			name:     "anonymous-empty-name",
+			value:    struct{ Key string }{},
+			wantRef:  "",
+			wantRefs: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchemaWithName(test.value, test.name, test.typeName)
+		root := schema.Root.Children[0]
+
+		gotRefs := []string{}
+		for _, child := range schema.TypeRef.Children {
+			gotRefs = append(gotRefs, child.Name)
+		}
+
+		if root.TypeRef != test.wantRef || strings.Join(gotRefs, ",") != strings.Join(test.wantRefs, ",") {
+			t.Errorf("TEST_FAIL %s: got=%q,%v want=%q,%v", test.name, root.TypeRef, gotRefs, test.wantRef, test.wantRefs)
+		} else {
+			t.Logf("TEST_OK %s: got=%q,%v", test.name, root.TypeRef, gotRefs)
+		}
+	}
+}
+
+func TestReflector_Reset(t *testing.T) {
+	type ResetStruct struct {
+		ID FakeUUID
+	}
+
+	r := reflector.NewReflector()
+	r.UseJSONNumber = true
+	if err := r.RegisterKnownType(FakeUUID{}, "string", "uuid"); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	r.SetDescriptions(map[string]string{"ResetStruct.ID": "Identifier"})
+
+	r.DeriveSchema(ResetStruct{}, "first")
+	schema := r.Reset().DeriveSchema(ResetStruct{}, "second")
+
+	// Schema is cleared.
+	if got := len(schema.Root.Children); got != 1 {
+		t.Errorf("TEST_FAIL schema: got=%d roots want=1", got)
+	}
+
+	// Configuration is kept.
+	field := schema.TypeRef.ChildByName("ResetStruct", nil).ChildByName("ID", nil)
+	if field.Type != "string" || field.Description != "Identifier" || !r.UseJSONNumber {
+		t.Errorf("TEST_FAIL config: got=%q,%q,%t want=%q,%q,%t", field.Type, field.Description, r.UseJSONNumber, "string", "Identifier", true)
+	} else {
+		t.Logf("TEST_OK config")
+	}
+}
+
+func TestReflector_Clone(t *testing.T) {
+	type CloneStruct struct {
+		ID FakeUUID
+	}
+
+	template := reflector.NewReflector()
+	if err := template.RegisterKnownType(FakeUUID{}, "string", "uuid"); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	template.SetDescriptions(map[string]string{"CloneStruct.ID": "Identifier"})
+
+	// Derive different types in each clone at the same time.
+	values := []interface{}{CloneStruct{}, &CycleTest{}}
+	clones := []*reflector.Reflector{template.Clone(), template.Clone()}
+
+	var wg sync.WaitGroup
+	for i := range clones {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clones[i].DeriveSchema(values[i], "clone")
+		}(i)
+	}
+	wg.Wait()
+
+	testCases := []struct {
+		name string
+		r    *reflector.Reflector
+		want []string
+	}{
+		{name: "template", r: template, want: []string{}},
+		{name: "clone-0", r: clones[0], want: []string{"CloneStruct"}},
+		{name: "clone-1", r: clones[1], want: []string{"AStruct", "BStruct", "CStruct", "CycleTest"}},
+	}
+
+	for _, test := range testCases {
+		got := []string{}
+		for _, ref := range test.r.Schema.TypeRef.Children {
+			got = append(got, ref.Name)
+		}
+		sort.Strings(got)
+		util.CompareStrings(t, test.name, got, test.want)
+	}
+
+	// Configuration is copied.
+	field := clones[0].Schema.TypeRef.ChildByName("CloneStruct", nil).ChildByName("ID", nil)
+	if field.Type != "string" || field.Description != "Identifier" {
+		t.Errorf("TEST_FAIL config: got=%q,%q want=%q,%q", field.Type, field.Description, "string", "Identifier")
+	} else {
+		t.Logf("TEST_OK config")
+	}
+}
+
+func TestReflector_EnableCache(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "cycle", value: &CycleTest{}},
+		{name: "root-error", value: []BasicStruct{}},
+		{name: "map", value: map[string]interface{}{"key": "value"}},
+	}
+
+	for _, test := range testCases {
+		// Derive twice with and without the cache, the second derivation is a cache hit.
+		uncached := reflector.NewReflector()
+		uncached.DeriveSchema(test.value, "first")
+		wantSchema := uncached.DeriveSchema(test.value, "second")
+
+		cached := reflector.NewReflector().EnableCache()
+		cached.DeriveSchema(test.value, "first")
+		gotSchema := cached.DeriveSchema(test.value, "second")
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(gotSchema)
+		wantStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(wantSchema)
+		util.CompareStrings(t, test.name, gotStrings, wantStrings)
+
+		// Cached copies must not share elements.
+		seen := map[*types.TypeNode]bool{}
+		gotSchema.Walk(func(node *types.TypeNode) error {
+			if seen[node] {
+				t.Errorf("TEST_FAIL %s: shared element %s", test.name, node.PathString(""))
+			}
+			seen[node] = true
+			return nil
+		})
+	}
+
+	// A new schema gets the cached TypeRefs.
+	r := reflector.NewReflector().EnableCache()
+	r.DeriveSchema(&CycleTest{}, "first")
+	schema := r.Reset().DeriveSchema(&CycleTest{}, "second")
+	if got := len(schema.TypeRef.Children); got != 4 {
+		t.Errorf("TEST_FAIL reset: got=%d type refs want=4", got)
+	} else {
+		t.Logf("TEST_OK reset")
+	}
+}
+
+func benchmarkDeriveSchema(b *testing.B, r *reflector.Reflector) {
+	for i := 0; i < b.N; i++ {
+		r.Reset().DeriveSchema(&CycleTest{}, "cycle")
+	}
+}
+
+func BenchmarkReflector_DeriveSchema(b *testing.B) {
+	benchmarkDeriveSchema(b, reflector.NewReflector())
+}
+
+func BenchmarkReflector_DeriveSchemaCached(b *testing.B) {
+	benchmarkDeriveSchema(b, reflector.NewReflector().EnableCache())
+}
+
+// largeSchema builds a schema with 1,000 elements of 10 fields each.
+func largeSchema() *types.Schema {
+	value := map[string]interface{}{}
+	for i := 0; i < 1000; i++ {
+		fields := map[string]interface{}{}
+		for j := 0; j < 10; j++ {
+			fields[fmt.Sprintf("field%02d", j)] = "value"
+		}
+		value[fmt.Sprintf("type%04d", i)] = fields
+	}
+	return reflector.NewReflector().DeriveSchema(value, "large")
+}
+
+func TestRenderer_RenderSchemaTo(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(&CycleTest{}, "cycle")
+	metadata := openapi.NewMetaData("cycle", "v1.0.0")
+
+	testCases := []struct {
+		name string
+		r    renderer.Renderer
+	}{
+		{name: "markdown", r: markdown.NewMarkdownRenderer(nil)},
+		{name: "simple", r: simple.NewSimpleRenderer(nil)},
+		{name: "typescript", r: typescript.NewTypeScriptRenderer(nil)},
+		// OpenAPI is not a WriterRenderer and is written after rendering.
+		{name: "openapi", r: openapi.NewOpenAPIRenderer(metadata, nil)},
+	}
+
+	for _, tc := range testCases {
+		wantStrings, err := tc.r.ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", tc.name, err)
+		}
+
+		buf := &strings.Builder{}
+		if err := renderer.ProcessSchemaTo(buf, schema, tc.r); err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", tc.name, err)
+		}
+
+		// Compare lines because rendered strings may be blocks of several lines.
+		gotStrings := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		util.CompareStrings(t, tc.name, gotStrings, strings.Split(strings.Join(wantStrings, "\n"), "\n"))
+	}
+}
+
+func TestRenderer_SharedOptions(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(&CycleTest{}, "cycle")
+
+	testCases := []struct {
+		name      string
+		newFn     func(opt *renderer.Options) renderer.Renderer
+		wantFirst string
+	}{
+		{
+			name:      "typescript",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return typescript.NewTypeScriptRenderer(opt) },
+			wantFirst: "  aChild?: BStruct;",
+		},
+		{
+			name: "openapi",
+			newFn: func(opt *renderer.Options) renderer.Renderer {
+				return openapi.NewOpenAPIRenderer(openapi.NewMetaData("cycle", "v1.0.0"), opt)
+			},
+			wantFirst: "  title: cycle",
+		},
+		{
+			name:      "python",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return python.NewPythonRenderer(opt) },
+			wantFirst: `    aChild: Optional["BStruct"] = None`,
+		},
+		{
+			name:      "zod",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return zod.NewZodRenderer(opt) },
+			wantFirst: "  aChild: z.lazy(() => BStructSchema).nullable(),",
+		},
+		{
+			name:      "gostruct",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return gostruct.NewGoStructRenderer(opt) },
+			wantFirst: "\tAChild *BStruct `json:\"aChild\"`",
+		},
+		{
+			name:      "cue",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return cue.NewCUERenderer(opt) },
+			wantFirst: "\taChild?: #BStruct | null",
+		},
+		{
+			name:      "sqlddl",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return sqlddl.NewSQLDDLRenderer(opt) },
+			wantFirst: "  -- unsupported column aChild (struct)",
+		},
+		{
+			name:      "html",
+			newFn:     func(opt *renderer.Options) renderer.Renderer { return html.NewHTMLRenderer(opt) },
+			wantFirst: "  <section>",
+		},
+	}
+
+	// Renderer defaults (e.g. a two space prefix) do not change Options shared with other renderers.
+	for _, tc := range testCases {
+		opt := renderer.NewOptions()
+		gotStrings, err := tc.newFn(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", tc.name, err)
+		}
+
+		got := fmt.Sprintf("%q|%d|%q", opt.Prefix, opt.Indent, firstIndented(gotStrings))
+		want := fmt.Sprintf("%q|%d|%q", "", 0, tc.wantFirst)
+		if got != want {
+			t.Errorf("TEST_FAIL %s: got=%s want=%s", tc.name, got, want)
+		} else {
+			t.Logf("TEST_OK %s: got=%s", tc.name, got)
+		}
+	}
+}
+
+// firstIndented returns the first line that starts with whitespace.
+func firstIndented(lines []string) string {
+	for _, line := range strings.Split(strings.Join(lines, "\n"), "\n") {
+		if strings.TrimLeft(line, " \t") != line {
+			return line
+		}
+	}
+	return ""
+}
+
+func BenchmarkRenderer_RenderSchema(b *testing.B) {
+	schema := largeSchema()
+	r := simple.NewSimpleRenderer(nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, line := range renderer.RenderSchema(schema, r) {
+			io.WriteString(io.Discard, line+"\n")
+		}
+	}
+}
+
+func BenchmarkRenderer_RenderSchemaTo(b *testing.B) {
+	schema := largeSchema()
+	r := simple.NewSimpleRenderer(nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		renderer.RenderSchemaTo(io.Discard, schema, r)
+	}
+}
+
+func TestReflector_MaxDepth(t *testing.T) {
+	// Build a map with 100 levels of nesting.
+	value := map[string]interface{}{"value": "bottom"}
+	for i := 0; i < 99; i++ {
+		value = map[string]interface{}{"nested": value}
+	}
+
+	r := reflector.NewReflector()
+	r.MaxDepth = 10
+	schema := r.DeriveSchema(value, "depth")
+
+	// Count levels and find the error at the bottom.
+	levels := 0
+	var gotErr string
+	for node := schema.Root.Children[0]; node != nil; levels++ {
+		gotErr = node.Error
+		node = node.ChildByName("Nested", nil)
+	}
+
+	if levels != 11 || gotErr != types.MaxDepthErr {
+		t.Errorf("TEST_FAIL depth: got=%d,%q want=%d,%q", levels, gotErr, 11, types.MaxDepthErr)
+	} else {
+		t.Logf("TEST_OK depth: got=%d,%q", levels, gotErr)
+	}
+}
+
+type PanicInner struct {
+	Name string
+}
+
+type PanicOuter struct {
+	Count int
+	Inner PanicInner
+}
+
+func TestReflector_PanicRecovery(t *testing.T) {
+	// A panic in a callback is captured on the element being reflected.
+	r := reflector.NewReflector()
+	r.TypeNameFunc = func(name string) string {
+		if strings.HasSuffix(name, "PanicInner") {
+			panic("bad type name")
+		}
+		return reflector.SanitizeTypeName(name)
+	}
+	r.Strict = true
+
+	schema, err := r.DeriveSchemaStrict(PanicOuter{}, "panic")
+	if err == nil {
+		t.Errorf("TEST_FAIL strict: want error")
+	} else {
+		t.Logf("TEST_OK strict: err=%s", err)
+	}
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	util.CompareStrings(t, "simple", gotStrings, []string{
+		`Root.{}:PanicOuter`,
+		`TypeRef.PanicOuter:{}`,
+		`TypeRef.PanicOuter:{}.Count:integer`,
+		`TypeRef.PanicOuter:{}.!Inner:{}! ERROR:reflection panic`,
+	})
+
+	inner := schema.TypeRef.ChildByName("PanicOuter", nil).ChildByName("Inner", nil)
+	if got := inner.NativeDefault().Error; got != "bad type name" {
+		t.Errorf("TEST_FAIL native: got=%q want=%q", got, "bad type name")
+	} else {
+		t.Logf("TEST_OK native: got=%q", got)
+	}
+}
+
+// Address has the same name as fixtures.Address with a different definition.
+type Address struct {
+	Lines []string `json:"lines"`
+}
+
+// Label has the same name and definition as fixtures.Label.
+type Label struct {
+	Text string `json:"text"`
+}
+
+type LabelStruct struct {
+	Name  Label          `json:"name"`
+	Title fixtures.Label `json:"title"`
+}
+
+type CollisionStruct struct {
+	Home  Address          `json:"home"`
+	Work  fixtures.Address `json:"work"`
+	Other Address          `json:"other"`
+}
+
+func TestReflector_TypeRefCollision(t *testing.T) {
+	r := reflector.NewReflector()
+	schema := r.DeriveSchema(CollisionStruct{}, "collision")
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	util.CompareStrings(t, "simple", gotStrings, []string{
+		`Root.{}:CollisionStruct`,
+		`TypeRef.!Address:{}! ERROR:type ref name used by different types`,
+		`TypeRef.!Address:{}!.Lines:[]`,
+		`TypeRef.!Address:{}!.Lines:[].string`,
+		`TypeRef.CollisionStruct:{}`,
+		`TypeRef.CollisionStruct:{}.Home:{}:Address`,
+		`TypeRef.CollisionStruct:{}.Other:{}:Address`,
+		`TypeRef.CollisionStruct:{}.Work:{}:Address`,
+	})
+
+	// Types with the same name and definition are not a collision.
+	schema = reflector.NewReflector().DeriveSchema(LabelStruct{}, "labels")
+
+	gotStrings, _ = simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	util.CompareStrings(t, "same", gotStrings, []string{
+		`Root.{}:LabelStruct`,
+		`TypeRef.Label:{}`,
+		`TypeRef.Label:{}.Text:string`,
+		`TypeRef.LabelStruct:{}`,
+		`TypeRef.LabelStruct:{}.Name:{}:Label`,
+		`TypeRef.LabelStruct:{}.Title:{}:Label`,
+	})
+
+	// Cached TypeRefs are checked for collisions.
+	r = reflector.NewReflector().EnableCache()
+	r.DeriveSchema(struct {
+		Home Address `json:"home"`
+	}{}, "home")
+	r.Reset()
+	r.DeriveSchema(struct {
+		Work fixtures.Address `json:"work"`
+	}{}, "work")
+	schema = r.DeriveSchema(struct {
+		Home Address `json:"home"`
+	}{}, "home")
+
+	gotStrings, _ = simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	util.CompareStrings(t, "cached", gotStrings, []string{
+		`Root.{}`,
+		`Root.{}.Home:{}:Address`,
+		`Root.{}`,
+		`Root.{}.Work:{}:Address`,
+		`TypeRef.!Address:{}! ERROR:type ref name used by different types`,
+		`TypeRef.!Address:{}!.City:string`,
+		`TypeRef.!Address:{}!.Street:string`,
+	})
+}
+
+func TestReflector_Errors(t *testing.T) {
+	r := reflector.NewReflector()
+	r.DeriveSchema(CompoundTypes{}, "compound")
+
+	gotStrings := []string{}
+	for _, e := range r.Errors() {
+		gotStrings = append(gotStrings, fmt.Sprintf("%s|%s|%s", e.Path, e.Type, e.Error))
+	}
+
+	wantStrings := []string{
+		`Root.{}.Interface|invalid|interface element is nil`,
+		`Root.{}.Map|map|map key type must be string`,
+		`Root.{}.PrivatePtr|struct|struct has no exported fields`,
+		`Root.{}.Slice.invalid|invalid|interface element is nil`,
+		`Root.{}.Struct|struct|empty struct not supported`,
+		`TypeRef.CompoundTypes.Interface|invalid|interface element is nil`,
+		`TypeRef.CompoundTypes.Map|map|map key type must be string`,
+		`TypeRef.CompoundTypes.Slice.invalid|invalid|interface element is nil`,
+		`TypeRef.CompoundTypes.Struct|struct|empty struct not supported`,
+		`TypeRef.PrivateStruct|struct|struct has no exported fields`,
+	}
+	util.CompareStrings(t, "compound", gotStrings, wantStrings)
+}
+
+func TestReflector_ErrorsInSync(t *testing.T) {
+	for _, value := range []interface{}{
+		CompoundTypes{}, CycleTest{}, CustomMarshalerTypes{},
+		InlineErrorTypes{}, KeyPatternTypes{}, PatternTypes{},
+	} {
+		schema := reflector.NewReflector().DeriveSchema(value, "sync")
+
+		// Native error details always come with a generic error on the node.
+		gotStrings := []string{}
+		schema.Walk(func(node *types.TypeNode) error {
+			if native := node.NativeDefault(); native != nil && native.Error != "" && node.Error == "" {
+				gotStrings = append(gotStrings, fmt.Sprintf("%s|%s", node.PathString(""), native.Error))
+			}
+			return nil
+		})
+		util.CompareStrings(t, fmt.Sprintf("%T", value), gotStrings, []string{})
+	}
+}
+
+func TestReflector_KnownTypePointer(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(NullableSpecialTypes{}, "known")
+
+	// Known types behind pointers are nullable values without a TypeRef.
+	gotStrings := []string{}
+	schema.Walk(func(node *types.TypeNode) error {
+		if node.Parent == nil || node.Parent.Name != "NullableSpecialTypes" {
+			return nil
+		}
+		_, hasRef := node.NativeDefault().Options.Lookup("TypeRef")
+		gotStrings = append(gotStrings, fmt.Sprintf("%s|%s|nullable=%t|ref=%q|%t", node.Name, node.Type, node.Nullable, node.TypeRef, hasRef))
+		return nil
+	})
+	util.CompareStrings(t, "nodes", gotStrings, []string{
+		`DateTime|datetime|nullable=true|ref=""|false`,
+		`Duration|duration|nullable=true|ref=""|false`,
+	})
+
+	gotRefs := []string{}
+	for _, ref := range schema.TypeRef.Children {
+		gotRefs = append(gotRefs, ref.Name)
+	}
+	util.CompareStrings(t, "typerefs", gotRefs, []string{"NullableSpecialTypes"})
+}
+
+func TestReflector_DeriveSchemaStrict(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   interface{}
+		strict  bool
+		wantErr string
+	}{
+		{
+			name:  "not-strict",
+			value: CompoundTypes{},
+		},
+		{
+			name:   "no-errors",
+			value:  GoodEntity{},
+			strict: true,
+		},
+		{
+			name:    "errors",
+			value:   CompoundTypes{},
+			strict:  true,
+			wantErr: "schema has 10 errors: Root.{}.Interface: interface element is nil; Root.{}.Map: map key type must be string; Root.{}.PrivatePtr: struct has no exported fields; and 7 more",
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.Strict = test.strict
+
+		schema, err := r.DeriveSchemaStrict(test.value, test.name)
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+
+		if schema == nil || gotErr != test.wantErr {
+			t.Errorf("TEST_FAIL %s: got=%q want=%q", test.name, gotErr, test.wantErr)
+		} else {
+			t.Logf("TEST_OK %s: got=%q", test.name, gotErr)
+		}
+	}
+
+	// Errors from earlier calls on the same Reflector are not reported again.
+	r := reflector.NewReflector()
+	r.Strict = true
+	if _, err := r.DeriveSchemaStrict(CompoundTypes{}, "first"); err == nil {
+		t.Errorf("TEST_FAIL first: want error")
+	}
+	if _, err := r.DeriveSchemaStrict(GoodEntity{}, "second"); err != nil {
+		t.Errorf("TEST_FAIL second: err=%s", err)
+	} else {
+		t.Logf("TEST_OK second")
+	}
+}
+
+func TestTypeNode_Order(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(BasicStruct{}, "order")
+
+	// Removing and re-adding a child moves it to the end of the slice.
+	n := schema.TypeRef.ChildByName("BasicStruct", nil).Copy()
+	first := n.Children[0]
+	n.RemoveChild(first)
+	n.AddChild(first)
+
+	opt := renderer.NewOptions()
+	opt.PreserveOrder = true
+
+	gotStrings := []string{}
+	for _, child := range renderer.Children(n, simple.NewSimpleRenderer(opt)) {
+		gotStrings = append(gotStrings, fmt.Sprintf("%d:%s", child.Order, child.Name))
+	}
+	wantStrings := []string{
+		`1:BoolVal`,
+		`2:IntVal`,
+		`3:Float64Val`,
+		`4:StringVal`,
+	}
+	util.CompareStrings(t, "order", gotStrings, wantStrings)
+}
+
+type YAMLTypes struct {
+	FirstName string `json:"firstName" yaml:"first_name"`
+	LastName  string `json:"lastName" yaml:"last_name,omitempty"`
+	JSONOnly  int    `json:"jsonOnly"`
+	YAMLOnly  bool   `yaml:"yaml_only"`
+	Skipped   string `json:"skipped" yaml:"-"`
+}
+
+// BigQueryTypes has bigquery column aliases for fields with json names.
+type BigQueryTypes struct {
+	UserID    int       `json:"userId" bigquery:"user_id"`
+	CreatedAt time.Time `json:"createdAt" bigquery:"created_at"`
+	Comment   string    `json:"comment"`
+	Internal  string    `json:"-" bigquery:"internal"`
+}
+
+func TestRenderer_Dialects(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		dialects []string
+		renderer func(opt *renderer.Options) renderer.Renderer
+		want     []string
+	}{
+		{
+			name:     "simple-default",
+			renderer: func(opt *renderer.Options) renderer.Renderer { return simple.NewSimpleRenderer(opt) },
+			want: []string{
+				`Root.{}:YAMLTypes`,
+				`TypeRef.YAMLTypes:{}`,
+				`TypeRef.YAMLTypes:{}.FirstName:string`,
+				`TypeRef.YAMLTypes:{}.JSONOnly:integer`,
+				`TypeRef.YAMLTypes:{}.LastName:string`,
+				`TypeRef.YAMLTypes:{}.Skipped:string`,
+				`TypeRef.YAMLTypes:{}.YAMLOnly:boolean`,
+			},
+		},
+		{
+			name:     "simple-yaml",
+			dialects: []string{"yaml"},
+			renderer: func(opt *renderer.Options) renderer.Renderer { return simple.NewSimpleRenderer(opt) },
+			want: []string{
+				`Root.{}:YAMLTypes`,
+				`TypeRef.YAMLTypes:{}`,
+				`TypeRef.YAMLTypes:{}.first_name:string`,
+				`TypeRef.YAMLTypes:{}.JSONOnly:integer`,
+				`TypeRef.YAMLTypes:{}.last_name:string`,
+				`TypeRef.YAMLTypes:{}.yaml_only:boolean`,
+			},
+		},
+		{
+			name:     "openapi-yaml",
+			dialects: []string{"yaml"},
+			renderer: func(opt *renderer.Options) renderer.Renderer {
+				return openapi.NewOpenAPIRenderer(openapi.NewMetaData("dialects", "v1.0.0"), opt)
+			},
+			want: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: dialects`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /dialects:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/YAMLTypes'`,
+				`components:`,
+				`  schemas:`,
+				`    YAMLTypes:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        first_name:`,
+				`          type: string`,
+				`        jsonOnly:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        last_name:`,
+				`          type: string`,
+				`        yaml_only:`,
+				`          type: boolean`,
+			},
+		},
+		{
+			name:     "openapi-bigquery",
+			value:    BigQueryTypes{},
+			dialects: []string{"bigquery"},
+			renderer: func(opt *renderer.Options) renderer.Renderer {
+				return openapi.NewOpenAPIRenderer(openapi.NewMetaData("dialects", "v1.0.0"), opt)
+			},
+			want: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: dialects`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /dialects:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/BigQueryTypes'`,
+				`components:`,
+				`  schemas:`,
+				`    BigQueryTypes:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        comment:`,
+				`          type: string`,
+				`        created_at:`,
+				`          type: string`,
+				`          format: date-time`,
+				`        internal:`,
+				`          type: string`,
+				`        user_id:`,
+				`          type: integer`,
+				`          format: int64`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		value := test.value
+		if value == nil {
+			value = YAMLTypes{}
+		}
+		schema := reflector.NewReflector().DeriveSchemaForOperation(value, "/dialects", "")
+
+		opt := renderer.NewOptions()
+		opt.Dialects = test.dialects
+
+		gotStrings, err := test.renderer(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}
+
+func TestRenderer_PreserveOrder(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(MainStruct{}, "order")
+
+	opt := renderer.NewOptions()
+	opt.PreserveOrder = true
+
+	gotStrings, _ := simple.NewSimpleRenderer(opt).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:MainStruct`,
+		`TypeRef.GoodEntity:{}`,
+		`TypeRef.GoodEntity:{}.Message:string`,
+		`TypeRef.GoodEntity:{}.IntVal:integer`,
+		`TypeRef.GoodEntity:{}.Same:boolean`,
+		`TypeRef.SimpleInt:integer`,
+		`TypeRef.OtherEntity:{}`,
+		`TypeRef.OtherEntity:{}.Status:string`,
+		`TypeRef.OtherEntity:{}.IntVal:integer`,
+		`TypeRef.OtherEntity:{}.FloatVal:float`,
+		`TypeRef.OtherEntity:{}.Same:boolean`,
+		`TypeRef.OtherEntity:{}.Simple:integer:SimpleInt`,
+		`TypeRef.OtherEntity:{}.MapNil:map{}`,
+		`TypeRef.OtherEntity:{}.MapNil:map{}.integer`,
+		`TypeRef.OtherEntity:{}.MapVal:map{}`,
+		`TypeRef.OtherEntity:{}.MapVal:map{}.integer`,
+		`TypeRef.OtherEntity:{}.Good:{}:GoodEntity`,
+		`TypeRef.OtherEntity:{}.GoodPtr:{}:GoodEntity`,
+		`TypeRef.OtherEntity:{}.GoodSlice:[]`,
+		`TypeRef.OtherEntity:{}.GoodSlice:[].{}:GoodEntity`,
+		`TypeRef.OtherEntity:{}.GoodPtrSlice:[]`,
+		`TypeRef.OtherEntity:{}.GoodPtrSlice:[].{}:GoodEntity`,
+		`TypeRef.OtherEntity:{}.AnonStruct:{}`,
+		`TypeRef.OtherEntity:{}.AnonStruct:{}.FieldOne:string`,
+		`TypeRef.OtherEntity:{}.AnonStruct:{}.FieldTwo:integer`,
+		`TypeRef.OtherEntity:{}.AnonStruct:{}.FieldThree:float`,
+		`TypeRef.MainStruct:{}`,
+		`TypeRef.MainStruct:{}.StringVal:string`,
+		`TypeRef.MainStruct:{}.IntVal:integer`,
+		`TypeRef.MainStruct:{}.FloatVal:float`,
+		`TypeRef.MainStruct:{}.BoolVal:boolean`,
+		`TypeRef.MainStruct:{}.SliceVal:[]`,
+		`TypeRef.MainStruct:{}.SliceVal:[].integer`,
+		`TypeRef.MainStruct:{}.!InterfaceVal:invalid! ERROR:interface element is nil`,
+		`TypeRef.MainStruct:{}.StructPtr:{}:GoodEntity`,
+		`TypeRef.MainStruct:{}.StructVal:{}:OtherEntity`,
+		`TypeRef.MainStruct:{}.StringPtr:string`,
+		`TypeRef.MainStruct:{}.DuplicateOne:string`,
+		`TypeRef.MainStruct:{}.!DuplicateTwo:string! ERROR:duplicate map key`,
+	}
+	util.CompareStrings(t, "order", gotStrings, wantStrings)
+}
+
+func TestRenderer_CyclicalGraph(t *testing.T) {
+	// Build a schema by hand where an element is its own child without a CyclicalReferenceErr.
+	schema := types.NewSchema("golang")
+	node := schema.Root.NewChild("")
+	node.MetaKey = "/nodes"
+	node.Type = generictype.Struct.String()
+
+	name := node.NewChild("Name")
+	name.Type = generictype.String.String()
+
+	next := node.NewChild("Next")
+	next.Type = generictype.Struct.String()
+	next.Children = []*types.TypeNode{name, next}
+
+	opt := renderer.NewOptions()
+	opt.DeReference = true
+
+	gotStrings, _ := simple.NewSimpleRenderer(opt).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}`,
+		`Root.{}.Name:string`,
+		`Root.{}.Next:{}`,
+		`Root.{}.Name:string`,
+		`Root.{}.!Next:{}! ERROR:cyclical reference`,
+	}
+	util.CompareStrings(t, "cycle/simple", gotStrings, wantStrings)
+
+	gotStrings, err := openapi.NewOpenAPIRenderer(openapi.NewMetaData("cycle", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL cycle/openapi: err=%s", err)
+	}
+	wantStrings = []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: cycle`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /nodes:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                type: object`,
+		`                additionalProperties: false`,
+		`                properties:`,
+		`                  Name:`,
+		`                    type: string`,
+		`                  Next:`,
+		`                    type: object`,
+		`                    additionalProperties: false`,
+		`                    properties:`,
+		`                      Name:`,
+		`                        type: string`,
+		`                      Next:`,
+		`                        description: 'ERROR=cyclical reference'`,
+		`                        type: object`,
+		`                        additionalProperties: false`,
+	}
+	util.CompareStrings(t, "cycle/openapi", gotStrings, wantStrings)
+}
+
+func TestRenderer_Settings(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(BasicStruct{}, "/basic")
+
+	opt := renderer.NewOptions()
+	r := simple.NewSimpleRenderer(opt)
+
+	// Settings apply to a single call.
+	gotStrings, err := r.ProcessSchema(schema, "deref=true")
+	if err != nil {
+		t.Fatalf("TEST_FAIL settings/deref: err=%s", err)
+	}
+	wantStrings := []string{
+		`Root.{}`,
+		`Root.{}.BoolVal:boolean`,
+		`Root.{}.Float64Val:float`,
+		`Root.{}.IntVal:integer`,
+		`Root.{}.StringVal:string`,
+	}
+	util.CompareStrings(t, "settings/deref", gotStrings, wantStrings)
+
+	gotStrings, _ = r.ProcessSchema(schema)
+	wantStrings = []string{
+		`Root.{}:BasicStruct`,
+		`TypeRef.BasicStruct:{}`,
+		`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+		`TypeRef.BasicStruct:{}.Float64Val:float`,
+		`TypeRef.BasicStruct:{}.IntVal:integer`,
+		`TypeRef.BasicStruct:{}.StringVal:string`,
+	}
+	util.CompareStrings(t, "settings/default", gotStrings, wantStrings)
+
+	if opt.DeReference {
+		t.Errorf("TEST_FAIL settings/options: DeReference changed")
+	}
+
+	// OpenAPI settings change the prefix for a single call.
+	o := openapi.NewOpenAPIRenderer(openapi.NewMetaData("settings", "v1.0.0"), nil)
+	gotStrings, err = o.ProcessSchema(schema, "deref", "prefix=    ")
+	if err != nil {
+		t.Fatalf("TEST_FAIL settings/openapi: err=%s", err)
+	}
+	wantStrings = []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`    title: settings`,
+		`    version: v1.0.0`,
+		``,
+		`paths:`,
+		`    /basic:`,
+		`        get:`,
+		`            summary: Return data.`,
+		`            responses:`,
+		`                '200':`,
+		`                    description: Success`,
+		`                    content:`,
+		`                        application/json:`,
+		`                            schema:`,
+		`                                description: 'From $ref: #/components/schemas/BasicStruct'`,
+		`                                type: object`,
+		`                                additionalProperties: false`,
+		`                                properties:`,
+		`                                    BoolVal:`,
+		`                                        type: boolean`,
+		`                                    Float64Val:`,
+		`                                        type: number`,
+		`                                        format: double`,
+		`                                    IntVal:`,
+		`                                        type: integer`,
+		`                                        format: int64`,
+		`                                    StringVal:`,
+		`                                        type: string`,
+	}
+	util.CompareStrings(t, "settings/openapi", gotStrings, wantStrings)
+
+	if o.Options.DeReference || o.Options.Prefix != "" {
+		t.Errorf("TEST_FAIL settings/openapi-options: options changed")
+	}
+
+	if _, err := o.ProcessSchema(schema, "unknown=true"); err == nil {
+		t.Errorf("TEST_FAIL settings/unknown: want error")
+	}
+}
+
+func TestRenderer_ExcludeErrored(t *testing.T) {
+	type PartialTypes struct {
+		Name    string       `json:"name"`
+		Count   int          `json:"count"`
+		Invalid InvalidTypes `json:"invalid"`
+		Chan    chan int     `json:"chan"`
+	}
+
+	schema := reflector.NewReflector().DeriveSchema(PartialTypes{}, "/partial")
+
+	opt := renderer.NewOptions()
+	opt.ExcludeErrored = true
+
+	gotStrings, _ := simple.NewSimpleRenderer(opt).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:PartialTypes`,
+		`TypeRef.InvalidTypes:{}`,
+		`TypeRef.PartialTypes:{}`,
+		`TypeRef.PartialTypes:{}.Count:integer`,
+		`TypeRef.PartialTypes:{}.Invalid:{}:InvalidTypes`,
+		`TypeRef.PartialTypes:{}.Name:string`,
+	}
+	util.CompareStrings(t, "exclude/simple", gotStrings, wantStrings)
+
+	gotStrings, _ = typescript.NewTypeScriptRenderer(opt).ProcessSchema(schema)
+	wantStrings = []string{
+		`export interface InvalidTypes {`,
+		`}`,
+		`export interface PartialTypes {`,
+		`  count: number;`,
+		`  invalid: InvalidTypes;`,
+		`  name: string;`,
+		`}`,
+	}
+	util.CompareStrings(t, "exclude/typescript", gotStrings, wantStrings)
+
+	gotStrings, err := openapi.NewOpenAPIRenderer(openapi.NewMetaData("exclude", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL exclude/openapi: err=%s", err)
+	}
+	wantStrings = []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: exclude`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /partial:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/PartialTypes'`,
+		`components:`,
+		`  schemas:`,
+		`    InvalidTypes:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`    PartialTypes:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        count:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        invalid:`,
+		`          $ref: '#/components/schemas/InvalidTypes'`,
+		`        name:`,
+		`          type: string`,
+	}
+	util.CompareStrings(t, "exclude/openapi", gotStrings, wantStrings)
+
+	if validateOpenAPI(t, "exclude", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK exclude: swagger")
+	}
+}
+
+func TestReflector_TreatGoMapsAsOpen(t *testing.T) {
+	type OpenMapStruct struct {
+		Counts map[string]int64
+		Object map[string]interface{}
+	}
+	value := OpenMapStruct{
+		Counts: map[string]int64{"one": 1, "two": 2},
+		Object: map[string]interface{}{"key": "value"},
+	}
+
+	testCases := []struct {
+		name string
+		open bool
+		want []string
+	}{
+		{
+			name: "struct",
+			want: []string{
+				`Root.{}:OpenMapStruct`,
+				`TypeRef.OpenMapStruct:{}`,
+				`TypeRef.OpenMapStruct:{}.Counts:{}`,
+				`TypeRef.OpenMapStruct:{}.Counts:{}.One:integer`,
+				`TypeRef.OpenMapStruct:{}.Counts:{}.Two:integer`,
+				`TypeRef.OpenMapStruct:{}.Object:{}`,
+				`TypeRef.OpenMapStruct:{}.Object:{}.Key:string`,
+			},
 		},
-	},
+		{
+			name: "open",
+			open: true,
+			want: []string{
+				`Root.{}:OpenMapStruct`,
+				`TypeRef.OpenMapStruct:{}`,
+				`TypeRef.OpenMapStruct:{}.Counts:map{}`,
+				`TypeRef.OpenMapStruct:{}.Counts:map{}.integer`,
+				`TypeRef.OpenMapStruct:{}.Object:{}`,
+				`TypeRef.OpenMapStruct:{}.Object:{}.Key:string`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.TreatGoMapsAsOpen = test.open
+		schema := r.DeriveSchema(value, test.name)
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
 }
 
-// StringStruct has one string field.
-type StringStruct struct {
-	Value string
+func TestReflector_AllowAnyInterface(t *testing.T) {
+	type AnyStruct struct {
+		Data   interface{}            `json:"data"`
+		Items  []interface{}          `json:"items"`
+		Values map[string]interface{} `json:"values"`
+	}
+
+	testCases := []struct {
+		name        string
+		allowAny    bool
+		wantSimple  []string
+		wantOpenAPI []string
+	}{
+		{
+			name: "default",
+			wantSimple: []string{
+				`Root.{}:AnyStruct`,
+				`TypeRef.AnyStruct:{}`,
+				`TypeRef.AnyStruct:{}.!Data:invalid! ERROR:interface element is nil`,
+				`TypeRef.AnyStruct:{}.Items:[]`,
+				`TypeRef.AnyStruct:{}.Items:[].!invalid! ERROR:interface element is nil`,
+				`TypeRef.AnyStruct:{}.Values:map{}`,
+				`TypeRef.AnyStruct:{}.Values:map{}.!invalid! ERROR:interface element is nil`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: any`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /default:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/AnyStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    AnyStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        data:`,
+				`          description: 'ERROR=interface element is nil'`,
+				`          type: string`,
+				`        items:`,
+				`          type: array`,
+				`          items:`,
+				`            description: 'ERROR=interface element is nil'`,
+				`            type: string`,
+				`        values:`,
+				`          type: object`,
+				`          additionalProperties: true`,
+			},
+		},
+		{
+			name:     "allow-any",
+			allowAny: true,
+			wantSimple: []string{
+				`Root.{}:AnyStruct`,
+				`TypeRef.AnyStruct:{}`,
+				`TypeRef.AnyStruct:{}.Data:any`,
+				`TypeRef.AnyStruct:{}.Items:[]`,
+				`TypeRef.AnyStruct:{}.Items:[].any`,
+				`TypeRef.AnyStruct:{}.Values:map{}`,
+				`TypeRef.AnyStruct:{}.Values:map{}.any`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: any`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /allow-any:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/AnyStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    AnyStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        data: {}`,
+				`        items:`,
+				`          type: array`,
+				`          items: {}`,
+				`        values:`,
+				`          type: object`,
+				`          additionalProperties: {}`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.AllowAnyInterface = test.allowAny
+		schema := r.DeriveSchema(AnyStruct{}, test.name)
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/simple", gotStrings, test.wantSimple)
+
+		gotStrings, _ = openapi.NewOpenAPIRenderer(openapi.NewMetaData("any", "v1.0.0"), nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/openapi", gotStrings, test.wantOpenAPI)
+	}
 }
 
-// Private Struct only has private fields.
-type PrivateStruct struct {
-	boolVal    bool
-	intVal     int
-	float64Val float64
-	stringVal  string
+func TestReflector_UnifyListTypes(t *testing.T) {
+	jsonValue := []byte(`{"mixed": [1, "two", true], "numbers": [1, 2.5]}`)
+
+	testCases := []struct {
+		name        string
+		unify       bool
+		wantSimple  []string
+		wantOpenAPI []string
+	}{
+		{
+			name: "default",
+			wantSimple: []string{
+				`Root.{}`,
+				`Root.{}.!Mixed:[]! ERROR:slice contains multiple kinds`,
+				`Root.{}.!Mixed:[]!.string`,
+				`Root.{}.Numbers:[]`,
+				`Root.{}.Numbers:[].float`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: unify`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /default:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  Mixed:`,
+				`                    description: 'ERROR=slice contains multiple kinds'`,
+				`                    nullable: true`,
+				`                    type: array`,
+				`                    items:`,
+				`                      nullable: true`,
+				`                      type: string`,
+				`                  Numbers:`,
+				`                    nullable: true`,
+				`                    type: array`,
+				`                    items:`,
+				`                      nullable: true`,
+				`                      type: number`,
+				`                      format: double`,
+			},
+		},
+		{
+			name:  "unify",
+			unify: true,
+			wantSimple: []string{
+				`Root.{}`,
+				`Root.{}.Mixed:[]`,
+				`Root.{}.Mixed:[].union`,
+				`Root.{}.Mixed:[].union.boolean`,
+				`Root.{}.Mixed:[].union.float`,
+				`Root.{}.Mixed:[].union.string`,
+				`Root.{}.Numbers:[]`,
+				`Root.{}.Numbers:[].float`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: unify`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /unify:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  Mixed:`,
+				`                    nullable: true`,
+				`                    type: array`,
+				`                    items:`,
+				`                      oneOf:`,
+				`                      - nullable: true`,
+				`                        type: boolean`,
+				`                      - nullable: true`,
+				`                        type: number`,
+				`                        format: double`,
+				`                      - nullable: true`,
+				`                        type: string`,
+				`                  Numbers:`,
+				`                    nullable: true`,
+				`                    type: array`,
+				`                    items:`,
+				`                      nullable: true`,
+				`                      type: number`,
+				`                      format: double`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.UnifyListTypes = test.unify
+		schema, err := r.DeriveSchemaFromJSON(jsonValue, test.name)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/simple", gotStrings, test.wantSimple)
+
+		gotStrings, _ = openapi.NewOpenAPIRenderer(openapi.NewMetaData("unify", "v1.0.0"), nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/openapi", gotStrings, test.wantOpenAPI)
+	}
 }
 
-// BasicStruct has one field for each basic type.
-type BasicStruct struct {
-	BoolVal    bool
-	IntVal     int
-	Float64Val float64
-	StringVal  string
+func TestReflector_AllowNonStructRoot(t *testing.T) {
+	testCases := []struct {
+		name         string
+		value        interface{}
+		allowNonRoot bool
+		wantSimple   []string
+		wantOpenAPI  []string
+	}{
+		{
+			name:  "default",
+			value: []BasicStruct{},
+			wantSimple: []string{
+				`Root.![]! ERROR:root type must be a struct`,
+				`TypeRef.BasicStruct:{}`,
+				`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+				`TypeRef.BasicStruct:{}.Float64Val:float`,
+				`TypeRef.BasicStruct:{}.IntVal:integer`,
+				`TypeRef.BasicStruct:{}.StringVal:string`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: root`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /default:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'ERROR=root type must be a struct'`,
+				`                type: array`,
+				`                items:`,
+				`components:`,
+				`  schemas:`,
+				`    BasicStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        BoolVal:`,
+				`          type: boolean`,
+				`        Float64Val:`,
+				`          type: number`,
+				`          format: double`,
+				`        IntVal:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        StringVal:`,
+				`          type: string`,
+			},
+		},
+		{
+			name:         "list",
+			value:        []BasicStruct{},
+			allowNonRoot: true,
+			wantSimple: []string{
+				`Root.[]`,
+				`Root.[].{}:BasicStruct`,
+				`TypeRef.BasicStruct:{}`,
+				`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+				`TypeRef.BasicStruct:{}.Float64Val:float`,
+				`TypeRef.BasicStruct:{}.IntVal:integer`,
+				`TypeRef.BasicStruct:{}.StringVal:string`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: root`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /list:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                type: array`,
+				`                items:`,
+				`                  $ref: '#/components/schemas/BasicStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    BasicStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        BoolVal:`,
+				`          type: boolean`,
+				`        Float64Val:`,
+				`          type: number`,
+				`          format: double`,
+				`        IntVal:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        StringVal:`,
+				`          type: string`,
+			},
+		},
+		{
+			name:         "list-ptr",
+			value:        []*BasicStruct{},
+			allowNonRoot: true,
+			wantSimple: []string{
+				`Root.[]`,
+				`Root.[].{}:BasicStruct`,
+				`TypeRef.BasicStruct:{}`,
+				`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+				`TypeRef.BasicStruct:{}.Float64Val:float`,
+				`TypeRef.BasicStruct:{}.IntVal:integer`,
+				`TypeRef.BasicStruct:{}.StringVal:string`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: root`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /list-ptr:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                type: array`,
+				`                items:`,
+				`                  nullable: true`,
+				`                  allOf:`,
+				`                  - $ref: '#/components/schemas/BasicStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    BasicStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        BoolVal:`,
+				`          type: boolean`,
+				`        Float64Val:`,
+				`          type: number`,
+				`          format: double`,
+				`        IntVal:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        StringVal:`,
+				`          type: string`,
+			},
+		},
+		{
+			name:         "map",
+			value:        map[string]BasicStruct{},
+			allowNonRoot: true,
+			wantSimple: []string{
+				`Root.map{}`,
+				`Root.map{}.{}:BasicStruct`,
+				`TypeRef.BasicStruct:{}`,
+				`TypeRef.BasicStruct:{}.BoolVal:boolean`,
+				`TypeRef.BasicStruct:{}.Float64Val:float`,
+				`TypeRef.BasicStruct:{}.IntVal:integer`,
+				`TypeRef.BasicStruct:{}.StringVal:string`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: root`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /map:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                type: object`,
+				`                additionalProperties:`,
+				`                  $ref: '#/components/schemas/BasicStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    BasicStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        BoolVal:`,
+				`          type: boolean`,
+				`        Float64Val:`,
+				`          type: number`,
+				`          format: double`,
+				`        IntVal:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        StringVal:`,
+				`          type: string`,
+			},
+		},
+		{
+			name:         "string",
+			value:        "",
+			allowNonRoot: true,
+			wantSimple: []string{
+				`Root.!string! ERROR:root type must be a struct`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: root`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /string:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'ERROR=root type must be a struct'`,
+				`                type: string`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.AllowNonStructRoot = test.allowNonRoot
+		schema := r.DeriveSchema(test.value, test.name)
+
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/simple", gotStrings, test.wantSimple)
+
+		gotStrings, _ = openapi.NewOpenAPIRenderer(openapi.NewMetaData("root", "v1.0.0"), nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/openapi", gotStrings, test.wantOpenAPI)
+	}
 }
 
-type MainStruct struct {
-	StringVal string `json:"stringVal,omitempty"`
-	IntVal    int    `json:"intVal" datastore:",noindex"`
-	FloatVal  float64
-	BoolVal   bool
+func TestReflector_StrictUintptr(t *testing.T) {
+	testCases := []struct {
+		name        string
+		strict      bool
+		wantSimple  []string
+		wantOpenAPI []string
+	}{
+		{
+			name: "default",
+			wantSimple: []string{
+				`Root.{}:IntegerTypes`,
+				`TypeRef.IntegerTypes:{}`,
+				`TypeRef.IntegerTypes:{}.Int:integer`,
+				`TypeRef.IntegerTypes:{}.Int16:integer`,
+				`TypeRef.IntegerTypes:{}.Int32:integer`,
+				`TypeRef.IntegerTypes:{}.Int64:integer`,
+				`TypeRef.IntegerTypes:{}.Int8:integer`,
+				`TypeRef.IntegerTypes:{}.Uint:integer`,
+				`TypeRef.IntegerTypes:{}.Uint16:integer`,
+				`TypeRef.IntegerTypes:{}.Uint32:integer`,
+				`TypeRef.IntegerTypes:{}.Uint64:integer`,
+				`TypeRef.IntegerTypes:{}.Uint8:integer`,
+				`TypeRef.IntegerTypes:{}.Uintptr:integer`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: uintptr`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /default:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/IntegerTypes'`,
+				`components:`,
+				`  schemas:`,
+				`    IntegerTypes:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        Int:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Int16:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Int32:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Int64:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Int8:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Uint16:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint32:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint64:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Uint8:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uintptr:`,
+				`          type: integer`,
+				`          format: int64`,
+			},
+		},
+		{
+			name:   "strict",
+			strict: true,
+			wantSimple: []string{
+				`Root.{}:IntegerTypes`,
+				`TypeRef.IntegerTypes:{}`,
+				`TypeRef.IntegerTypes:{}.Int:integer`,
+				`TypeRef.IntegerTypes:{}.Int16:integer`,
+				`TypeRef.IntegerTypes:{}.Int32:integer`,
+				`TypeRef.IntegerTypes:{}.Int64:integer`,
+				`TypeRef.IntegerTypes:{}.Int8:integer`,
+				`TypeRef.IntegerTypes:{}.Uint:integer`,
+				`TypeRef.IntegerTypes:{}.Uint16:integer`,
+				`TypeRef.IntegerTypes:{}.Uint32:integer`,
+				`TypeRef.IntegerTypes:{}.Uint64:integer`,
+				`TypeRef.IntegerTypes:{}.Uint8:integer`,
+				`TypeRef.IntegerTypes:{}.!Uintptr:invalid:uintptr! ERROR:kind not supported`,
+			},
+			wantOpenAPI: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: uintptr`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /strict:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/IntegerTypes'`,
+				`components:`,
+				`  schemas:`,
+				`    IntegerTypes:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        Int:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Int16:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Int32:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Int64:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Int8:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Uint16:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint32:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uint64:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        Uint8:`,
+				`          type: integer`,
+				`          format: int32`,
+				`        Uintptr:`,
+				`          description: 'ERROR=kind not supported;Kind=invalid:uintptr'`,
+				`          type: string`,
+			},
+		},
+	}
 
-	SliceVal []int
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		r.StrictUintptr = test.strict
+		schema := r.DeriveSchema(IntegerTypes{}, test.name)
 
-	InterfaceVal interface{}
+		gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/simple", gotStrings, test.wantSimple)
 
-	StructPtr *GoodEntity
-	StructVal OtherEntity
+		gotStrings, _ = openapi.NewOpenAPIRenderer(openapi.NewMetaData("uintptr", "v1.0.0"), nil).ProcessSchema(schema)
+		util.CompareStrings(t, test.name+"/openapi", gotStrings, test.wantOpenAPI)
+	}
+}
 
-	StringPtr *string
+func TestGoStructRenderer_JSONRoundTrip(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(fromJSON([]byte(jsonMapTests)), "json-map")
 
-	// Test duplicate JSON keys when capitalized.
-	DuplicateOne string
-	DuplicateTwo string `json:"duplicateOne"`
+	gotStrings, err := gostruct.NewGoStructRenderer(nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL json-map: err=%s", err)
+	}
 
-	privateVal string
+	wantStrings := []string{
+		"package schema",
+		"",
+		"type JsonMap struct {",
+		"\tMapOK *struct {",
+		"\t\tBoolVal  bool      `json:\"BoolVal\"`",
+		"\t\tFloatVal float64   `json:\"FloatVal\"`",
+		"\t\tIntVal   float64   `json:\"IntVal\"`",
+		"\t\tListVal  []float64 `json:\"ListVal\"`",
+		"\t\tMapVal   *struct {",
+		"\t\t\tKey1 string `json:\"Key1\"`",
+		"\t\t\tKey2 *struct {",
+		"\t\t\t\tDeepKey1 string  `json:\"DeepKey1\"`",
+		"\t\t\t\tDeepKey2 float64 `json:\"DeepKey2\"`",
+		"\t\t\t} `json:\"Key2\"`",
+		"\t\t} `json:\"MapVal\"`",
+		"\t\tStringVal string `json:\"StringVal\"`",
+		"\t} `json:\"MapOK\"`",
+		"}",
+	}
+	util.CompareStrings(t, "json-map", gotStrings, wantStrings)
+
+	// Generated source must compile.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "schema.go", strings.Join(gotStrings, "\n"), 0)
+	if err != nil {
+		t.Fatalf("TEST_FAIL json-map: parse err=%s", err)
+	}
+	conf := types2.Config{Importer: importer.Default()}
+	if _, err := conf.Check("schema", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("TEST_FAIL json-map: type check err=%s", err)
+	} else {
+		t.Logf("TEST_OK json-map: source compiles")
+	}
 }
 
-// define a struct for data storage
-type GoodEntity struct {
-	Message string
-	IntVal  int64
-	Same    bool
+// FakeUUID is a struct type that should render as a string.
+type FakeUUID struct {
+	Hi uint64
+	Lo uint64
+}
 
-	secret string
+func TestReflector_RegisterKnownType(t *testing.T) {
+	type UUIDStruct struct {
+		ID     FakeUUID
+		IDList []FakeUUID
+	}
+
+	r := reflector.NewReflector()
+	if err := r.RegisterKnownType(FakeUUID{}, "string", "uuid"); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+
+	// Invalid registrations return errors.
+	if err := r.RegisterKnownType(struct{ Key string }{}, "string", ""); err == nil {
+		t.Errorf("TEST_FAIL anonymous: want error")
+	}
+	if err := r.RegisterKnownType(FakeUUID{}, "uuid", ""); err == nil {
+		t.Errorf("TEST_FAIL unknown generic type: want error")
+	}
+
+	schema := r.DeriveSchema(UUIDStruct{}, "uuid")
+
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:UUIDStruct`,
+		`TypeRef.UUIDStruct:{}`,
+		`TypeRef.UUIDStruct:{}.ID:string`,
+		`TypeRef.UUIDStruct:{}.IDList:[]`,
+		`TypeRef.UUIDStruct:{}.IDList:[].string`,
+	}
+	util.CompareStrings(t, "simple", gotStrings, wantStrings)
+
+	// Format is stored on the native type, not as an option.
+	native := schema.TypeRef.ChildByName("UUIDStruct", nil).ChildByName("ID", nil).NativeDefault()
+	if _, ok := native.Options.Lookup("format"); native.Format != "uuid" || ok {
+		t.Errorf("TEST_FAIL native: format=%q option=%t", native.Format, ok)
+	} else {
+		t.Logf("TEST_OK native")
+	}
+
+	// Format is available to renderers.
+	yamlStrings, _ := openapi.NewOpenAPIRenderer(openapi.NewMetaData("uuid", "v1.0.0"), nil).ProcessSchema(schema)
+	yamlStr := strings.Join(yamlStrings, "\n")
+	if got := strings.Count(yamlStr, "format: uuid"); got != 2 {
+		t.Errorf("TEST_FAIL openapi: got=%d formats want=2\n%s", got, yamlStr)
+	} else {
+		t.Logf("TEST_OK openapi")
+	}
+
+	// The registered format takes precedence over global defaults.
+	opt := renderer.NewOptions()
+	opt.OptionDefaults["string.format"] = "global"
+	yamlStrings, _ = openapi.NewOpenAPIRenderer(openapi.NewMetaData("uuid", "v1.0.0"), opt).ProcessSchema(schema)
+	yamlStr = strings.Join(yamlStrings, "\n")
+	if got := strings.Count(yamlStr, "format: uuid"); got != 2 || strings.Contains(yamlStr, "format: global") {
+		t.Errorf("TEST_FAIL openapi-default: got=%d formats want=2\n%s", got, yamlStr)
+	} else {
+		t.Logf("TEST_OK openapi-default")
+	}
 }
 
-// Test named and un-named types.
-type SimpleString string
-type SimpleInt int64
-type SimpleFloat float64
-type SimpleBool bool
-type SimpleInterface interface{}
-type SimpleSlice []string
-type SimpleMap map[string]int64
-type SimpleStruct GoodEntity
-type SimpleStructSlice []GoodEntity
-type SimplePtr *GoodEntity
-type SimplePtrSlice []*GoodEntity
+func TestReflector_CustomMarshaler(t *testing.T) {
+	// flagged returns the paths of elements flagged with a custom marshaler.
+	flagged := func(schema *types.Schema) []string {
+		out := []string{}
+		schema.Walk(func(node *types.TypeNode) error {
+			native := node.NativeDefault()
+			if native.Options.Get(reflector.CUSTOM_MARSHALER_OPTION) == "true" && native.Error == types.CustomMarshalerErr {
+				out = append(out, strings.Join(simple.NewSimpleRenderer(nil).Path(node), "."))
+			}
+			return nil
+		})
+		return out
+	}
 
-type NamedEntity struct {
-	NamedString SimpleString `json:"namedString,omitempty"`
-	RealString  string
+	schema := reflector.NewReflector().DeriveSchema(CustomMarshalerTypes{}, "custom")
+	// The error stays on the TypeRef, references to it are not flagged.
+	util.CompareStrings(t, "flagged", flagged(schema), []string{
+		`Root.{}:CustomMarshalerTypes.!Price:{}:Money!`,
+		`Root.{}:CustomMarshalerTypes.!PricePtr:{}:Money!`,
+		`TypeRef.!Money:{}!`,
+	})
 
-	NamedInt SimpleInt
-	RealInt  int64
+	// A registered known type supplies the real schema.
+	r := reflector.NewReflector()
+	if err := r.RegisterKnownType(Money{}, "string", ""); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	schema = r.DeriveSchema(CustomMarshalerTypes{}, "custom")
+	util.CompareStrings(t, "known-flagged", flagged(schema), []string{})
 
-	NamedFloat SimpleFloat
-	RealFloat  float64
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	util.CompareStrings(t, "known-simple", gotStrings, []string{
+		`Root.{}:CustomMarshalerTypes`,
+		`TypeRef.CustomMarshalerTypes:{}`,
+		`TypeRef.CustomMarshalerTypes:{}.Name:string`,
+		`TypeRef.CustomMarshalerTypes:{}.Price:string`,
+		`TypeRef.CustomMarshalerTypes:{}.PricePtr:string`,
+	})
+}
 
-	NamedBool SimpleBool
-	RealBool  bool
+// Status is a string enum with declared constants.
+type Status string
 
-	NamedInterface SimpleInterface
-	RealInterface  interface{}
+const (
+	StatusActive   Status = "active"
+	StatusInactive Status = "inactive"
+	StatusPending  Status = "pending"
+)
 
-	NamedSlice SimpleSlice
-	RealSlice  []string
+// Version is an integer constant.
+type Version int
 
-	NamedMap SimpleMap
-	RealMap  map[string]int64
+const CurrentVersion Version = 2
+
+type RegisteredEnumTypes struct {
+	Status  Status   `json:"status"`
+	History []Status `json:"history"`
+	Version Version  `json:"version"`
+}
+
+func TestReflector_RegisterEnum(t *testing.T) {
+	r := reflector.NewReflector()
+	if err := r.RegisterEnum(StatusActive, StatusActive, StatusInactive, StatusPending); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	if err := r.RegisterEnum(CurrentVersion, CurrentVersion); err != nil {
+		t.Fatalf("TEST_FAIL register const: err=%s", err)
+	}
+
+	// Invalid registrations return errors.
+	if err := r.RegisterEnum("active", "active"); err == nil {
+		t.Errorf("TEST_FAIL unnamed: want error")
+	}
+	if err := r.RegisterEnum(FakeUUID{}, FakeUUID{}); err == nil {
+		t.Errorf("TEST_FAIL struct: want error")
+	}
+	if err := r.RegisterEnum(StatusActive); err == nil {
+		t.Errorf("TEST_FAIL no values: want error")
+	}
+	if err := r.RegisterEnum(StatusActive, 1); err == nil {
+		t.Errorf("TEST_FAIL wrong kind: want error")
+	}
+	if err := r.RegisterEnum(StatusActive, Status("a|b")); err == nil {
+		t.Errorf("TEST_FAIL separator: want error")
+	}
+
+	schema := r.DeriveSchema(RegisteredEnumTypes{}, "enum")
+
+	gotStrings, err := openapi.NewOpenAPIRenderer(openapi.NewMetaData("enum", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL openapi: err=%s", err)
+	}
+	util.CompareStrings(t, "openapi", gotStrings, []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: enum`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /enum:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/RegisteredEnumTypes'`,
+		`components:`,
+		`  schemas:`,
+		`    RegisteredEnumTypes:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        history:`,
+		`          type: array`,
+		`          items:`,
+		`            $ref: '#/components/schemas/Status'`,
+		`        status:`,
+		`          $ref: '#/components/schemas/Status'`,
+		`        version:`,
+		`          $ref: '#/components/schemas/Version'`,
+		`    Status:`,
+		`      type: string`,
+		`      enum:`,
+		`      - 'active'`,
+		`      - 'inactive'`,
+		`      - 'pending'`,
+		`    Version:`,
+		`      type: integer`,
+		`      format: int64`,
+		`      enum:`,
+		`      - 2`,
+	})
+
+	if validateOpenAPI(t, "enum", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK enum: swagger")
+	}
+}
+
+func TestReflector_RegisterInterfaceImplementations(t *testing.T) {
+	r := reflector.NewReflector()
+
+	// Invalid registrations return errors.
+	testCases := []struct {
+		name     string
+		ifacePtr interface{}
+		impls    []interface{}
+	}{
+		{name: "not-pointer", ifacePtr: Circle{}, impls: []interface{}{Circle{}}},
+		{name: "not-interface", ifacePtr: &Circle{}, impls: []interface{}{Circle{}}},
+		{name: "no-impls", ifacePtr: (*Shape)(nil)},
+		{name: "not-implemented", ifacePtr: (*Shape)(nil), impls: []interface{}{Square{}}},
+		{name: "nil-impl", ifacePtr: (*Shape)(nil), impls: []interface{}{nil}},
+	}
+
+	for _, test := range testCases {
+		if err := r.RegisterInterfaceImplementations(test.ifacePtr, test.impls...); err == nil {
+			t.Errorf("TEST_FAIL %s: want error", test.name)
+		} else {
+			t.Logf("TEST_OK %s: err=%s", test.name, err)
+		}
+	}
+
+	// Unregistered interfaces are still nil interface errors.
+	schema := r.DeriveSchema(ShapeTypes{}, "unregistered")
+	gotStrings, _ := simple.NewSimpleRenderer(nil).ProcessSchema(schema)
+	wantStrings := []string{
+		`Root.{}:ShapeTypes`,
+		`TypeRef.!Shape:invalid! ERROR:interface element is nil`,
+		`TypeRef.ShapeTypes:{}`,
+		`TypeRef.ShapeTypes:{}.Name:string`,
+		`TypeRef.ShapeTypes:{}.Shape:invalid:Shape`,
+		`TypeRef.ShapeTypes:{}.Shapes:[]`,
+		`TypeRef.ShapeTypes:{}.Shapes:[].invalid:Shape`,
+	}
+	util.CompareStrings(t, "unregistered", gotStrings, wantStrings)
+}
+
+// Pet is an interface with implementations distinguished by their "kind" property.
+type Pet interface {
+	PetKind() string
+}
+
+type Cat struct {
+	Kind  string `json:"kind"`
+	Lives int    `json:"lives"`
+}
+
+func (c Cat) PetKind() string { return "cat" }
+
+type Dog struct {
+	Kind  string `json:"kind"`
+	Breed string `json:"breed"`
+}
+
+func (d Dog) PetKind() string { return "dog" }
+
+type PetTypes struct {
+	Owner string `json:"owner"`
+	Pet   Pet    `json:"pet"`
+}
+
+func TestOpenAPIRenderer_Discriminator(t *testing.T) {
+	r := reflector.NewReflector()
+
+	// Discriminators need registered implementations.
+	if err := r.RegisterDiscriminator((*Pet)(nil), "kind", nil); err == nil {
+		t.Errorf("TEST_FAIL unregistered: want error")
+	}
+	if err := r.RegisterInterfaceImplementations((*Pet)(nil), Cat{}, Dog{}); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	if err := r.RegisterDiscriminator((*Pet)(nil), "kind", map[string]interface{}{"circle": Circle{}}); err == nil {
+		t.Errorf("TEST_FAIL not-implementation: want error")
+	}
+	if err := r.RegisterDiscriminator((*Pet)(nil), "", nil); err == nil {
+		t.Errorf("TEST_FAIL no-property: want error")
+	}
+	if err := r.RegisterDiscriminator((*Pet)(nil), "kind", map[string]interface{}{"cat": Cat{}, "dog": &Dog{}}); err != nil {
+		t.Fatalf("TEST_FAIL discriminator: err=%s", err)
+	}
+
+	schema := r.DeriveSchema(PetTypes{}, "pets")
+
+	testCases := []struct {
+		name        string
+		deReference bool
+		wantStrings []string
+	}{
+		{
+			name: "discriminator",
+			wantStrings: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: discriminator`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /pets:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/PetTypes'`,
+				`components:`,
+				`  schemas:`,
+				`    Cat:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        kind:`,
+				`          type: string`,
+				`        lives:`,
+				`          type: integer`,
+				`          format: int64`,
+				`    Dog:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        breed:`,
+				`          type: string`,
+				`        kind:`,
+				`          type: string`,
+				`    Pet:`,
+				`      oneOf:`,
+				`      - $ref: '#/components/schemas/Cat'`,
+				`      - $ref: '#/components/schemas/Dog'`,
+				`      discriminator:`,
+				`        propertyName: 'kind'`,
+				`        mapping:`,
+				`          'cat': '#/components/schemas/Cat'`,
+				`          'dog': '#/components/schemas/Dog'`,
+				`    PetTypes:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        owner:`,
+				`          type: string`,
+				`        pet:`,
+				`          $ref: '#/components/schemas/Pet'`,
+			},
+		},
+		{
+			name:        "discriminator-deref",
+			deReference: true,
+			wantStrings: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: discriminator`,
+				`  version: v1.0.0`,
+				``,
+				`paths:`,
+				`  /pets:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'From $ref: #/components/schemas/PetTypes'`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  owner:`,
+				`                    type: string`,
+				`                  pet:`,
+				`                    description: 'From $ref: #/components/schemas/Pet'`,
+				`                    oneOf:`,
+				`                    - description: 'From $ref: #/components/schemas/Cat'`,
+				`                      type: object`,
+				`                      additionalProperties: false`,
+				`                      properties:`,
+				`                        kind:`,
+				`                          type: string`,
+				`                        lives:`,
+				`                          type: integer`,
+				`                          format: int64`,
+				`                    - description: 'From $ref: #/components/schemas/Dog'`,
+				`                      type: object`,
+				`                      additionalProperties: false`,
+				`                      properties:`,
+				`                        breed:`,
+				`                          type: string`,
+				`                        kind:`,
+				`                          type: string`,
+				`                    discriminator:`,
+				`                      propertyName: 'kind'`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		o := openapi.NewOpenAPIRenderer(openapi.NewMetaData("discriminator", "v1.0.0"), &renderer.Options{DeReference: test.deReference})
+		gotStrings, err := o.ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.wantStrings)
+
+		if validateOpenAPI(t, test.name, strings.Join(gotStrings, "\n")) {
+			t.Logf("TEST_OK %s: swagger", test.name)
+		}
+	}
+}
+
+func TestOpenAPIRenderer_NamePrefix(t *testing.T) {
+	r := reflector.NewReflector()
+	if err := r.RegisterInterfaceImplementations((*Pet)(nil), Cat{}, Dog{}); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	if err := r.RegisterDiscriminator((*Pet)(nil), "kind", map[string]interface{}{"cat": Cat{}, "dog": Dog{}}); err != nil {
+		t.Fatalf("TEST_FAIL discriminator: err=%s", err)
+	}
+	r.DeriveSchemaForOperation(ResponseError{}, "/errors/last", "")
+	schema := r.DeriveSchemaForOperation(PetTypes{}, "/pets", "")
+
+	o := openapi.NewOpenAPIRenderer(openapi.NewMetaData("prefix", "v1.0.0"), &renderer.Options{NamePrefix: "v2"})
+	o.PathOptions["/pets"] = openapi.PathInfo{
+		Responses: map[string]openapi.ResponseSpec{
+			"200": {Description: "Pets found."},
+			"404": {Description: "No pets.", SchemaRef: "ResponseError"},
+		},
+	}
 
-	NamedStruct SimpleStruct
-	RealStruct  GoodEntity
+	gotStrings, err := o.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL prefix: err=%s", err)
+	}
+	util.CompareStrings(t, "prefix", gotStrings, []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: prefix`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /errors/last:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/V2ResponseError'`,
+		`  /pets:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: 'Pets found.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/V2PetTypes'`,
+		`        '404':`,
+		`          description: 'No pets.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/V2ResponseError'`,
+		`components:`,
+		`  schemas:`,
+		`    V2Cat:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        kind:`,
+		`          type: string`,
+		`        lives:`,
+		`          type: integer`,
+		`          format: int64`,
+		`    V2Dog:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        breed:`,
+		`          type: string`,
+		`        kind:`,
+		`          type: string`,
+		`    V2Pet:`,
+		`      oneOf:`,
+		`      - $ref: '#/components/schemas/V2Cat'`,
+		`      - $ref: '#/components/schemas/V2Dog'`,
+		`      discriminator:`,
+		`        propertyName: 'kind'`,
+		`        mapping:`,
+		`          'cat': '#/components/schemas/V2Cat'`,
+		`          'dog': '#/components/schemas/V2Dog'`,
+		`    V2PetTypes:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        owner:`,
+		`          type: string`,
+		`        pet:`,
+		`          $ref: '#/components/schemas/V2Pet'`,
+		`    V2ResponseError:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        code:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        message:`,
+		`          type: string`,
+	})
 
-	NamedStructSlice SimpleStructSlice
-	RealStructSlice  []GoodEntity
+	// Every reference has a definition.
+	definitions := map[string]bool{}
+	for _, line := range gotStrings {
+		if strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "     ") && strings.HasSuffix(line, ":") {
+			definitions[strings.TrimSuffix(strings.TrimSpace(line), ":")] = true
+		}
+	}
+	refRegexp := regexp.MustCompile(`'#/components/schemas/([^']+)'`)
+	for _, line := range gotStrings {
+		for _, m := range refRegexp.FindAllStringSubmatch(line, -1) {
+			if !definitions[m[1]] {
+				t.Errorf("TEST_FAIL prefix: no definition for %q", m[1])
+			}
+		}
+	}
 
-	NamedPtr SimplePtr
-	RealPtr  *GoodEntity
+	if validateOpenAPI(t, "prefix", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK prefix: swagger")
+	}
+}
 
-	NamedPtrSlice SimplePtrSlice
-	RealPtrSlice  []*GoodEntity
+// ExtensionStruct has vendor extensions in struct tags.
+type ExtensionStruct struct {
+	ID    string        `json:"id" b9schema:"x-go-type=uuid.UUID,x-order=1"`
+	Owner *StringStruct `json:"owner" b9schema:"x-go-type-skip-optional-pointer"`
+	Count int           `json:"count"`
 }
 
-// define a different struct to test mismatched structs
-type OtherEntity struct {
-	Status   string
-	IntVal   int64
-	FloatVal float64
-	Same     bool
-	Simple   SimpleInt
+func TestOpenAPIRenderer_Extensions(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(ExtensionStruct{}, "extensions")
 
-	MapNil map[string]int64
-	MapVal map[string]int64
+	// Global defaults are extensions of all elements of a type.
+	opt := renderer.NewOptions()
+	opt.EmitExtensions = true
+	opt.OptionDefaults["integer.x-order"] = "3"
 
-	Good         GoodEntity
-	GoodPtr      *GoodEntity
-	GoodSlice    []GoodEntity
-	GoodPtrSlice []*GoodEntity
+	gotStrings, err := openapi.NewOpenAPIRenderer(openapi.NewMetaData("extensions", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL extensions: err=%s", err)
+	}
+	util.CompareStrings(t, "extensions", gotStrings, []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: extensions`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /extensions:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/ExtensionStruct'`,
+		`components:`,
+		`  schemas:`,
+		`    ExtensionStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        count:`,
+		`          type: integer`,
+		`          format: int64`,
+		`          x-order: 3`,
+		`        id:`,
+		`          type: string`,
+		`          x-go-type: 'uuid.UUID'`,
+		`          x-order: 1`,
+		`        owner:`,
+		`          nullable: true`,
+		`          x-go-type-skip-optional-pointer: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/StringStruct'`,
+		`    StringStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        Value:`,
+		`          type: string`,
+	})
 
-	AnonStruct struct {
-		FieldOne   string
-		FieldTwo   int32
-		FieldThree float32
+	if validateOpenAPI(t, "extensions", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK extensions: swagger")
 	}
-}
 
-// Test nested data structures.
-type OuterStruct struct {
-	ID    int          `json:"id"`
-	Inner *InnerStruct `json:"inner"`
+	// Extensions are not rendered by default.
+	gotStrings, err = openapi.NewOpenAPIRenderer(openapi.NewMetaData("extensions", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL no-extensions: err=%s", err)
+	}
+	if got := strings.Join(gotStrings, "\n"); strings.Contains(got, "x-") {
+		t.Errorf("TEST_FAIL no-extensions: unexpected extension\n%s", got)
+	} else {
+		t.Logf("TEST_OK no-extensions")
+	}
 }
 
-type InnerStruct struct {
-	ListOfStrings []string       `json:"listOfStrings"`
-	ListOfStructs []*BasicStruct `json:"listOfStructs"`
-}
+func TestOpenAPIRenderer_MultiFile(t *testing.T) {
+	r := reflector.NewReflector()
+	if err := r.RegisterInterfaceImplementations((*Pet)(nil), Cat{}, Dog{}); err != nil {
+		t.Fatalf("TEST_FAIL register: err=%s", err)
+	}
+	if err := r.RegisterDiscriminator((*Pet)(nil), "kind", map[string]interface{}{"cat": Cat{}, "dog": Dog{}}); err != nil {
+		t.Fatalf("TEST_FAIL discriminator: err=%s", err)
+	}
+	r.DeriveSchemaForOperation(ResponseError{}, "/errors/last", "")
+	schema := r.DeriveSchemaForOperation(PetTypes{}, "/pets", "")
 
-// fromJSON converts a JSON string into an interface.
-func fromJSON(in []byte) interface{} {
-	var out interface{}
+	o := openapi.NewOpenAPIRenderer(openapi.NewMetaData("multi-file", "v1.0.0"), nil)
+	o.PathOptions["/pets"] = openapi.PathInfo{
+		Responses: map[string]openapi.ResponseSpec{
+			"200": {Description: "Pets found."},
+			"404": {Description: "No pets.", SchemaRef: "ResponseError"},
+		},
+	}
 
-	if err := json.Unmarshal(in, &out); err != nil {
-		err = fmt.Errorf("ERROR json.Unmarshal: %s\n%s", err, string(in))
-		fmt.Println(err)
-		return err
+	files, err := o.ProcessSchemaMultiFile(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL multi-file: err=%s", err)
 	}
 
-	// // DEBUGXXXXX Print indented JSON string.
-	// if out != nil {
-	// 	if b, err := json.MarshalIndent(out, "", "  "); err == nil {
-	// 		fmt.Println(string(b))
-	// 	}
-	// }
+	fileNames := []string{}
+	for name := range files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+	util.CompareStrings(t, "file-names", fileNames, []string{
+		`openapi.yaml`,
+		`schemas/Cat.yaml`,
+		`schemas/Dog.yaml`,
+		`schemas/Pet.yaml`,
+		`schemas/PetTypes.yaml`,
+		`schemas/ResponseError.yaml`,
+	})
 
-	return out
+	util.CompareStrings(t, "main", files[openapi.MAIN_FILE], []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: multi-file`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /errors/last:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: './schemas/ResponseError.yaml#/ResponseError'`,
+		`  /pets:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: 'Pets found.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: './schemas/PetTypes.yaml#/PetTypes'`,
+		`        '404':`,
+		`          description: 'No pets.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: './schemas/ResponseError.yaml#/ResponseError'`,
+	})
+	util.CompareStrings(t, "pet", files["schemas/Pet.yaml"], []string{
+		`Pet:`,
+		`  oneOf:`,
+		`  - $ref: './Cat.yaml#/Cat'`,
+		`  - $ref: './Dog.yaml#/Dog'`,
+		`  discriminator:`,
+		`    propertyName: 'kind'`,
+		`    mapping:`,
+		`      'cat': './Cat.yaml#/Cat'`,
+		`      'dog': './Dog.yaml#/Dog'`,
+	})
+
+	// Every external reference points to a file with the referenced key.
+	refRegexp := regexp.MustCompile(`'\./(\S+)\.yaml#/([^']+)'`)
+	for name, lines := range files {
+		dir := path.Dir(name)
+		for _, line := range lines {
+			for _, m := range refRegexp.FindAllStringSubmatch(line, -1) {
+				target := files[path.Join(dir, m[1]+".yaml")]
+				if len(target) == 0 || target[0] != m[2]+":" {
+					t.Errorf("TEST_FAIL multi-file: %s has no definition for %q", name, m[0])
+				}
+			}
+		}
+	}
+
+	// The main file is valid with its schema files.
+	dir := t.TempDir()
+	for name, lines := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("TEST_FAIL multi-file: err=%s", err)
+		}
+		if err := os.WriteFile(fileName, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatalf("TEST_FAIL multi-file: err=%s", err)
+		}
+	}
+	if validateOpenAPIFile(t, "multi-file", filepath.Join(dir, openapi.MAIN_FILE), strings.Join(files[openapi.MAIN_FILE], "\n")) {
+		t.Logf("TEST_OK multi-file: swagger")
+	}
+
+	// Schemas are inline if de-referencing.
+	o.Options.DeReference = true
+	files, err = o.ProcessSchemaMultiFile(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL multi-file-deref: err=%s", err)
+	}
+	if len(files) != 1 || len(files[openapi.MAIN_FILE]) == 0 {
+		t.Errorf("TEST_FAIL multi-file-deref: got=%d files want=1", len(files))
+	} else {
+		t.Logf("TEST_OK multi-file-deref")
+	}
 }
 
-// makeJSON converts an interface to JSON.
-func makeJSON(x interface{}) interface{} {
-	var s = "hey"
+func TestOpenAPIRenderer_Security(t *testing.T) {
+	meta := openapi.NewMetaData("security", "v1.0.0")
+	meta.AddSecurityScheme("bearerAuth", openapi.NewBearerAuth("JWT"))
+	meta.AddSecurityScheme("apiKeyAuth", openapi.NewApiKeyAuth("X-API-Key", "header"))
+	meta.Security = []openapi.SecurityRequirementObject{{"bearerAuth": nil}}
 
-	x = &MainStruct{
-		StringVal: "hello",
-		IntVal:    123,
-		FloatVal:  234.345,
-		BoolVal:   true,
-		SliceVal:  []int{1, 2, 3},
-		StructPtr: &GoodEntity{
-			Message: "hi",
-			IntVal:  234,
-			Same:    true,
-			secret:  "eyes only",
-		},
-		StructVal: OtherEntity{
-			Status:   "ok",
-			IntVal:   789,
-			FloatVal: 789.123,
-			Same:     true,
-			MapVal:   map[string]int64{"one": 234, "two": 345, "three": 456},
-			Good: GoodEntity{
-				Message: "",
-				IntVal:  0,
-				Same:    false,
-				secret:  "",
+	r := reflector.NewReflector()
+	r.DeriveSchemaForOperation(BasicStruct{}, "/users", "")
+	r.DeriveSchemaForOperation(BasicStruct{}, "/keys", "")
+	schema := r.DeriveSchemaForOperation(BasicStruct{}, "/health", "")
+
+	testCases := []struct {
+		name        string
+		deReference bool
+		wantStrings []string
+	}{
+		{
+			name: "security",
+			wantStrings: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: security`,
+				`  version: v1.0.0`,
+				`security:`,
+				`  - bearerAuth: []`,
+				``,
+				`paths:`,
+				`  /health:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      security: []`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/BasicStruct'`,
+				`  /keys:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      security:`,
+				`      - apiKeyAuth: []`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/BasicStruct'`,
+				`  /users:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                $ref: '#/components/schemas/BasicStruct'`,
+				`components:`,
+				`  schemas:`,
+				`    BasicStruct:`,
+				`      type: object`,
+				`      additionalProperties: false`,
+				`      properties:`,
+				`        BoolVal:`,
+				`          type: boolean`,
+				`        Float64Val:`,
+				`          type: number`,
+				`          format: double`,
+				`        IntVal:`,
+				`          type: integer`,
+				`          format: int64`,
+				`        StringVal:`,
+				`          type: string`,
+				`  securitySchemes:`,
+				`    apiKeyAuth:`,
+				`      type: apiKey`,
+				`      name: X-API-Key`,
+				`      in: header`,
+				`    bearerAuth:`,
+				`      type: http`,
+				`      scheme: bearer`,
+				`      bearerFormat: JWT`,
 			},
-			GoodPtr: &GoodEntity{
-				Message: "hi",
-				IntVal:  234,
-				Same:    true,
-				secret:  "eyes only",
+		},
+		{
+			name:        "security-deref",
+			deReference: true,
+			wantStrings: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: security`,
+				`  version: v1.0.0`,
+				`security:`,
+				`  - bearerAuth: []`,
+				``,
+				`paths:`,
+				`  /health:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      security: []`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'From $ref: #/components/schemas/BasicStruct'`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  BoolVal:`,
+				`                    type: boolean`,
+				`                  Float64Val:`,
+				`                    type: number`,
+				`                    format: double`,
+				`                  IntVal:`,
+				`                    type: integer`,
+				`                    format: int64`,
+				`                  StringVal:`,
+				`                    type: string`,
+				`  /keys:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      security:`,
+				`      - apiKeyAuth: []`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'From $ref: #/components/schemas/BasicStruct'`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  BoolVal:`,
+				`                    type: boolean`,
+				`                  Float64Val:`,
+				`                    type: number`,
+				`                    format: double`,
+				`                  IntVal:`,
+				`                    type: integer`,
+				`                    format: int64`,
+				`                  StringVal:`,
+				`                    type: string`,
+				`  /users:`,
+				`    get:`,
+				`      summary: Return data.`,
+				`      responses:`,
+				`        '200':`,
+				`          description: Success`,
+				`          content:`,
+				`            application/json:`,
+				`              schema:`,
+				`                description: 'From $ref: #/components/schemas/BasicStruct'`,
+				`                type: object`,
+				`                additionalProperties: false`,
+				`                properties:`,
+				`                  BoolVal:`,
+				`                    type: boolean`,
+				`                  Float64Val:`,
+				`                    type: number`,
+				`                    format: double`,
+				`                  IntVal:`,
+				`                    type: integer`,
+				`                    format: int64`,
+				`                  StringVal:`,
+				`                    type: string`,
+				`components:`,
+				`  securitySchemes:`,
+				`    apiKeyAuth:`,
+				`      type: apiKey`,
+				`      name: X-API-Key`,
+				`      in: header`,
+				`    bearerAuth:`,
+				`      type: http`,
+				`      scheme: bearer`,
+				`      bearerFormat: JWT`,
 			},
-			GoodSlice:    []GoodEntity{},
-			GoodPtrSlice: []*GoodEntity{},
 		},
-		StringPtr: &s,
+	}
 
-		DuplicateOne: "one",
-		DuplicateTwo: "two",
+	for _, test := range testCases {
+		opt := renderer.NewOptions()
+		opt.DeReference = test.deReference
 
-		privateVal: "shh",
+		o := openapi.NewOpenAPIRenderer(meta, opt)
+		o.PathOptions["/keys"] = openapi.PathInfo{Security: []openapi.SecurityRequirementObject{{"apiKeyAuth": nil}}}
+		o.PathOptions["/health"] = openapi.PathInfo{Security: []openapi.SecurityRequirementObject{}}
+
+		gotStrings, err := o.ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.wantStrings)
+
+		if validateOpenAPI(t, test.name, strings.Join(gotStrings, "\n")) {
+			t.Logf("TEST_OK %s: swagger", test.name)
+		}
 	}
 
-	if b, err := json.Marshal(x); err != nil {
-		return fmt.Errorf("ERROR json.Marshal: %s", err)
-	} else {
-		return fromJSON(b)
+	// Operations must reference known security schemes.
+	o := openapi.NewOpenAPIRenderer(meta, nil)
+	o.PathOptions["/users"] = openapi.PathInfo{Security: []openapi.SecurityRequirementObject{{"unknown": nil}}}
+	if _, err := o.ProcessSchema(schema); err == nil {
+		t.Errorf("TEST_FAIL unknown: want error")
 	}
 }
 
-func TestReflector_AllTests(t *testing.T) {
-	// Booleans for deref looping.
-	derefFlags := []bool{false, true}
-
-	// want represents strings for deref false/true
-	var want map[bool][]string
-	var format string
-	var gotStrings []string
+func TestOpenAPIRenderer_Tags(t *testing.T) {
+	meta := openapi.NewMetaData("tags", "v1.0.0")
+	meta.Tags = []*openapi.TagObject{
+		{Name: "users", Description: "Operations on users."},
+		{Name: "health", Description: "Health checks.", ExternalDocs: &openapi.ExternalDocumentationObject{URL: "https://docs.site.com/health"}},
+	}
 
 	r := reflector.NewReflector()
-	opt := renderer.NewOptions()
+	r.DeriveSchemaForOperation(BasicStruct{}, "/users", "")
+	schema := r.DeriveSchemaForOperation(BasicStruct{}, "/health", "")
 
-	// testName builds a test name string.
-	testName := func(group, name, format string, deref bool) string {
-		return fmt.Sprintf("%s/%s/%t/%s", group, name, deref, format)
-	}
+	o := openapi.NewOpenAPIRenderer(meta, nil)
+	o.PathOptions["/users"] = openapi.PathInfo{Tags: []string{"users"}}
+	o.PathOptions["/health"] = openapi.PathInfo{Tags: []string{"health"}}
 
-	// Build sorted list of test keys.
-	allKeys := []string{}
-	for k := range allTests {
-		allKeys = append(allKeys, k)
+	gotStrings, err := o.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL tags: err=%s", err)
 	}
-	sort.Strings(allKeys)
-
-	for _, testGroup := range allKeys {
-		testCases := allTests[testGroup]
 
-		for _, test := range testCases {
-			r.Reset()
-			gotSchema := r.DeriveSchema(test.Value, testGroup+"/"+test.Name)
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: tags`,
+		`  version: v1.0.0`,
+		`tags:`,
+		`  - name: users`,
+		`    description: Operations on users.`,
+		`  - name: health`,
+		`    description: Health checks.`,
+		`    externalDocs:`,
+		`      url: https://docs.site.com/health`,
+		``,
+		`paths:`,
+		`  /health:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      tags:`,
+		`      - 'health'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/BasicStruct'`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      tags:`,
+		`      - 'users'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/BasicStruct'`,
+		`components:`,
+		`  schemas:`,
+		`    BasicStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        BoolVal:`,
+		`          type: boolean`,
+		`        Float64Val:`,
+		`          type: number`,
+		`          format: double`,
+		`        IntVal:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        StringVal:`,
+		`          type: string`,
+	}
+	util.CompareStrings(t, "tags", gotStrings, wantStrings)
 
-			format = "reflector"
-			want = test.Want[format]
-			if want != nil {
-				for _, deref := range derefFlags {
-					wantStrings := want[deref]
-					if len(wantStrings) > 0 {
-						name := testName(testGroup, test.Name, format, deref)
+	if validateOpenAPI(t, "tags", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK tags: swagger")
+	}
+}
 
-						if b, err := yaml.Marshal(gotSchema.CopyWithoutNative()); err != nil {
-							t.Errorf("TEST_FAIL %s: yaml err=%s", name, err)
-							continue
-						} else {
-							gotStrings = strings.Split(string(b), "\n")
-						}
+// ResponseError is a response body for errors.
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
 
-						util.CompareStrings(t, name, gotStrings, wantStrings)
-					}
-				}
-			}
+func TestOpenAPIRenderer_Responses(t *testing.T) {
+	r := reflector.NewReflector()
+	r.DeriveSchemaForOperation(ResponseError{}, "/errors/last", "")
+	r.DeriveSchemaForOperation(BasicStruct{}, "/users", "")
+	schema := r.DeriveSchemaForOperation(BasicStruct{}, "/users", "post")
 
-			format = "simple"
-			want = test.Want[format]
-			if want != nil {
-				for _, deref := range derefFlags {
-					wantStrings := want[deref]
-					if len(wantStrings) > 0 {
-						name := testName(testGroup, test.Name, format, deref)
-						opt.DeReference = deref
+	o := openapi.NewOpenAPIRenderer(openapi.NewMetaData("responses", "v1.0.0"), nil)
+	o.PathOptions["/users"] = openapi.PathInfo{
+		Responses: map[string]openapi.ResponseSpec{
+			"200": {Description: "Users found."},
+			"404": {Description: "No users.", SchemaRef: "ResponseError"},
+		},
+	}
+	o.PathOptions["/users post"] = openapi.PathInfo{
+		Responses: map[string]openapi.ResponseSpec{
+			"201":     {Description: "User created."},
+			"default": {Description: "Unexpected error.", SchemaRef: "ResponseError"},
+		},
+	}
 
-						r := simple.NewSimpleRenderer(opt)
-						gotStrings, err := r.ProcessSchema(gotSchema)
-						if err != nil {
-							t.Errorf("TEST_FAIL %s: %q err=%s", name, format, err)
-							continue
-						}
+	gotStrings, err := o.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL responses: err=%s", err)
+	}
+	util.CompareStrings(t, "responses", gotStrings, []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: responses`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /errors/last:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/ResponseError'`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: 'Users found.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/BasicStruct'`,
+		`        '404':`,
+		`          description: 'No users.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/ResponseError'`,
+		`    post:`,
+		`      summary: Send data.`,
+		`      requestBody:`,
+		`        required: true`,
+		`        content:`,
+		`          application/json:`,
+		`            schema:`,
+		`              $ref: '#/components/schemas/BasicStruct'`,
+		`      responses:`,
+		`        '201':`,
+		`          description: 'User created.'`,
+		`        'default':`,
+		`          description: 'Unexpected error.'`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/ResponseError'`,
+		`components:`,
+		`  schemas:`,
+		`    BasicStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        BoolVal:`,
+		`          type: boolean`,
+		`        Float64Val:`,
+		`          type: number`,
+		`          format: double`,
+		`        IntVal:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        StringVal:`,
+		`          type: string`,
+		`    ResponseError:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        code:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        message:`,
+		`          type: string`,
+	})
 
-						util.CompareStrings(t, name, gotStrings, wantStrings)
-					}
-				}
-			}
+	if validateOpenAPI(t, "responses", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK responses: swagger")
+	}
 
-			format = "openapi"
-			want = test.Want[format]
-			if want != nil {
-				for _, deref := range derefFlags {
-					wantStrings := want[deref]
-					if len(wantStrings) > 0 {
-						name := testName(testGroup, test.Name, format, deref)
-						opt.DeReference = deref
-						opt.Indent = 0
+	// Invalid responses return errors.
+	for code, spec := range map[string]openapi.ResponseSpec{
+		"600": {Description: "Unknown."},
+		"404": {},
+		"200": {Description: "Users found.", SchemaRef: "ResponseError"},
+	} {
+		o.PathOptions["/users"] = openapi.PathInfo{Responses: map[string]openapi.ResponseSpec{code: spec}}
+		if _, err := o.ProcessSchema(schema); err == nil {
+			t.Errorf("TEST_FAIL invalid %s: want error", code)
+		} else {
+			t.Logf("TEST_OK invalid %s: err=%s", code, err)
+		}
+	}
+}
 
-						r := openapi.NewOpenAPIRenderer(openapi.NewMetaData(test.Name, "v1.0.0"), opt)
-						gotStrings, err := r.ProcessSchema(gotSchema)
+func TestOpenAPIRenderer_AlwaysEmitComponents(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchemaForOperation(AStruct{}, "/cycle", "")
 
-						if err != nil {
-							t.Errorf("TEST_FAIL %s: %q err=%s", name, format, err)
-							continue
-						}
+	opt := renderer.NewOptions()
+	opt.DeReference = true
 
-						if !util.CompareStrings(t, name, gotStrings, wantStrings) {
-							continue
-						}
+	r := openapi.NewOpenAPIRenderer(openapi.NewMetaData("components", "v1.0.0"), opt)
+	r.AlwaysEmitComponents = true
 
-						// Verify that YAML is valid.
-						yamlStr := strings.Join(gotStrings, "\n")
-						var yamlOut interface{}
-						if err := yaml.Unmarshal([]byte(yamlStr), &yamlOut); err != nil {
-							util.OutputErrStrings(t, name, gotStrings, fmt.Errorf("yaml err=%s", err))
-							continue
-						} else if b, err := json.Marshal(yamlOut); err != nil {
-							t.Errorf("TEST_FAIL %s: json err=%s", name, err)
-						} else {
-							_ = b
-							//t.Logf("TEST_OK %s: yaml/json=%s", test.Name, string(b))
-							//t.Logf("TEST_OK %s: yaml/json len=%d", test.Name, len(b))
-						}
+	gotStrings, err := r.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL components: err=%s", err)
+	}
 
-						// Verify that OpenAPI is valid.
-						if !validateOpenAPI(t, name, yamlStr) {
-							continue
-						}
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: components`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /cycle:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                description: 'From $ref: #/components/schemas/AStruct'`,
+		`                type: object`,
+		`                additionalProperties: false`,
+		`                properties:`,
+		`                  aChild:`,
+		`                    description: 'From $ref: #/components/schemas/BStruct'`,
+		`                    nullable: true`,
+		`                    type: object`,
+		`                    additionalProperties: false`,
+		`                    properties:`,
+		`                      bChild:`,
+		`                        description: 'From $ref: #/components/schemas/CStruct'`,
+		`                        nullable: true`,
+		`                        type: object`,
+		`                        additionalProperties: false`,
+		`                        properties:`,
+		`                          cChild:`,
+		`                            description: 'From $ref: #/components/schemas/AStruct;ERROR=cyclical reference'`,
+		`                            nullable: true`,
+		`                            type: object`,
+		`                            additionalProperties: false`,
+		`                          cName:`,
+		`                            type: string`,
+		`                      bName:`,
+		`                        type: string`,
+		`                  aName:`,
+		`                    type: string`,
+		`components:`,
+		`  schemas:`,
+		`    AStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        aChild:`,
+		`          nullable: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/BStruct'`,
+		`        aName:`,
+		`          type: string`,
+		`    BStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        bChild:`,
+		`          nullable: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/CStruct'`,
+		`        bName:`,
+		`          type: string`,
+		`    CStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        cChild:`,
+		`          nullable: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/AStruct'`,
+		`        cName:`,
+		`          type: string`,
+	}
+	util.CompareStrings(t, "components", gotStrings, wantStrings)
 
-						t.Logf("TEST_OK %s", name)
-					}
-				}
-			}
-		}
+	if validateOpenAPI(t, "components", strings.Join(gotStrings, "\n")) {
+		t.Logf("TEST_OK components: swagger")
 	}
 }
 
 func validateOpenAPI(t *testing.T, name, yamlStr string) bool {
-	if err := os.WriteFile(OPENAPI_CLI_FILE, []byte(yamlStr), 0644); err != nil {
+	// Scratch files are written to a temporary directory so that test runs do not change the tree.
+	fileName := filepath.Join(t.TempDir(), OPENAPI_CLI_FILE)
+	if err := os.WriteFile(fileName, []byte(yamlStr), 0644); err != nil {
 		t.Errorf("TEST_FAIL %s: writing yaml file err=%s", name, err)
 		return false
 	}
 
-	cmd := exec.Command(OPENAPI_CLI, "validate", OPENAPI_CLI_FILE)
+	return validateOpenAPIFile(t, name, fileName, yamlStr)
+}
+
+// validateOpenAPIFile validates a document that was written to a file, e.g. the main file of a multi-file document.
+// - yamlStr is shown if validation fails.
+func validateOpenAPIFile(t *testing.T, name, fileName, yamlStr string) bool {
+	cmd := exec.Command(OPENAPI_CLI, "validate", fileName)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -0,0 +1,126 @@
+package reflector
+
+import (
+	"reflect"
+
+	"github.com/gitmann/b9schema-golang/common/types"
+)
+
+// cacheEntry is a derived root element and the TypeRef elements it needs.
+// - Elements are detached copies that are copied again on retrieval.
+type cacheEntry struct {
+	root     *types.TypeNode
+	typeRefs []*types.TypeNode
+}
+
+// EnableCache turns on caching of derived root elements by reflect.Type.
+// - Deriving a cached type copies the cached elements instead of reflecting the value again.
+// - Cached elements keep the value-specific native options (e.g. IsZero) of the first derived value.
+// - Types that contain interfaces or maps are not cached because their schemas depend on their values.
+// - The cache is kept by Reset. Calling EnableCache again clears it, e.g. after changing configuration.
+func (r *Reflector) EnableCache() *Reflector {
+	r.cache = map[reflect.Type]*cacheEntry{}
+
+	// Return *Reflector for chaining.
+	return r
+}
+
+// cachedRoot adds a copy of a cached root element to the current schema.
+// - Returns nil if the value cannot be cached or is not in the cache.
+func (r *Reflector) cachedRoot(v reflect.Value, metaKey, typeName string) *types.TypeNode {
+	if !r.cacheable(v, typeName) {
+		return nil
+	}
+
+	entry := r.cache[v.Type()]
+	if entry == nil {
+		return nil
+	}
+
+	// Cached elements are copied so that schemas never share elements with the cache.
+	childNode := entry.root.Copy()
+	childNode.MetaKey = metaKey
+	r.Schema.Root.AddChild(childNode)
+
+	// TypeRefs that are already captured are checked for collisions like in addTypeRef.
+	typeRefMap := r.Schema.TypeRef.ChildMap()
+	for _, ref := range entry.typeRefs {
+		if existing := typeRefMap[ref.Name]; existing != nil {
+			checkTypeRefCollision(existing, ref)
+		} else {
+			r.Schema.TypeRef.AddChild(ref.Copy())
+		}
+	}
+
+	return childNode
+}
+
+// cacheRoot stores copies of a derived root element and its TypeRef elements.
+// - firstRef is the number of TypeRef elements before the root element was derived.
+// - TypeRef elements added by the derivation are stored even if they are not referenced, e.g. below a root error.
+func (r *Reflector) cacheRoot(v reflect.Value, typeName string, childNode *types.TypeNode, firstRef int) {
+	if !r.cacheable(v, typeName) {
+		return
+	}
+
+	// Collect names of referenced TypeRefs, including references from other TypeRefs.
+	typeRefMap := r.Schema.TypeRef.ChildMap()
+	names := map[string]bool{}
+	var collect func(t *types.TypeNode)
+	collect = func(t *types.TypeNode) {
+		t.Walk(func(node *types.TypeNode) error {
+			if node.TypeRef != "" && !names[node.TypeRef] {
+				names[node.TypeRef] = true
+				if ref := typeRefMap[node.TypeRef]; ref != nil {
+					collect(ref)
+				}
+			}
+			return nil
+		})
+	}
+	collect(childNode)
+
+	entry := &cacheEntry{root: childNode.Copy()}
+	entry.root.MetaKey = ""
+	for i, ref := range r.Schema.TypeRef.Children {
+		if i >= firstRef || names[ref.Name] {
+			entry.typeRefs = append(entry.typeRefs, ref.Copy())
+		}
+	}
+
+	r.cache[v.Type()] = entry
+}
+
+// cacheable returns true if the cache is enabled and the derivation of a value only depends on its type.
+// - Forced root type names are not cached.
+// - Nothing is cached while capturing examples, see CaptureExamples.
+func (r *Reflector) cacheable(v reflect.Value, typeName string) bool {
+	return r.cache != nil && v.IsValid() && typeName == "" && !r.CaptureExamples && !valueDependent(v.Type(), map[reflect.Type]bool{})
+}
+
+// valueDependent returns true if reflection of a type depends on values, i.e. it contains interfaces or maps.
+// - seen prevents infinite recursion on cyclical types.
+func valueDependent(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return valueDependent(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			// Unexported fields are not reflected unless they are embedded.
+			if field := t.Field(i); field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if valueDependent(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package reflector
+
+import (
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/enum/typecategory"
+	"github.com/gitmann/b9schema-golang/common/types"
+)
+
+// SchemaError is an error found on an element during reflection.
+type SchemaError struct {
+	// Path is the dotted path of the element, e.g. "TypeRef.CompoundTypes.Map"
+	// - Unnamed elements (e.g. list items) use the path default of their type, e.g. "[]"
+	Path string
+
+	// Error is the error string of the element.
+	Error string
+
+	// Type is the generic type of the element.
+	Type string
+}
+
+// Errors returns all errors in the Root and TypeRef trees of the current schema.
+// - Excluded elements and their children are skipped.
+// - Identical path and error pairs are returned once.
+func (r *Reflector) Errors() []SchemaError {
+	out := []SchemaError{}
+	if r.Schema == nil {
+		return out
+	}
+
+	found := map[string]bool{}
+	r.Schema.Walk(func(node *types.TypeNode) error {
+		out = appendError(out, found, node)
+		return nil
+	})
+
+	return out
+}
+
+// rootErrors returns the errors of a root element and of the TypeRef elements that it references.
+// - Other root elements and unreferenced TypeRefs in the schema are skipped.
+func (r *Reflector) rootErrors(root *types.TypeNode) []SchemaError {
+	out := []SchemaError{}
+	found := map[string]bool{}
+
+	seenRefs := map[string]bool{}
+	queue := []*types.TypeNode{root}
+	for len(queue) > 0 {
+		elem := queue[0]
+		queue = queue[1:]
+
+		elem.Walk(func(node *types.TypeNode) error {
+			out = appendError(out, found, node)
+
+			if node.TypeRef == "" || seenRefs[node.TypeRef] {
+				return nil
+			}
+			if _, include := errorPath(node); !include {
+				return nil
+			}
+			seenRefs[node.TypeRef] = true
+			if ref := r.Schema.TypeRef.ChildByName(node.TypeRef, nil); ref != nil {
+				queue = append(queue, ref)
+			}
+			return nil
+		})
+	}
+
+	return out
+}
+
+// appendError adds the error of an element to out unless it is excluded or its path and error are in found.
+func appendError(out []SchemaError, found map[string]bool, node *types.TypeNode) []SchemaError {
+	if node.Error == "" {
+		return out
+	}
+
+	path, include := errorPath(node)
+	if !include {
+		return out
+	}
+
+	key := path + "\n" + node.Error
+	if found[key] {
+		return out
+	}
+	found[key] = true
+
+	return append(out, SchemaError{
+		Path:  path,
+		Error: node.Error,
+		Type:  node.Type,
+	})
+}
+
+// errorPath builds the dotted path of an element.
+// - include is false if the element or one of its ancestors is excluded.
+func errorPath(t *types.TypeNode) (path string, include bool) {
+	parts := []string{}
+	for n := t; n != nil; n = n.Parent {
+		if native := n.NativeDefault(); native != nil && native.Include == threeflag.False {
+			return "", false
+		}
+
+		part := n.Name
+		if part == "" {
+			if gt := generictype.FromType(n.Type); gt == nil || gt.Category() == typecategory.Invalid {
+				part = n.Type
+			} else {
+				part = gt.PathDefault()
+			}
+		}
+		parts = append([]string{part}, parts...)
+	}
+
+	return strings.Join(parts, "."), true
+}
@@ -1,8 +1,12 @@
 package reflector
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -16,12 +20,111 @@ import (
 
 const (
 	NATIVE_DIALECT = "golang"
+
+	// OMITEMPTY_OPTION is the native option set from the json tag "omitempty" option.
+	OMITEMPTY_OPTION = "OmitEmpty"
+
+	// MAX_STRICT_ERRORS is the number of error paths in the DeriveSchemaStrict error.
+	MAX_STRICT_ERRORS = 3
+
+	// DESCRIPTION_OPTION is the b9schema tag option for field descriptions, e.g. `b9schema:"desc=Some text"`
+	DESCRIPTION_OPTION = "desc"
+
+	// EXAMPLE_OPTION is the option for example values, e.g. `b9schema:"example=42"`
+	EXAMPLE_OPTION = "example"
+
+	// KEY_PATTERN_OPTION is the b9schema tag option for a regular expression that map keys must match.
+	// - e.g. `b9schema:"keyPattern=^[a-z]+$"`
+	KEY_PATTERN_OPTION = "keyPattern"
+
+	// PATTERN_OPTION is the b9schema tag option for a regular expression that string values must match.
+	// - Patterns with commas must be quoted, e.g. `b9schema:"pattern='^[a-z]{1,8}$'"`
+	PATTERN_OPTION = "pattern"
+
+	// CUSTOM_MARSHALER_OPTION is the native option set on types that implement json.Marshaler.
+	CUSTOM_MARSHALER_OPTION = "HasCustomMarshaler"
+
+	// ENUM_OPTION is the option for allowed values, e.g. `b9schema:"enum=red|green|blue"`
+	ENUM_OPTION = "enum"
+
+	// DISCRIMINATOR_OPTION is the native option of a union with the name of the property that distinguishes alternatives.
+	// - DISCRIMINATOR_VALUE_OPTION is the native option of an alternative with its property value.
+	// - See RegisterDiscriminator.
+	DISCRIMINATOR_OPTION       = "discriminator"
+	DISCRIMINATOR_VALUE_OPTION = "discriminatorValue"
 )
 
 // Reflector provides functions to build type and values from a Go value.
+// - Schema holds the state of derivations, it is cleared by Reset.
+// - Other fields are configuration that is kept by Reset.
 type Reflector struct {
 	// Keep track of refs found during parsing.
 	Schema *types.Schema
+
+	// UseJSONNumber decodes numbers as json.Number in DeriveSchemaFromJSON so that integers are not floats.
+	UseJSONNumber bool
+
+	// Strict makes DeriveSchemaStrict return an error if any element has an error.
+	Strict bool
+
+	// MaxDepth stops reflection of elements below the given depth, 0 means no limit.
+	// - Top-level elements have depth 1.
+	// - Elements below the maximum depth have a MaxDepthErr error and no children.
+	MaxDepth int
+
+	// TreatGoMapsAsOpen keeps Go maps with entries as maps with a value type instead of structs with observed keys.
+	// - Maps with interface values (e.g. JSON objects) are still converted to structs.
+	TreatGoMapsAsOpen bool
+
+	// AllowNonStructRoot allows lists and maps as root elements, e.g. endpoints that return a JSON array of objects.
+	// - Other root types are still a RootKindErr.
+	AllowNonStructRoot bool
+
+	// AllowAnyInterface reflects nil interfaces as the "any" generic type instead of a NilInterfaceErr.
+	AllowAnyInterface bool
+
+	// UnifyListTypes reflects lists with elements of different types (e.g. JSON [1, "two", true]) as lists of unions.
+	// - The union has one alternative per type in the order found instead of a SliceMultiTypeErr.
+	UnifyListTypes bool
+
+	// CaptureExamples records non-zero scalar values of exported struct fields as examples, e.g. from sample data.
+	// - Examples from tags and SetExamples take precedence.
+	// - Types are not cached while capturing examples because examples depend on values.
+	CaptureExamples bool
+
+	// StrictUintptr reflects uintptr values as invalid types with an InvalidKindErr, like unsafe.Pointer.
+	// - A uintptr usually holds a memory address that has no meaning outside the process.
+	// - It is opt-in because uintptr is a plain integer for encoding/json and existing schemas render it as one.
+	StrictUintptr bool
+
+	// TypeNameFunc converts Go type names to TypeRef names, SanitizeTypeName is used if nil.
+	// - Instantiated generic types have names with type arguments, e.g. "Response[github.com/org/pkg.User]"
+	// - The Go type name is kept in the "Type.Name" native option.
+	TypeNameFunc func(name string) string
+
+	// knownTypes maps full type paths to registered generic types, see RegisterKnownType.
+	knownTypes map[string]*knownType
+
+	// interfaceImpls maps interface types to implementation types, see RegisterInterfaceImplementations.
+	interfaceImpls map[reflect.Type][]reflect.Type
+
+	// discriminators maps interface types to registered discriminators, see RegisterDiscriminator.
+	discriminators map[reflect.Type]*discriminator
+
+	// enums maps named types to registered enum values, see RegisterEnum.
+	enums map[reflect.Type][]string
+
+	// descriptions maps "TypeName" and "TypeName.FieldName" to descriptions, see SetDescriptions.
+	descriptions map[string]string
+
+	// examples maps "TypeName.FieldName" to example values, see SetExamples.
+	examples map[string]interface{}
+
+	// cache maps types to derived root elements, see EnableCache.
+	cache map[reflect.Type]*cacheEntry
+
+	// rootTypeName overrides the TypeRef name of the root element while deriving a schema.
+	rootTypeName string
 }
 
 func NewReflector() *Reflector {
@@ -32,29 +135,396 @@ func NewReflector() *Reflector {
 	return r
 }
 
+// Reset clears the derivation state so that the Reflector can be reused.
+// - Cleared: Schema and the ID counter.
+// - Kept: configuration flags, registered known types and enums, descriptions, examples and the cache.
 func (r *Reflector) Reset() *Reflector {
 	// Initialize state.
 	idgen.Reset()
 
 	r.Schema = types.NewSchema(NATIVE_DIALECT)
+	r.rootTypeName = ""
 
 	// Return *Reflector for chaining.
 	return r
 }
 
+// Clone returns a new Reflector with a copy of the configuration and an empty Schema.
+// - The Schema is not shared so that clones of a configured Reflector can derive schemas in separate goroutines.
+// - Registered types, descriptions, examples and cached types are copied, changes to a clone do not affect r.
+func (r *Reflector) Clone() *Reflector {
+	c := *r
+	c.Schema = types.NewSchema(NATIVE_DIALECT)
+	c.rootTypeName = ""
+
+	if r.knownTypes != nil {
+		c.knownTypes = map[string]*knownType{}
+		for k, v := range r.knownTypes {
+			c.knownTypes[k] = v
+		}
+	}
+
+	if r.interfaceImpls != nil {
+		c.interfaceImpls = map[reflect.Type][]reflect.Type{}
+		for k, v := range r.interfaceImpls {
+			c.interfaceImpls[k] = append([]reflect.Type{}, v...)
+		}
+	}
+
+	if r.discriminators != nil {
+		c.discriminators = map[reflect.Type]*discriminator{}
+		for k, v := range r.discriminators {
+			c.discriminators[k] = v
+		}
+	}
+
+	if r.enums != nil {
+		c.enums = map[reflect.Type][]string{}
+		for k, v := range r.enums {
+			c.enums[k] = append([]string{}, v...)
+		}
+	}
+
+	if r.descriptions != nil {
+		c.descriptions = map[string]string{}
+		for k, v := range r.descriptions {
+			c.descriptions[k] = v
+		}
+	}
+
+	if r.examples != nil {
+		c.examples = map[string]interface{}{}
+		for k, v := range r.examples {
+			c.examples[k] = v
+		}
+	}
+
+	if r.cache != nil {
+		c.cache = map[reflect.Type]*cacheEntry{}
+		for k, v := range r.cache {
+			c.cache[k] = v
+		}
+	}
+
+	return &c
+}
+
+// knownType is a generic type and format registered for a Go type.
+type knownType struct {
+	genericType string
+	format      string
+}
+
+// RegisterKnownType maps the named type of example to a generic type.
+// - Registered types are not reflected further, e.g. struct fields of a UUID type are skipped.
+// - format is stored as the Format of the native type, e.g. "uuid".
+// - Returns an error if example is not a named type or genericType is unknown.
+func (r *Reflector) RegisterKnownType(example interface{}, genericType string, format string) error {
+	v := reflect.ValueOf(example)
+	if !v.IsValid() || v.Type().PkgPath() == "" {
+		return fmt.Errorf("known type must be a named type: %T", example)
+	}
+
+	if gt := generictype.FromType(genericType); gt == nil || gt.Category() == typecategory.Internal {
+		return fmt.Errorf("unknown generic type %q", genericType)
+	}
+
+	if r.knownTypes == nil {
+		r.knownTypes = map[string]*knownType{}
+	}
+	r.knownTypes[generictype.FullPathOf(v)] = &knownType{
+		genericType: genericType,
+		format:      format,
+	}
+
+	return nil
+}
+
+// RegisterInterfaceImplementations registers the types that can be stored in an interface type.
+// - ifacePtr is a nil pointer to the interface type, e.g. (*Shape)(nil)
+// - Nil interface elements of a registered type are reflected as a union of the implementation types instead of a NilInterfaceErr.
+// - Implementations may be values or pointers, pointers are reflected as their target types.
+// - Returns an error if ifacePtr is not a pointer to an interface or an implementation does not implement the interface.
+func (r *Reflector) RegisterInterfaceImplementations(ifacePtr interface{}, impls ...interface{}) error {
+	ifaceType := reflect.TypeOf(ifacePtr)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("interface must be a pointer to an interface type: %T", ifacePtr)
+	}
+	ifaceType = ifaceType.Elem()
+
+	if len(impls) == 0 {
+		return fmt.Errorf("no implementations for %s", ifaceType)
+	}
+
+	implTypes := []reflect.Type{}
+	seen := map[reflect.Type]bool{}
+	for _, impl := range impls {
+		implType := reflect.TypeOf(impl)
+		if implType == nil || !implType.Implements(ifaceType) {
+			return fmt.Errorf("%T does not implement %s", impl, ifaceType)
+		}
+
+		if implType.Kind() == reflect.Ptr {
+			implType = implType.Elem()
+		}
+		if !seen[implType] {
+			implTypes = append(implTypes, implType)
+			seen[implType] = true
+		}
+	}
+
+	if r.interfaceImpls == nil {
+		r.interfaceImpls = map[reflect.Type][]reflect.Type{}
+	}
+	r.interfaceImpls[ifaceType] = implTypes
+
+	return nil
+}
+
+// discriminator is the property name and values that distinguish the implementations of an interface.
+type discriminator struct {
+	propertyName string
+	values       map[reflect.Type]string
+}
+
+// RegisterDiscriminator sets the property that distinguishes the implementations of an interface.
+// - The implementations must be registered first, see RegisterInterfaceImplementations.
+// - values maps property values to implementations, e.g. {"cat": Cat{}, "dog": Dog{}}, and may be empty.
+// - Unions of the interface have the DISCRIMINATOR_OPTION, alternatives have the DISCRIMINATOR_VALUE_OPTION.
+// - Returns an error if the interface has no registered implementations or a value is not a registered implementation.
+func (r *Reflector) RegisterDiscriminator(ifacePtr interface{}, propertyName string, values map[string]interface{}) error {
+	ifaceType := reflect.TypeOf(ifacePtr)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("interface must be a pointer to an interface type: %T", ifacePtr)
+	}
+	ifaceType = ifaceType.Elem()
+
+	implTypes := r.interfaceImpls[ifaceType]
+	if len(implTypes) == 0 {
+		return fmt.Errorf("no implementations registered for %s", ifaceType)
+	}
+
+	if propertyName == "" {
+		return fmt.Errorf("missing discriminator property name for %s", ifaceType)
+	}
+
+	d := &discriminator{
+		propertyName: propertyName,
+		values:       map[reflect.Type]string{},
+	}
+	for val, impl := range values {
+		implType := reflect.TypeOf(impl)
+		if implType != nil && implType.Kind() == reflect.Ptr {
+			implType = implType.Elem()
+		}
+
+		found := false
+		for _, t := range implTypes {
+			found = found || t == implType
+		}
+		if !found {
+			return fmt.Errorf("%T is not a registered implementation of %s", impl, ifaceType)
+		}
+		d.values[implType] = val
+	}
+
+	if r.discriminators == nil {
+		r.discriminators = map[reflect.Type]*discriminator{}
+	}
+	r.discriminators[ifaceType] = d
+
+	return nil
+}
+
+// RegisterEnum registers the allowed values of a named basic type, e.g. constants of "type Status string"
+// - Values are stored as the ENUM_OPTION of elements of the type, e.g. the TypeRef element of the type.
+// - A single value is a constant.
+// - Returns an error if example is not a named basic type or a value has a different kind.
+func (r *Reflector) RegisterEnum(example interface{}, values ...interface{}) error {
+	t := reflect.TypeOf(example)
+	if t == nil || t.PkgPath() == "" {
+		return fmt.Errorf("enum must be a named type: %T", example)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return fmt.Errorf("enum must be a basic type: %T", example)
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("no values for %s", t)
+	}
+
+	vals := []string{}
+	for _, value := range values {
+		if vt := reflect.TypeOf(value); vt == nil || vt.Kind() != t.Kind() {
+			return fmt.Errorf("enum value %v is not a %s", value, t)
+		}
+
+		val := fmt.Sprint(value)
+		if strings.Contains(val, types.LIST_SEPARATOR) {
+			return fmt.Errorf("enum value %q contains %q", val, types.LIST_SEPARATOR)
+		}
+		vals = append(vals, val)
+	}
+
+	if r.enums == nil {
+		r.enums = map[reflect.Type][]string{}
+	}
+	r.enums[t] = vals
+
+	return nil
+}
+
+// SetDescriptions sets descriptions of types and struct fields.
+// - Keys are "TypeName" for types and "TypeName.FieldName" for struct fields.
+// - A b9schema tag "desc" option on a field takes precedence over the map.
+func (r *Reflector) SetDescriptions(descriptions map[string]string) {
+	r.descriptions = descriptions
+}
+
+// SetExamples sets example values of struct fields.
+// - Keys are "TypeName.FieldName".
+// - Values are stored as strings in the EXAMPLE_OPTION of the field's native default options.
+// - A b9schema tag "example" option on a field takes precedence over the map.
+func (r *Reflector) SetExamples(examples map[string]interface{}) {
+	r.examples = examples
+}
+
 // DeriveSchema builds a reflector list of elements from the given interface.
 func (r *Reflector) DeriveSchema(x interface{}, metaKey string) *types.Schema {
+	return r.DeriveSchemaWithName(x, metaKey, "")
+}
+
+// DeriveSchemaStrict builds a schema like DeriveSchema.
+// - If Strict is true and any element of the new root element has an error, a summary of the errors is returned.
+// - Errors of elements added by earlier calls are ignored unless the new root element references them.
+// - The schema is always returned so that errors can be inspected.
+func (r *Reflector) DeriveSchemaStrict(x interface{}, metaKey string) (*types.Schema, error) {
+	if r.Schema == nil {
+		r.Reset()
+	}
+	root := r.deriveRoot(reflect.ValueOf(x), metaKey, "")
+	schema := r.Schema
+	if !r.Strict {
+		return schema, nil
+	}
+
+	schemaErrors := r.rootErrors(root)
+	if len(schemaErrors) == 0 {
+		return schema, nil
+	}
+
+	msgs := []string{}
+	for i, e := range schemaErrors {
+		if i == MAX_STRICT_ERRORS {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(schemaErrors)-i))
+			break
+		}
+		msgs = append(msgs, e.Path+": "+e.Error)
+	}
+
+	return schema, fmt.Errorf("schema has %d errors: %s", len(schemaErrors), strings.Join(msgs, "; "))
+}
+
+// DeriveSchemaWithName builds a reflector list of elements from the given interface.
+// - typeName is used as the TypeRef name of the root element, e.g. for anonymous structs or decoded JSON.
+// - If typeName is empty, the reflected type name is used.
+func (r *Reflector) DeriveSchemaWithName(x interface{}, metaKey, typeName string) *types.Schema {
+	return r.deriveSchemaValue(reflect.ValueOf(x), metaKey, typeName)
+}
+
+// DeriveSchemaFromJSON decodes a JSON string and builds a reflector list of elements from the decoded value.
+// - If UseJSONNumber is set, numbers without a decimal point or exponent are integers, otherwise all numbers are floats.
+func (r *Reflector) DeriveSchemaFromJSON(b []byte, metaKey string) (*types.Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if r.UseJSONNumber {
+		dec.UseNumber()
+	}
+
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, err
+	}
+
+	return r.DeriveSchema(x, metaKey), nil
+}
+
+// DeriveSchemaFromType builds a reflector list of elements from a zero value of the given type.
+// - Pointers are reflected using a zero value of the target type, same as nil pointers in DeriveSchema.
+// - Interface types have a nil zero value so they produce a NilInterfaceErr.
+// - A nil type produces an InvalidKindErr, same as DeriveSchema(nil).
+func (r *Reflector) DeriveSchemaFromType(t reflect.Type, metaKey string) *types.Schema {
+	var v reflect.Value
+	if t != nil {
+		v = reflect.New(t).Elem()
+	}
+	return r.deriveSchemaValue(v, metaKey, "")
+}
+
+// DeriveSchemaFromValue builds a reflector list of elements from a reflect.Value as-is, e.g. in code that works with reflection.
+// - The zero Value produces an InvalidKindErr, same as DeriveSchema(nil).
+func (r *Reflector) DeriveSchemaFromValue(v reflect.Value, metaKey string) *types.Schema {
+	return r.deriveSchemaValue(v, metaKey, "")
+}
+
+// deriveSchemaValue starts recursive reflection on a value as a new root element.
+func (r *Reflector) deriveSchemaValue(v reflect.Value, metaKey, typeName string) *types.Schema {
 	if r.Schema == nil {
 		r.Reset()
 	}
 
-	// Start recursive reflection.
+	r.deriveRoot(v, metaKey, typeName)
+
+	return r.Schema
+}
+
+// DeriveSchemaInto builds elements from the given interface in a caller-supplied schema instead of the Reflector's Schema.
+// - Returns the new root element so that callers can set details such as MetaKey or Description.
+// - TypeRefs are added to and deduplicated against the supplied schema's TypeRef tree.
+// - If schema is nil, a new schema is used and can be found from the returned element's ancestors.
+func (r *Reflector) DeriveSchemaInto(schema *types.Schema, x interface{}, metaKey string) *types.TypeNode {
+	if schema == nil {
+		schema = types.NewSchema(NATIVE_DIALECT)
+	}
+
+	// Reflect into the supplied schema and restore the Reflector's Schema on exit.
+	saved := r.Schema
+	r.Schema = schema
+	defer func() { r.Schema = saved }()
+
+	return r.deriveRoot(reflect.ValueOf(x), metaKey, "")
+}
+
+// deriveRoot adds a root element to the current schema and starts recursive reflection.
+// - If the cache is enabled, cached types are copied instead of reflected.
+func (r *Reflector) deriveRoot(v reflect.Value, metaKey, typeName string) *types.TypeNode {
+	if childNode := r.cachedRoot(v, metaKey, typeName); childNode != nil {
+		return childNode
+	}
+	firstRef := len(r.Schema.TypeRef.Children)
+
 	childNode := r.Schema.Root.NewChild("")
 	childNode.MetaKey = metaKey
 
-	r.reflectTypeImpl(types.NewAncestorTypeRef(), childNode, reflect.ValueOf(x))
+	r.rootTypeName = typeName
+	r.reflectTypeImpl(types.NewAncestorTypeRef(), childNode, v)
+	r.rootTypeName = ""
 
-	return r.Schema
+	r.cacheRoot(v, typeName, childNode, firstRef)
+
+	return childNode
+}
+
+// DeriveSchemaForOperation builds a reflector list of elements for an API operation.
+// - path is the API path, e.g. "/users"
+// - method is the HTTP method, e.g. "post"; if empty, renderers use their default method
+func (r *Reflector) DeriveSchemaForOperation(x interface{}, path, method string) *types.Schema {
+	return r.DeriveSchema(x, types.OperationKey(path, method))
 }
 
 // reflectTypeImpl is a recursive function to reflect Go values.
@@ -74,6 +544,21 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 		panic("currentElem cannot be nil")
 	}
 
+	// Panics (e.g. in TypeNameFunc or on exotic values) mark the element instead of crashing the caller.
+	// - Children reflected before the panic are removed, the panic value is the native error.
+	defer func() {
+		if p := recover(); p != nil {
+			if currentElem.Type == "" {
+				currentElem.Type = generictype.Invalid.String()
+			}
+			currentElem.SetError(types.ReflectionPanicErr)
+			currentElem.Children = []*types.TypeNode{}
+			if native := currentElem.NativeDefault(); native != nil {
+				native.Error = fmt.Sprint(p)
+			}
+		}
+	}()
+
 	// Create temporary list for named type refs.
 	refList := types.NewTypeList()
 	refList.Push(currentElem)
@@ -87,9 +572,15 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 	currentElem.Type = genericType.String()
 
 	// ERROR CHECKING
+	// Stop recursion below the maximum depth. Top-level elements have depth 1.
+	if r.MaxDepth > 0 && len(currentElem.Ancestors())-1 > r.MaxDepth {
+		currentElem.SetError(types.MaxDepthErr)
+		return
+	}
+
 	// Check for invalid types. These may panic on some operations so we exit quickly with minimal reflection.
 	if genericType.Category() == typecategory.Invalid {
-		currentElem.Error = types.InvalidKindErr
+		currentElem.SetError(types.InvalidKindErr)
 
 		if v == reflect.ValueOf(nil) {
 			currentElem.Type = currentElem.Type + ":nil"
@@ -100,6 +591,13 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 		return
 	}
 
+	// Memory addresses are not data, see StrictUintptr.
+	if r.StrictUintptr && v.Kind() == reflect.Uintptr {
+		currentElem.Type = generictype.Invalid.String() + ":" + v.Kind().String()
+		currentElem.SetError(types.InvalidKindErr)
+		return
+	}
+
 	// If parent is a root, the current element must be a struct or a Reference.
 	if currentElem.Parent == nil {
 		panic("parent is nil")
@@ -110,20 +608,94 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 	native.Options.AddBool("IsValid", v.IsValid())
 	native.Options.AddThreeFlag("IsNil", threeflag.Undefined)
 	native.Type = v.Kind().String()
+	native.Format = formatOf(v, genericType)
 	native.Options.AddKeyVal("Type.Name", v.Type().Name())
 	native.Options.AddKeyVal("Type.Kind", v.Type().Kind().String())
 	native.Options.AddKeyVal("Type.PkgPath", v.Type().PkgPath())
 
+	if vals := r.enums[v.Type()]; vals != nil {
+		native.Options.AddList(ENUM_OPTION, vals)
+	}
+
+	// Registered known types replace reflection of the value.
+	if v.Type().PkgPath() != "" {
+		if known := r.knownTypes[generictype.FullPathOf(v)]; known != nil {
+			currentElem.Type = known.genericType
+			native.Format = known.format
+
+			if currentElem.Parent.Type == generictype.Root.String() && !r.isRootType(currentElem.Type) {
+				currentElem.SetError(types.RootKindErr)
+			}
+			return
+		}
+	}
+
+	// database/sql wrappers (e.g. sql.NullString) are nullable values of their generic types.
+	if generictype.IsSQLNull(v) {
+		currentElem.Nullable = true
+		native.Format = formatOf(v.Field(0), genericType)
+
+		if currentElem.Parent.Type == generictype.Root.String() {
+			currentElem.SetError(types.RootKindErr)
+		}
+		return
+	}
+
+	// Types that implement json.Marshaler may serialize to anything, the schema is derived but flagged.
+	// - Register a known type to replace the derived schema, see RegisterKnownType.
+	// - Known types (e.g. time.Time) and json.RawMessage have schemas that match their JSON.
+	// - The error is set after reflection so that the schema is still derived, see below.
+	customMarshaler := genericType.Category() != typecategory.Known && genericType.Category() != typecategory.Reference &&
+		!generictype.IsJSONRawMessage(v) && isJSONMarshaler(v.Type())
+	if customMarshaler {
+		native.Options.AddBool(CUSTOM_MARSHALER_OPTION, true)
+	}
+
+	// Types that implement encoding.TextMarshaler are serialized as strings.
+	// - Pointers and interfaces are skipped so that their target types are checked.
+	// - Known types like time.Time keep their generic types.
+	if genericType.Category() != typecategory.Known && genericType.Category() != typecategory.Reference && isTextMarshaler(v.Type()) {
+		currentElem.Type = generictype.String.String()
+
+		// The text is not base64 encoded, e.g. net.IP is a []byte.
+		native.Format = ""
+
+		if customMarshaler {
+			currentElem.SetError(types.CustomMarshalerErr)
+		}
+
+		if currentElem.Parent.Type == generictype.Root.String() {
+			currentElem.SetError(types.RootKindErr)
+		}
+		return
+	}
+
 	// If type.Name differs from type.Kind, element is a TypeRef.
-	if v.Type().Name() != v.Type().Kind().String() {
-		currentElem.TypeRef = v.Type().Name()
+	// - json.Number is a named string type but it holds a plain number.
+	// - json.RawMessage is a named []byte type but it holds any JSON value.
+	// - Known types (e.g. time.Time) are values, also behind pointers. Types redefined from them are TypeRefs.
+	typeName := v.Type().Name()
+	isKnown := genericType.Category() == typecategory.Known && genericType.ContainsKind(generictype.FullPathOf(v))
+	isTypeRef := typeName != v.Type().Kind().String() && !isKnown && !generictype.IsJSONNumber(v) && !generictype.IsJSONRawMessage(v)
+	if isTypeRef {
+		typeName = r.typeRefName(typeName)
+	}
+
+	// Root element name may be forced. Pointers and interfaces are skipped because they wrap the root type.
+	if r.rootTypeName != "" && currentElem.Parent == r.Schema.Root && genericType.Category() != typecategory.Reference {
+		typeName = r.rootTypeName
+		isTypeRef = true
+	}
+
+	if isTypeRef {
+		currentElem.TypeRef = typeName
 
 		native.TypeRef = currentElem.TypeRef
 		native.Options.AddKeyVal("TypeRef", currentElem.TypeRef)
 
 		// Check for cyclical references.
 		if ancestorTypeRef.Contains(currentElem.TypeRef) {
-			currentElem.Error = types.CyclicalReferenceErr
+			currentElem.SetError(types.CyclicalReferenceErr)
 			return
 		}
 		ancestorTypeRef.Add(currentElem.TypeRef)
@@ -136,12 +708,7 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 		// Basic types are already handled by the default operations above. Nothing else to do here.
 
 	case typecategory.Known:
-		// If the known type package path matches the known ones, then remove the type ref.
-		fullPath := generictype.FullPathOf(v)
-		if genericType.ContainsKind(fullPath) {
-			currentElem.TypeRef = ""
-			native.TypeRef = ""
-		}
+		// Known types are not TypeRefs, see isTypeRef above. Nothing else to do here.
 
 	case typecategory.Compound:
 		switch genericType {
@@ -176,11 +743,19 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 		panic(fmt.Sprintf("unexpected type %q", genericType))
 	}
 
-	// If current node parent is Root, type must be a Struct.
+	// Errors found while reflecting (e.g. in map keys) are more specific than the custom marshaler error.
+	if customMarshaler && currentElem.Error == "" {
+		currentElem.SetError(types.CustomMarshalerErr)
+	}
+
+	// If current node parent is Root, type must be a Struct (or a List or Map with AllowNonStructRoot).
 	// - NOTE: Use currentElem type because it may have changed in recursive processing.
 	if currentElem.Parent.Type == generictype.Root.String() {
-		if currentElem.Type != generictype.Struct.String() {
-			currentElem.Error = types.RootKindErr
+		if !r.isRootType(currentElem.Type) {
+			// Keep a more specific error, e.g. from a nil interface.
+			if currentElem.Error == "" {
+				currentElem.SetError(types.RootKindErr)
+			}
 			currentElem.RemoveAllChildren()
 			return
 		}
@@ -190,6 +765,17 @@ func (r *Reflector) reflectTypeImpl(ancestorTypeRef types.AncestorTypeRef, curre
 	r.addTypeRef(currentElem)
 }
 
+// isRootType returns true if a generic type is allowed as a root element.
+func (r *Reflector) isRootType(genericType string) bool {
+	switch genericType {
+	case generictype.Struct.String():
+		return true
+	case generictype.List.String(), generictype.Map.String():
+		return r.AllowNonStructRoot
+	}
+	return false
+}
+
 // addTypeRef adds a TypeRef for the current element.
 // - This function should only be called on an element with a TypeRef.
 func (r *Reflector) addTypeRef(currentElem *types.TypeNode) {
@@ -198,16 +784,40 @@ func (r *Reflector) addTypeRef(currentElem *types.TypeNode) {
 		return
 	}
 
-	// Skip if the TypeRef has already been captured.
-	if r.Schema.TypeRef.ChildByName(currentElem.NativeDefault().TypeRef, nil) != nil {
+	// Skip if the TypeRef has a cyclical reference error.
+	if currentElem.Error == types.CyclicalReferenceErr {
 		return
 	}
 
-	// Skip if the TypeRef has a cyclical reference error.
-	if currentElem.Error == types.CyclicalReferenceErr {
+	// Skip if the TypeRef has already been captured.
+	// - The comparison element is built without adding the TypeRefs of its descendants.
+	if ref := r.Schema.TypeRef.ChildByName(currentElem.NativeDefault().TypeRef, nil); ref != nil {
+		compareElem := r.typeRefElem(currentElem)
+		compareElem.Walk(func(node *types.TypeNode) error {
+			if node.NativeDefault().TypeRef != "" {
+				clearTypeRef(node)
+			}
+			return nil
+		})
+		checkTypeRefCollision(ref, compareElem)
 		return
 	}
 
+	r.Schema.TypeRef.AddChild(r.newTypeRef(currentElem))
+}
+
+// newTypeRef returns a TypeRef element for an element with a TypeRef.
+// - TypeRefs of descendants are added to the schema.
+func (r *Reflector) newTypeRef(currentElem *types.TypeNode) *types.TypeNode {
+	refElem := r.typeRefElem(currentElem)
+	r.typeRefRecursion(refElem)
+
+	return refElem
+}
+
+// typeRefElem returns a copy of an element with a TypeRef that is changed into the TypeRef element.
+// - Descendants are copied as they are.
+func (r *Reflector) typeRefElem(currentElem *types.TypeNode) *types.TypeNode {
 	// The first element of a type ref is not a type ref. Move type ref name to element name.
 	refElem := currentElem.Copy()
 
@@ -215,15 +825,40 @@ func (r *Reflector) addTypeRef(currentElem *types.TypeNode) {
 	refElem.TypeRef = ""
 	refElem.MetaKey = ""
 
+	// TypeRefs are kept in the order found, not in the declaration order of the referencing field.
+	refElem.Order = 0
+
+	// Nullable and omitempty apply to the element that references the type, not the type itself.
+	refElem.Nullable = false
+	refElem.NativeDefault().Options.Delete(OMITEMPTY_OPTION)
+
+	// Struct tag options (e.g. deprecated) describe the field, not the type.
+	delete(refElem.Native, types.TAG_DIALECT)
+
+	// Field descriptions are replaced by the type description.
+	refElem.Description = r.descriptions[refElem.NativeDefault().Options.Get("Type.Name")]
+
 	// Move TypeRef to Name on all NativeTypes.
 	for _, nativeNode := range refElem.Native {
 		nativeNode.Name = nativeNode.TypeRef
 		nativeNode.TypeRef = ""
 	}
 
-	r.typeRefRecursion(refElem)
+	return refElem
+}
 
-	r.Schema.TypeRef.AddChild(refElem)
+// checkTypeRefCollision marks a captured TypeRef with a collision error if newRef has the same name
+// but comes from a different Go type with a different definition.
+func checkTypeRefCollision(ref, newRef *types.TypeNode) {
+	if ref.Error == "" && !sameGoType(ref, newRef) && !ref.Equals(newRef, false) {
+		ref.SetError(types.TypeRefCollisionErr)
+	}
+}
+
+// sameGoType returns true if two elements were reflected from the same Go type name and package.
+func sameGoType(a, b *types.TypeNode) bool {
+	aOpts, bOpts := a.NativeDefault().Options, b.NativeDefault().Options
+	return aOpts.Get("Type.PkgPath") == bOpts.Get("Type.PkgPath") && aOpts.Get("Type.Name") == bOpts.Get("Type.Name")
 }
 
 // typeRefRecursion is an internal recursive function to handle nested TypeRef.
@@ -234,10 +869,8 @@ func (r *Reflector) typeRefRecursion(currentElem *types.TypeNode) {
 		// Add TypeRef only if they are not cyclical errors.
 		if currentElem.Error != types.CyclicalReferenceErr {
 			r.addTypeRef(currentElem)
-			currentElem.RemoveAllChildren()
 		}
-
-		currentElem.Error = ""
+		clearTypeRef(currentElem)
 
 		return
 	}
@@ -248,15 +881,46 @@ func (r *Reflector) typeRefRecursion(currentElem *types.TypeNode) {
 	}
 }
 
+// clearTypeRef removes the children and errors of an element that references a TypeRef.
+// - Elements with a cyclical reference error have no TypeRef and keep their children.
+func clearTypeRef(currentElem *types.TypeNode) {
+	if currentElem.Error != types.CyclicalReferenceErr {
+		currentElem.RemoveAllChildren()
+	}
+
+	// Errors stay on the TypeRef.
+	currentElem.ClearErrors(false)
+}
+
 // reflectTypeInterfaceImpl refects on interface types
 // Interface is a special case which is either:
-// - nil -- nil has no discernable type and is an error
+// - nil -- nil has no discernable type and is an error, or "any" if AllowAnyInterface is set
 // - a wrapper around another type -- ignore the interface and continue reflection with the wrapped type
 func (r *Reflector) reflectTypeInterfaceImpl(ancestorTypeRef types.AncestorTypeRef, currentElem *types.TypeNode, v reflect.Value) {
 	if v.IsZero() {
+		if implTypes := r.interfaceImpls[v.Type()]; len(implTypes) > 0 {
+			r.reflectTypeUnionImpl(ancestorTypeRef, currentElem, implTypes)
+
+			// Alternatives have a child for each implementation in order.
+			if d := r.discriminators[v.Type()]; d != nil {
+				currentElem.NativeDefault().Options.AddKeyVal(DISCRIMINATOR_OPTION, d.propertyName)
+				for i, implType := range implTypes {
+					if val := d.values[implType]; val != "" {
+						currentElem.Children[i].NativeDefault().Options.AddKeyVal(DISCRIMINATOR_VALUE_OPTION, val)
+					}
+				}
+			}
+			return
+		}
+
+		if r.AllowAnyInterface {
+			currentElem.Type = generictype.Any.String()
+			return
+		}
+
 		// nil is an invalid element because its type cannot be determined
 		currentElem.Type = "invalid"
-		currentElem.Error = types.NilInterfaceErr
+		currentElem.SetError(types.NilInterfaceErr)
 		return
 	}
 
@@ -268,6 +932,19 @@ func (r *Reflector) reflectTypeInterfaceImpl(ancestorTypeRef types.AncestorTypeR
 	r.reflectTypeImpl(ancestorTypeRef.Copy(), currentElem, v.Elem())
 }
 
+// reflectTypeUnionImpl reflects a nil interface with registered implementations as a union.
+// - Each implementation type is an unnamed child with the type name as MetaKey so that children have unique keys.
+func (r *Reflector) reflectTypeUnionImpl(ancestorTypeRef types.AncestorTypeRef, currentElem *types.TypeNode, implTypes []reflect.Type) {
+	currentElem.Type = generictype.Union.String()
+
+	for _, implType := range implTypes {
+		nextElem := currentElem.NewChild("")
+		nextElem.MetaKey = implType.String()
+
+		r.reflectTypeImpl(ancestorTypeRef.Copy(), nextElem, reflect.New(implType).Elem())
+	}
+}
+
 // reflectTypePointerImpl refects on pointer types
 func (r *Reflector) reflectTypePointerImpl(ancestorTypeRef types.AncestorTypeRef, currentElem *types.TypeNode, v reflect.Value) {
 	// Pointer is a memory address pointing to some other type element.
@@ -337,8 +1014,14 @@ func (r *Reflector) reflectTypeListImpl(ancestorTypeRef types.AncestorTypeRef, c
 
 	if listHasElements {
 		// Check all slice elements to verify that they are all the same kind.
+		// - JSON numbers may mix integers and floats so they are counted as one kind.
 		kindsFound := map[string]int{}
 		childElem := []*types.TypeNode{}
+		jsonFloat := false
+
+		// First element of each kind in the order found, used by UnifyListTypes.
+		kindOrder := []string{}
+		kindElem := map[string]*types.TypeNode{}
 
 		for i := 0; i < v.Len(); i++ {
 			nextElem := currentElem.NewChild("")
@@ -347,10 +1030,19 @@ func (r *Reflector) reflectTypeListImpl(ancestorTypeRef types.AncestorTypeRef, c
 			targetValue = v.Index(i)
 			r.reflectTypeImpl(ancestorTypeRef.Copy(), nextElem, targetValue)
 
-			kindsFound[nextElem.Type]++
-			if len(kindsFound) > 1 {
+			kind := nextElem.Type
+			if generictype.IsJSONNumber(targetValue) {
+				jsonFloat = jsonFloat || kind == generictype.Float.String()
+				kind = generictype.Float.String()
+			}
+			kindsFound[kind]++
+			if kindElem[kind] == nil {
+				kindOrder = append(kindOrder, kind)
+				kindElem[kind] = nextElem
+			}
+			if len(kindsFound) > 1 && !r.UnifyListTypes {
 				// If multiple types found, set error and exit.
-				currentElem.Error = types.SliceMultiTypeErr
+				currentElem.SetError(types.SliceMultiTypeErr)
 
 				// Build a string with type:count elements.
 				out := []string{}
@@ -362,6 +1054,25 @@ func (r *Reflector) reflectTypeListImpl(ancestorTypeRef types.AncestorTypeRef, c
 			}
 		}
 
+		if jsonFloat {
+			kindElem[generictype.Float.String()].Type = generictype.Float.String()
+		}
+
+		if len(kindOrder) > 1 {
+			// Replace element children with a union of the first element of each kind.
+			for _, child := range childElem {
+				currentElem.RemoveChild(child)
+			}
+
+			unionElem := currentElem.NewChild("")
+			unionElem.Type = generictype.Union.String()
+			for _, kind := range kindOrder {
+				kindElem[kind].MetaKey = kind
+				unionElem.AddChild(kindElem[kind])
+			}
+			return
+		}
+
 		// All list elements have same type. Add first element as child of current element.
 		currentElem.AddChild(childElem[0])
 
@@ -390,13 +1101,13 @@ func (r *Reflector) reflectTypeMapImpl(ancestorTypeRef types.AncestorTypeRef, cu
 		if currentElem.Error == "" {
 			// Map key must be ancestorTypeRef string.
 			if v.Type().Key().Kind() != reflect.String {
-				currentElem.Error = types.MapKeyTypeErr
+				currentElem.SetError(types.MapKeyTypeErr)
 				currentElem.NativeDefault().Error = fmt.Sprintf("map key type must be string not %q", v.Type().Key())
 				return
 			}
 
-			// If map is empty, keep Map type and capture value kind as child.
-			if v.Len() == 0 {
+			// If map is empty or open, keep Map type and capture value kind as child.
+			if v.Len() == 0 || (r.TreatGoMapsAsOpen && v.Type().Elem().Kind() != reflect.Interface) {
 				targetValue := reflect.New(v.Type().Elem()).Elem()
 				nextElem := currentElem.NewChild("")
 				r.reflectTypeImpl(ancestorTypeRef.Copy(), nextElem, targetValue)
@@ -439,6 +1150,7 @@ func (r *Reflector) reflectTypeMapImpl(ancestorTypeRef types.AncestorTypeRef, cu
 				mapValue := v.MapIndex(k.Value)
 
 				nextElem := currentElem.NewChild(k.ExportName)
+				nextElem.Order = len(currentElem.Children)
 				if k.ExportName != k.Name {
 					// Use original Name for native defaults.
 					nextElem.NativeDefault().Name = k.Name
@@ -446,7 +1158,7 @@ func (r *Reflector) reflectTypeMapImpl(ancestorTypeRef types.AncestorTypeRef, cu
 
 				// Check for duplicate ExportName
 				if uniqKeys[k.ExportName] > 0 {
-					nextElem.Error = types.DuplicateMapKeyErr
+					nextElem.SetError(types.DuplicateMapKeyErr)
 					nextElem.NativeDefault().Error = fmt.Sprintf("duplicate map key %q (%q)", k.ExportName, k.Name)
 				}
 				uniqKeys[k.ExportName]++
@@ -466,45 +1178,353 @@ func (r *Reflector) reflectTypeStructImpl(ancestorTypeRef types.AncestorTypeRef,
 	case reflect.Struct:
 		if currentElem.Error == "" {
 			if v.NumField() == 0 {
-				currentElem.Error = types.EmptyStructErr
+				currentElem.SetError(types.EmptyStructErr)
 				return
 			}
 
-			// Count exported fields.
-			exportedFields := 0
+			// Keep track of json output names to find duplicates.
+			uniqKeys := map[string]int{}
 
-			for i := 0; i < v.NumField(); i++ {
-				structField := v.Type().Field(i)
-				targetValue := v.Field(i)
+			embedded := map[reflect.Type]bool{v.Type(): true}
+			exportedFields := r.reflectStructFields(ancestorTypeRef, currentElem, v, uniqKeys, embedded, false)
 
-				// Skip un-exported fields.
-				if structField.PkgPath != "" {
-					continue
-				}
-				exportedFields++
-
-				nextElem := currentElem.NewChild(structField.Name)
-
-				// Parse struct tags.
-				tags := types.ParseTags(structField.Tag)
-				if len(tags) > 0 {
-					for tagName, tagVal := range tags {
-						tempNative := nextElem.Native[tagName]
-						if tempNative == nil {
-							tempNative = types.NewNativeType(tagName)
-							nextElem.Native[tagName] = tempNative
-						}
-						tempNative.UpdateFromTag(tagVal)
-					}
+			if exportedFields == 0 {
+				currentElem.SetError(types.NoExportedFieldsErr)
+				return
+			}
+		}
+	}
+}
+
+// reflectStructFields adds a child element for each exported field of struct v.
+// - Fields of embedded structs without a json name are promoted to currentElem, like encoding/json.
+// - Promoted fields are skipped if a field with the same name was already added at a shallower level.
+// - embedded holds the struct types being flattened to stop recursion of embedded cycles.
+// - Returns the number of exported fields that are serialized, fields ignored with json:"-" are not counted.
+func (r *Reflector) reflectStructFields(ancestorTypeRef types.AncestorTypeRef, currentElem *types.TypeNode, v reflect.Value, uniqKeys map[string]int, embedded map[reflect.Type]bool, promoted bool) int {
+	// Count exported fields.
+	exportedFields := 0
+
+	// Embedded structs are flattened after direct fields so that direct fields take precedence.
+	promotedFields := []int{}
+
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		targetValue := v.Field(i)
+
+		if r.isPromoted(structField) {
+			promotedFields = append(promotedFields, i)
+			continue
+		}
+
+		// Skip un-exported fields.
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		// Parse struct tags.
+		tags := types.ParseTags(structField.Tag)
+
+		if promoted {
+			// Skip promoted fields that are shadowed by a field at a shallower level.
+			jsonName := structField.Name
+			if jsonTag := tags["json"]; jsonTag != nil && jsonTag.Alias != "" {
+				jsonName = jsonTag.Alias
+			}
+			if uniqKeys[util.Capitalize(jsonName)] > 0 || currentElem.ChildByName(structField.Name, nil) != nil {
+				continue
+			}
+		}
+
+		nextElem := currentElem.NewChild(structField.Name)
+		nextElem.Order = len(currentElem.Children)
+
+		if len(tags) > 0 {
+			for tagName, tagVal := range tags {
+				tempNative := nextElem.Native[tagName]
+				if tempNative == nil {
+					tempNative = types.NewNativeType(tagName)
+					nextElem.Native[tagName] = tempNative
 				}
+				tempNative.UpdateFromTag(tagVal)
+			}
+		}
 
-				r.reflectTypeImpl(ancestorTypeRef.Copy(), nextElem, targetValue)
+		if jsonTag, ok := tags["json"]; ok {
+			// A json:"-" field is not serialized so it is excluded from the schema for all dialects.
+			if jsonTag.Ignore {
+				nextElem.NativeDefault().Include = threeflag.False
 			}
 
-			if exportedFields == 0 {
-				currentElem.Error = types.NoExportedFieldsErr
-				return
+			// Record omitempty from the json tag on the native type.
+			_, omitEmpty := jsonTag.Options.Lookup("omitempty")
+			nextElem.NativeDefault().Options.AddBool(OMITEMPTY_OPTION, omitEmpty)
+		}
+
+		// Check for duplicate json output names, compared the same way as map keys.
+		// - Only serialized fields are counted as exported, a struct with only json:"-" fields has no exported fields.
+		if nextElem.NativeDefault().Include != threeflag.False {
+			exportedFields++
+
+			jsonName := nextElem.GetNativeType("json").Name
+			exportName := util.Capitalize(jsonName)
+			if uniqKeys[exportName] > 0 {
+				nextElem.SetError(types.DuplicateMapKeyErr)
+				nextElem.NativeDefault().Error = fmt.Sprintf("duplicate json key %q (%q)", exportName, jsonName)
 			}
+			uniqKeys[exportName]++
+		}
+
+		// Tag descriptions take precedence over registered descriptions.
+		if tagNative := nextElem.Native[types.TAG_DIALECT]; tagNative != nil && tagNative.Options.Get(DESCRIPTION_OPTION) != "" {
+			nextElem.Description = tagNative.Options.Get(DESCRIPTION_OPTION)
+		} else {
+			nextElem.Description = r.descriptions[v.Type().Name()+"."+structField.Name]
+		}
+
+		if example, ok := r.examples[v.Type().Name()+"."+structField.Name]; ok {
+			nextElem.NativeDefault().Options.AddKeyVal(EXAMPLE_OPTION, exampleString(example))
+		}
+
+		r.reflectTypeImpl(ancestorTypeRef.Copy(), nextElem, targetValue)
+
+		if r.CaptureExamples {
+			captureExample(nextElem, targetValue)
+		}
+
+		r.checkInline(currentElem, nextElem)
+		r.checkKeyPattern(nextElem)
+		r.checkPattern(nextElem)
+	}
+
+	for _, i := range promotedFields {
+		fieldType := v.Type().Field(i).Type
+		targetValue := v.Field(i)
+
+		// Use a zero value for nil embedded pointers.
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+			if targetValue.IsNil() {
+				targetValue = reflect.New(fieldType).Elem()
+			} else {
+				targetValue = targetValue.Elem()
+			}
+		}
+
+		if embedded[fieldType] {
+			continue
+		}
+		embedded[fieldType] = true
+		exportedFields += r.reflectStructFields(ancestorTypeRef, currentElem, targetValue, uniqKeys, embedded, true)
+		delete(embedded, fieldType)
+	}
+
+	return exportedFields
+}
+
+// checkInline validates the types.INLINE_OPTION of a struct field.
+// - Only map fields can be inline and a struct can have only one inline field.
+// - Invalid inline options are removed and reported as a native error.
+func (r *Reflector) checkInline(currentElem, nextElem *types.TypeNode) {
+	tagNative := nextElem.Native[types.TAG_DIALECT]
+	if tagNative == nil {
+		return
+	}
+	if _, ok := tagNative.Options.Lookup(types.INLINE_OPTION); !ok {
+		return
+	}
+
+	errMsg := ""
+	if nextElem.Type != generictype.Map.String() {
+		errMsg = fmt.Sprintf("inline field must be a map, found %s", nextElem.Type)
+	} else {
+		for _, child := range currentElem.Children {
+			if child == nextElem {
+				continue
+			}
+			if childTag := child.Native[types.TAG_DIALECT]; childTag != nil {
+				if _, ok := childTag.Options.Lookup(types.INLINE_OPTION); ok {
+					errMsg = fmt.Sprintf("inline field already defined: %s", child.Name)
+					break
+				}
+			}
+		}
+	}
+
+	if errMsg != "" {
+		tagNative.Options.Delete(types.INLINE_OPTION)
+		nextElem.SetError(types.InlineFieldErr)
+		nextElem.NativeDefault().Error = errMsg
+	}
+}
+
+// checkKeyPattern verifies the "keyPattern" option of a struct field.
+// - The option is only valid on maps and must be a valid regular expression.
+// - Invalid options are removed and an error is set on the field.
+func (r *Reflector) checkKeyPattern(nextElem *types.TypeNode) {
+	tagNative := nextElem.Native[types.TAG_DIALECT]
+	if tagNative == nil {
+		return
+	}
+	pattern, ok := tagNative.Options.Lookup(KEY_PATTERN_OPTION)
+	if !ok {
+		return
+	}
+
+	errMsg := ""
+	if nextElem.Type != generictype.Map.String() {
+		errMsg = fmt.Sprintf("keyPattern field must be a map, found %s", nextElem.Type)
+	} else if _, err := regexp.Compile(pattern); err != nil {
+		errMsg = fmt.Sprintf("invalid keyPattern %q: %s", pattern, err)
+	}
+
+	if errMsg != "" {
+		tagNative.Options.Delete(KEY_PATTERN_OPTION)
+		nextElem.SetError(types.KeyPatternErr)
+		nextElem.NativeDefault().Error = errMsg
+	}
+}
+
+// checkPattern verifies the "pattern" option of a struct field.
+// - The option is only valid on strings and must be a valid regular expression.
+// - Invalid options are removed and an error is set on the field.
+func (r *Reflector) checkPattern(nextElem *types.TypeNode) {
+	tagNative := nextElem.Native[types.TAG_DIALECT]
+	if tagNative == nil {
+		return
+	}
+	pattern, ok := tagNative.Options.Lookup(PATTERN_OPTION)
+	if !ok {
+		return
+	}
+
+	errMsg := ""
+	if nextElem.Type != generictype.String.String() {
+		errMsg = fmt.Sprintf("pattern field must be a string, found %s", nextElem.Type)
+	} else if _, err := regexp.Compile(pattern); err != nil {
+		errMsg = fmt.Sprintf("invalid pattern %q: %s", pattern, err)
+	}
+
+	if errMsg != "" {
+		tagNative.Options.Delete(PATTERN_OPTION)
+		nextElem.SetError(types.PatternErr)
+		nextElem.NativeDefault().Error = errMsg
+	}
+}
+
+// isPromoted returns true if the fields of an embedded struct field are promoted to the parent.
+// - The field type must be a struct or a pointer to an exported struct.
+// - A json tag with a name keeps the field nested, json:"-" excludes it.
+// - Known types like time.Time and registered types are not promoted.
+func (r *Reflector) isPromoted(structField reflect.StructField) bool {
+	if !structField.Anonymous {
+		return false
+	}
+
+	fieldType := structField.Type
+	if fieldType.Kind() == reflect.Ptr {
+		// Embedded pointers to unexported structs are ignored by encoding/json.
+		if structField.PkgPath != "" {
+			return false
+		}
+		fieldType = fieldType.Elem()
+	}
+
+	zeroValue := reflect.New(fieldType).Elem()
+	if generictype.GenericTypeOf(zeroValue) != generictype.Struct {
+		return false
+	}
+	if fieldType.PkgPath() != "" && r.knownTypes[generictype.FullPathOf(zeroValue)] != nil {
+		return false
+	}
+
+	if jsonTag := types.ParseTags(structField.Tag)["json"]; jsonTag != nil && (jsonTag.Ignore || jsonTag.Alias != "") {
+		return false
+	}
+
+	return true
+}
+
+// textMarshalerType is the type of the encoding.TextMarshaler interface.
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isTextMarshaler returns true if a type or a pointer to the type implements encoding.TextMarshaler.
+func isTextMarshaler(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// jsonMarshalerType is the type of the json.Marshaler interface.
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// isJSONMarshaler returns true if a type or a pointer to the type implements json.Marshaler.
+func isJSONMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType)
+}
+
+// formatOf returns the format of a value that refines its generic type.
+// - Integers up to 32 bits are "int32", other integers are "int64".
+// - int, uint and uintptr are always "int64" because their size depends on the platform and int64 holds them on any platform.
+// - Returns an empty string if the generic type has no format.
+func formatOf(v reflect.Value, genericType *generictype.GenericType) string {
+	switch genericType {
+	case generictype.Integer:
+		switch v.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			return "int32"
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+			return "int64"
+		}
+	case generictype.Float:
+		switch v.Kind() {
+		case reflect.Float32:
+			return "float"
+		case reflect.Float64:
+			return "double"
+		}
+	case generictype.String:
+		if generictype.IsByteSlice(v) {
+			return "byte"
+		}
+	case generictype.DateTime:
+		return "date-time"
+	}
+	return ""
+}
+
+// captureExample records the value of a scalar struct field as its example.
+// - Pointers and interfaces are followed, nil and zero values are skipped.
+// - Values that cannot be accessed (e.g. promoted from unexported embedded structs) are skipped.
+// - Existing examples are kept.
+func captureExample(nextElem *types.TypeNode, v reflect.Value) {
+	native := nextElem.NativeDefault()
+	if _, ok := native.Options.Lookup(EXAMPLE_OPTION); ok {
+		return
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.CanInterface() || v.IsZero() {
+		return
+	}
+
+	switch nextElem.Type {
+	case generictype.Boolean.String(), generictype.Integer.String(), generictype.Float.String(),
+		generictype.String.String(), generictype.DateTime.String():
+		native.Options.AddKeyVal(EXAMPLE_OPTION, exampleString(v.Interface()))
+	}
+}
+
+// exampleString converts an example value to a string.
+// - Values that implement encoding.TextMarshaler (e.g. time.Time) use their text form.
+func exampleString(x interface{}) string {
+	if m, ok := x.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
 		}
 	}
+	return fmt.Sprint(x)
 }
@@ -0,0 +1,46 @@
+package reflector
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+)
+
+// qualifiedNameRegexp matches package-qualified type names in type arguments, e.g. "github.com/org/pkg.User"
+var qualifiedNameRegexp = regexp.MustCompile(`[\w.\-/]*\.(\w+)`)
+
+// listRegexp matches slice and array prefixes in type arguments, e.g. "[]" and "[4]"
+var listRegexp = regexp.MustCompile(`\[\d*\]`)
+
+// SanitizeTypeName converts the name of an instantiated generic type into an identifier.
+// - Package paths are removed from type arguments and each word is capitalized, e.g. "Response[pkg.User]" --> "ResponseUser"
+// - Slices and arrays become "List" so that they differ from their element types, e.g. "Response[[]*pkg.User]" --> "ResponseListUser"
+// - Names without type arguments are returned unchanged.
+func SanitizeTypeName(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+
+	name = qualifiedNameRegexp.ReplaceAllString(name, "$1")
+	name = listRegexp.ReplaceAllString(name, " list ")
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	out := ""
+	for _, w := range words {
+		out += util.Capitalize(w)
+	}
+	return out
+}
+
+// typeRefName returns the TypeRef name for a Go type name using TypeNameFunc or SanitizeTypeName.
+func (r *Reflector) typeRefName(name string) string {
+	if r.TypeNameFunc != nil {
+		return r.TypeNameFunc(name)
+	}
+	return SanitizeTypeName(name)
+}
@@ -0,0 +1,192 @@
+package csvheader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// scalarTypes are generic types that can be CSV columns.
+var scalarTypes = map[string]bool{
+	generictype.Boolean.String():  true,
+	generictype.Integer.String():  true,
+	generictype.Float.String():    true,
+	generictype.String.String():   true,
+	generictype.DateTime.String(): true,
+	generictype.Duration.String(): true,
+}
+
+// CSVHeaderRenderer renders the columns of flat record types as CSV header lines.
+// - Each top-level struct renders a title line "# Name", a line of column names, and a line of generic types.
+// - Column names are json names, nested structs are flattened with "." (e.g. "inner.key").
+// - Fields that are not scalar (e.g. lists, maps) are skipped and listed in a line "# errors: ..."
+type CSVHeaderRenderer struct {
+	opt *renderer.Options
+
+	// typeRefs stores TypeRef elements by name to flatten references.
+	typeRefs map[string]*types.TypeNode
+}
+
+func NewCSVHeaderRenderer(opt *renderer.Options) *CSVHeaderRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &CSVHeaderRenderer{opt: opt}
+}
+
+// ProcessSchema renders TypeRef elements before Root elements.
+func (r *CSVHeaderRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	r.typeRefs = schema.TypeRef.ChildMap()
+
+	lines := []string{}
+	if !r.DeReference() {
+		lines = append(lines, renderer.RenderType(schema.TypeRef, r)...)
+	}
+	lines = append(lines, renderer.RenderType(schema.Root, r)...)
+
+	out := []string{}
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+func (r *CSVHeaderRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *CSVHeaderRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *CSVHeaderRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *CSVHeaderRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *CSVHeaderRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *CSVHeaderRenderer) Prefix() string {
+	if r.opt.Prefix == "" {
+		return ""
+	}
+	return strings.Repeat(r.opt.Prefix, r.opt.Indent)
+}
+
+func (r *CSVHeaderRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+func (r *CSVHeaderRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *CSVHeaderRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+// Pre renders the header lines of each top-level struct.
+func (r *CSVHeaderRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Type != generictype.Root.String() {
+		return []string{}
+	}
+
+	title := renderer.TopLevelName(t, r)
+	if title == "" {
+		return []string{}
+	}
+
+	if t.Type != generictype.Struct.String() {
+		return []string{fmt.Sprintf("# unsupported record %s (%s)", title, t.Type)}
+	}
+
+	names := []string{}
+	genericTypes := []string{}
+	errs := []string{}
+	r.flatten(t, "", map[string]bool{}, &names, &genericTypes, &errs)
+
+	out := []string{"# " + title}
+	if len(names) > 0 {
+		out = append(out, csvLine(names), csvLine(genericTypes))
+	}
+	if len(errs) > 0 {
+		out = append(out, "# errors: "+strings.Join(errs, ", "))
+	}
+	return out
+}
+
+func (r *CSVHeaderRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *CSVHeaderRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// flatten appends the columns of a struct and its nested structs.
+// - References are followed in the TypeRef tree, seen prevents cycles.
+// - Non-scalar fields and errors are appended to errs as "<path> (<type>)".
+func (r *CSVHeaderRenderer) flatten(t *types.TypeNode, prefix string, seen map[string]bool, names, genericTypes, errs *[]string) {
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		path := prefix + native.Name
+
+		if child.Error != "" {
+			*errs = append(*errs, fmt.Sprintf("%s (%s)", path, child.Error))
+			continue
+		}
+
+		if scalarTypes[child.Type] {
+			*names = append(*names, path)
+			*genericTypes = append(*genericTypes, child.Type)
+			continue
+		}
+
+		if child.Type != generictype.Struct.String() {
+			*errs = append(*errs, fmt.Sprintf("%s (%s)", path, child.Type))
+			continue
+		}
+
+		// Nested structs are flattened, references are looked up if their children were removed.
+		nested := child
+		if child.TypeRef != "" && len(child.Children) == 0 {
+			if seen[child.TypeRef] || r.typeRefs[child.TypeRef] == nil {
+				*errs = append(*errs, fmt.Sprintf("%s (%s)", path, child.TypeRef))
+				continue
+			}
+			nested = r.typeRefs[child.TypeRef]
+		}
+
+		seen[child.TypeRef] = true
+		r.flatten(nested, path+".", seen, names, genericTypes, errs)
+		delete(seen, child.TypeRef)
+	}
+}
+
+// csvLine returns values as a CSV line without a line break.
+func csvLine(values []string) string {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write(values)
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,81 @@
+package csvheader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type BasicStruct struct {
+	BoolVal    bool
+	IntVal     int
+	Float64Val float64
+	StringVal  string
+}
+
+type InnerStruct struct {
+	Key           string   `json:"key"`
+	ListOfStrings []string `json:"listOfStrings"`
+}
+
+type RecordStruct struct {
+	ID        int         `json:"id"`
+	Name      string      `json:"name,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	Ignored   string      `json:"-"`
+	Inner     InnerStruct `json:"inner"`
+	Anon      struct {
+		Flag bool `json:"flag"`
+	} `json:"anon"`
+	Tags map[string]string `json:"tags"`
+}
+
+func TestCSVHeaderRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{
+			name:  "basic",
+			value: BasicStruct{},
+			want: []string{
+				`# BasicStruct`,
+				`BoolVal,IntVal,Float64Val,StringVal`,
+				`boolean,integer,float,string`,
+			},
+		},
+		{
+			name:  "record",
+			value: RecordStruct{},
+			want: []string{
+				`# InnerStruct`,
+				`key`,
+				`string`,
+				`# errors: listOfStrings (list)`,
+				`# RecordStruct`,
+				`id,name,created_at,inner.key,anon.flag`,
+				`integer,string,datetime,string,boolean`,
+				`# errors: inner.listOfStrings (list), tags (map)`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+		opt := renderer.NewOptions()
+		opt.PreserveOrder = true
+
+		gotStrings, err := NewCSVHeaderRenderer(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}
@@ -0,0 +1,268 @@
+package cue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// identifierRegexp matches names that can be used as field names without quotes.
+// - Names starting with "_" or "#" are hidden fields or definitions in CUE so they are quoted.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z$][A-Za-z0-9_$]*$`)
+
+// CUERenderer renders a schema as CUE definitions.
+// - Each TypeRef becomes a definition, e.g. "#Name: {...}"
+// - Root elements are defined only if they are not already defined as a TypeRef.
+// - Optional fields use "?" and nullable fields allow null, e.g. "name?: string | null"
+// - Errors are rendered as comments.
+type CUERenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+
+	// usesTime is set if a definition uses the CUE time package.
+	usesTime bool
+}
+
+func NewCUERenderer(opt *renderer.Options) *CUERenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	// CUE is formatted with tabs.
+	return &CUERenderer{opt: opt, defaultPrefix: "\t"}
+}
+
+// ProcessSchema renders TypeRef elements before Root elements.
+// - The time package is imported if a definition uses time.Time.
+func (r *CUERenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	r.usesTime = false
+
+	lines := []string{}
+	if !r.DeReference() {
+		lines = append(lines, renderer.RenderType(schema.TypeRef, r)...)
+	}
+	lines = append(lines, renderer.RenderType(schema.Root, r)...)
+
+	out := []string{}
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+
+	if r.usesTime {
+		out = append([]string{`import "time"`}, out...)
+	}
+	return out, nil
+}
+
+func (r *CUERenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *CUERenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *CUERenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *CUERenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *CUERenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *CUERenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *CUERenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+// Pre renders a complete definition for each top-level element.
+func (r *CUERenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Type != generictype.Root.String() {
+		return []string{}
+	}
+
+	name := renderer.TopLevelName(t, r)
+	if name == "" {
+		return []string{}
+	}
+
+	out := r.errorComments(t)
+
+	expr := r.typeExpr(t)
+	expr[0] = fmt.Sprintf("%s#%s: %s", r.Prefix(), name, expr[0])
+	out = append(out, expr...)
+
+	return out
+}
+
+func (r *CUERenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *CUERenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *CUERenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *CUERenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// errorComments returns comment lines for errors on an element and its unnamed descendants.
+func (r *CUERenderer) errorComments(t *types.TypeNode) []string {
+	out := []string{}
+
+	for n := t; n != nil; {
+		if n.Error != "" {
+			out = append(out, fmt.Sprintf("%s// ERROR: %s", r.Prefix(), n.Error))
+		}
+
+		// Continue with unnamed list items and map values.
+		if len(n.Children) == 1 && n.Children[0].Name == "" && (n.TypeRef == "" || r.DeReference()) {
+			n = n.Children[0]
+		} else {
+			n = nil
+		}
+	}
+
+	return out
+}
+
+// fieldExpr returns the CUE expression for an element that allows null if the element is nullable.
+func (r *CUERenderer) fieldExpr(t *types.TypeNode) []string {
+	expr := r.typeExpr(t)
+
+	if t.Nullable {
+		expr[len(expr)-1] += " | null"
+	}
+
+	return expr
+}
+
+// typeExpr returns the lines of the CUE expression for an element.
+// - The first line has no prefix so that it can follow a name.
+func (r *CUERenderer) typeExpr(t *types.TypeNode) []string {
+	// References are rendered by name unless de-referencing.
+	// - Cyclical references are always kept as references.
+	if t.TypeRef != "" {
+		if !r.DeReference() || t.Error == types.CyclicalReferenceErr {
+			return []string{"#" + t.TypeRef}
+		}
+	}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		return []string{"bool"}
+	case generictype.Integer.String():
+		return []string{"int"}
+	case generictype.Float.String():
+		return []string{"number"}
+	case generictype.String.String():
+		return []string{"string"}
+	case generictype.DateTime.String():
+		r.usesTime = true
+		return []string{"time.Time"}
+	case generictype.Duration.String():
+		return []string{util.ValueIfTrue(r.opt.DurationAsString, "string", "int")}
+	case generictype.Any.String():
+		return []string{"_"}
+	case generictype.Struct.String():
+		return r.structExpr(t)
+	case generictype.List.String():
+		if len(t.Children) == 0 {
+			return []string{"[...]"}
+		}
+		return wrap("[...", r.fieldExpr(t.Children[0]), "]")
+	case generictype.Map.String():
+		if len(t.Children) == 0 {
+			return []string{"{...}"}
+		}
+		return wrap("{[string]: ", r.fieldExpr(t.Children[0]), "}")
+	case generictype.Union.String():
+		return r.unionExpr(t)
+	}
+
+	// Invalid and unknown types.
+	return []string{"_"}
+}
+
+// structExpr returns the lines of a struct with one line per field.
+func (r *CUERenderer) structExpr(t *types.TypeNode) []string {
+	out := []string{"{"}
+
+	r.SetIndent(r.Indent() + 1)
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		name := native.Name
+		if !identifierRegexp.MatchString(name) {
+			name = fmt.Sprintf("%q", name)
+		}
+		if child.IsOptional() {
+			name += "?"
+		}
+
+		out = append(out, r.errorComments(child)...)
+
+		expr := r.fieldExpr(child)
+		expr[0] = fmt.Sprintf("%s%s: %s", r.Prefix(), name, expr[0])
+		out = append(out, expr...)
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	if len(out) == 1 {
+		return []string{"{}"}
+	}
+
+	return append(out, r.Prefix()+"}")
+}
+
+// unionExpr returns a disjunction of the alternatives of a union.
+func (r *CUERenderer) unionExpr(t *types.TypeNode) []string {
+	if len(t.Children) == 0 {
+		return []string{"_"}
+	}
+
+	out := []string{}
+	for i, child := range renderer.Children(t, r) {
+		expr := r.fieldExpr(child)
+		if i > 0 {
+			out[len(out)-1] += " | " + expr[0]
+			expr = expr[1:]
+		}
+		out = append(out, expr...)
+	}
+	return out
+}
+
+// wrap adds open and close text around the lines of an expression.
+func wrap(open string, expr []string, close string) []string {
+	out := append([]string{}, expr...)
+	out[0] = open + out[0]
+	out[len(out)-1] += close
+	return out
+}
@@ -0,0 +1,158 @@
+package cue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type BasicStruct struct {
+	BoolVal     bool
+	IntVal      int
+	Float64Val  float64
+	StringVal   string    `json:"stringVal"`
+	TimeVal     time.Time `json:"time-val"`
+	OptionalVal *string   `json:"optionalVal,omitempty"`
+	Ignored     string    `json:"-"`
+	ListVal     []*int
+	MapVal      map[string][]string
+	Anonymous   struct {
+		Key string
+	}
+	BadVal chan int
+}
+
+type OuterStruct struct {
+	ID    int          `json:"id"`
+	Inner *InnerStruct `json:"inner"`
+}
+
+type InnerStruct struct {
+	ListOfStrings []string       `json:"listOfStrings"`
+	ListOfStructs []*BasicStruct `json:"listOfStructs"`
+}
+
+type AStruct struct {
+	AName  string   `json:"aName,omitempty"`
+	AChild *AStruct `json:"aChild,omitempty"`
+}
+
+func TestCUERenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		deref bool
+		want  []string
+	}{
+		{
+			name:  "basic",
+			value: BasicStruct{},
+			want: []string{
+				`import "time"`,
+				`#BasicStruct: {`,
+				`	Anonymous: {`,
+				`		Key: string`,
+				`	}`,
+				`	// ERROR: kind not supported`,
+				`	BadVal: _`,
+				`	BoolVal: bool`,
+				`	Float64Val: number`,
+				`	IntVal: int`,
+				`	ListVal: [...int | null]`,
+				`	MapVal: {[string]: [...string]}`,
+				`	optionalVal?: string | null`,
+				`	stringVal: string`,
+				`	"time-val": time.Time`,
+				`}`,
+			},
+		},
+		{
+			name:  "outer",
+			value: OuterStruct{},
+			want: []string{
+				`import "time"`,
+				`#BasicStruct: {`,
+				`	Anonymous: {`,
+				`		Key: string`,
+				`	}`,
+				`	// ERROR: kind not supported`,
+				`	BadVal: _`,
+				`	BoolVal: bool`,
+				`	Float64Val: number`,
+				`	IntVal: int`,
+				`	ListVal: [...int | null]`,
+				`	MapVal: {[string]: [...string]}`,
+				`	optionalVal?: string | null`,
+				`	stringVal: string`,
+				`	"time-val": time.Time`,
+				`}`,
+				`#InnerStruct: {`,
+				`	listOfStrings: [...string]`,
+				`	listOfStructs: [...#BasicStruct | null]`,
+				`}`,
+				`#OuterStruct: {`,
+				`	id: int`,
+				`	inner?: #InnerStruct | null`,
+				`}`,
+			},
+		},
+		{
+			name:  "outer-deref",
+			value: OuterStruct{},
+			deref: true,
+			want: []string{
+				`import "time"`,
+				`#OuterStruct: {`,
+				`	id: int`,
+				`	inner?: {`,
+				`		listOfStrings: [...string]`,
+				`		listOfStructs: [...{`,
+				`			Anonymous: {`,
+				`				Key: string`,
+				`			}`,
+				`			// ERROR: kind not supported`,
+				`			BadVal: _`,
+				`			BoolVal: bool`,
+				`			Float64Val: number`,
+				`			IntVal: int`,
+				`			ListVal: [...int | null]`,
+				`			MapVal: {[string]: [...string]}`,
+				`			optionalVal?: string | null`,
+				`			stringVal: string`,
+				`			"time-val": time.Time`,
+				`		} | null]`,
+				`	} | null`,
+				`}`,
+			},
+		},
+		{
+			name:  "cycle",
+			value: AStruct{},
+			deref: true,
+			want: []string{
+				`#AStruct: {`,
+				`	// ERROR: cyclical reference`,
+				`	aChild?: #AStruct | null`,
+				`	aName?: string`,
+				`}`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+		opt := renderer.NewOptions()
+		opt.DeReference = test.deref
+
+		gotStrings, err := NewCUERenderer(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}
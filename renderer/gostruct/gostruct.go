@@ -0,0 +1,282 @@
+package gostruct
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// DEFAULT_PACKAGE is the package name used if PackageName is empty.
+const DEFAULT_PACKAGE = "schema"
+
+// identifierRegexp matches names that can be used as exported Go identifiers.
+var identifierRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// GoStructRenderer renders a schema as Go source with one type declaration per top-level element.
+// - Each TypeRef becomes a named type, e.g. "type Name struct {...}"
+// - Root elements are declared only if they are not already declared as a TypeRef.
+// - Field names are capitalized json names with a json tag that keeps the original name.
+// - Nullable structs and references are pointers.
+// - Output is formatted with go/format.
+type GoStructRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+
+	// PackageName is the name in the package clause, DEFAULT_PACKAGE if empty.
+	PackageName string
+
+	// usesTime is true if the "time" package is used.
+	usesTime bool
+}
+
+func NewGoStructRenderer(opt *renderer.Options) *GoStructRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &GoStructRenderer{opt: opt, defaultPrefix: "\t"}
+}
+
+// ProcessSchema renders a Go source file with TypeRef elements before Root elements.
+// - If the source cannot be formatted, the unformatted lines are returned with the error.
+func (r *GoStructRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	r.usesTime = false
+
+	decls := []string{}
+	if !r.DeReference() {
+		decls = append(decls, renderer.RenderType(schema.TypeRef, r)...)
+	}
+	decls = append(decls, renderer.RenderType(schema.Root, r)...)
+
+	packageName := r.PackageName
+	if packageName == "" {
+		packageName = DEFAULT_PACKAGE
+	}
+
+	out := []string{"package " + packageName}
+	if r.usesTime {
+		out = append(out, `import "time"`)
+	}
+	for _, line := range decls {
+		if line == "" {
+			continue
+		}
+
+		// Separate top-level declarations and their comments with a blank line.
+		isTopLevel := strings.HasPrefix(line, "type ") || strings.HasPrefix(line, "// ")
+		if isTopLevel && !strings.HasPrefix(out[len(out)-1], "// ") {
+			out = append(out, "")
+		}
+		out = append(out, line)
+	}
+
+	b, err := format.Source([]byte(strings.Join(out, "\n")))
+	if err != nil {
+		return out, err
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n"), nil
+}
+
+func (r *GoStructRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *GoStructRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *GoStructRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *GoStructRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *GoStructRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *GoStructRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *GoStructRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return t.GetNativeType("json")
+}
+
+func (r *GoStructRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *GoStructRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+// Pre renders a complete type declaration for each top-level element.
+// - Fields are rendered here so that nested anonymous structs can be inlined.
+func (r *GoStructRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Type != generictype.Root.String() {
+		return []string{}
+	}
+
+	name := r.declName(t)
+	if name == "" {
+		return []string{}
+	}
+
+	out := []string{}
+	if t.Error != "" {
+		out = append(out, fmt.Sprintf("%s// ERROR: %s", r.Prefix(), t.Error))
+	}
+
+	expr := r.typeExpr(t)
+	expr[0] = fmt.Sprintf("%stype %s %s", r.Prefix(), name, expr[0])
+	out = append(out, expr...)
+
+	return out
+}
+
+func (r *GoStructRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *GoStructRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// declName returns the type name for a top-level element.
+// - Returns empty string if the element should not be declared.
+func (r *GoStructRenderer) declName(t *types.TypeNode) string {
+	return util.ToIdentifier(renderer.TopLevelName(t, r))
+}
+
+// fieldName returns an exported Go field name for a json name.
+func fieldName(jsonName string) string {
+	if name := util.Capitalize(jsonName); identifierRegexp.MatchString(name) {
+		return name
+	}
+	if name := util.ToIdentifier(jsonName); identifierRegexp.MatchString(name) {
+		return name
+	}
+	return "Field" + util.ToIdentifier(jsonName)
+}
+
+// fieldExpr returns the Go type for a struct field, list item, or map value.
+// - Nullable structs and references are pointers, other types use their zero value for null.
+func (r *GoStructRenderer) fieldExpr(t *types.TypeNode) []string {
+	expr := r.typeExpr(t)
+
+	if t.Nullable && t.Type == generictype.Struct.String() {
+		expr = prepend("*", expr)
+	}
+
+	return expr
+}
+
+// typeExpr returns the lines of the Go type for an element.
+// - The first line has no prefix so that it can follow a name.
+func (r *GoStructRenderer) typeExpr(t *types.TypeNode) []string {
+	// References are rendered by name unless de-referencing.
+	// - Cyclical references are always kept as references.
+	if t.TypeRef != "" {
+		if !r.DeReference() || t.Error == types.CyclicalReferenceErr {
+			return []string{util.ToIdentifier(t.TypeRef)}
+		}
+	}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		return []string{"bool"}
+	case generictype.Integer.String():
+		return []string{"int64"}
+	case generictype.Float.String():
+		return []string{"float64"}
+	case generictype.String.String():
+		return []string{"string"}
+	case generictype.DateTime.String():
+		r.usesTime = true
+		return []string{"time.Time"}
+	case generictype.Duration.String():
+		r.usesTime = true
+		return []string{"time.Duration"}
+	case generictype.Struct.String():
+		return r.structExpr(t)
+	case generictype.List.String():
+		if len(t.Children) == 0 {
+			return []string{"[]interface{}"}
+		}
+		return prepend("[]", r.fieldExpr(t.Children[0]))
+	case generictype.Map.String():
+		if len(t.Children) == 0 {
+			return []string{"map[string]interface{}"}
+		}
+		return prepend("map[string]", r.fieldExpr(t.Children[0]))
+	}
+
+	// Any, invalid, and unknown types.
+	return []string{"interface{}"}
+}
+
+// structExpr returns the lines of a struct type with one line per field.
+func (r *GoStructRenderer) structExpr(t *types.TypeNode) []string {
+	out := []string{"struct {"}
+
+	usedNames := map[string]bool{}
+
+	r.SetIndent(r.Indent() + 1)
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		// Number duplicate names, e.g. "a-b" and "a_b" are both "AB".
+		name := fieldName(native.Name)
+		baseName := name
+		for n := 2; usedNames[name]; n++ {
+			name = fmt.Sprintf("%s%d", baseName, n)
+		}
+		usedNames[name] = true
+
+		tag := native.Name
+		if jsonNative := child.Native["json"]; jsonNative != nil {
+			if _, ok := jsonNative.Options.Lookup("omitempty"); ok {
+				tag += ",omitempty"
+			}
+		}
+
+		if child.Error != "" {
+			out = append(out, fmt.Sprintf("%s// ERROR: %s", r.Prefix(), child.Error))
+		}
+
+		expr := r.fieldExpr(child)
+		expr[0] = fmt.Sprintf("%s%s %s", r.Prefix(), name, expr[0])
+		expr[len(expr)-1] += fmt.Sprintf(" `json:%q`", tag)
+		out = append(out, expr...)
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	if len(out) == 1 {
+		return []string{"struct{}"}
+	}
+
+	return append(out, r.Prefix()+"}")
+}
+
+// prepend adds text before the first line of an expression.
+func prepend(text string, expr []string) []string {
+	out := append([]string{}, expr...)
+	out[0] = text + out[0]
+	return out
+}
@@ -0,0 +1,95 @@
+package gostruct
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Person struct {
+	Name      string             `json:"name"`
+	Born      time.Time          `json:"born,omitempty"`
+	Home      *Address           `json:"home"`
+	Addresses []Address          `json:"addresses"`
+	Tags      map[string]string  `json:"tags"`
+	Friends   map[string]*Person `json:"friends"`
+	Ignored   string             `json:"-"`
+	DashName  int                `json:"dash-name"`
+}
+
+func TestGoStructRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		deref bool
+		want  []string
+	}{
+		{
+			name: "person",
+			want: []string{
+				"package schema",
+				"",
+				"import \"time\"",
+				"",
+				"type Address struct {",
+				"\tCity string `json:\"city\"`",
+				"}",
+				"",
+				"type Person struct {",
+				"\tAddresses []Address          `json:\"addresses\"`",
+				"\tBorn      time.Time          `json:\"born,omitempty\"`",
+				"\tDashName  int64              `json:\"dash-name\"`",
+				"\tFriends   map[string]*Person `json:\"friends\"`",
+				"\tHome      *Address           `json:\"home\"`",
+				"\tName      string             `json:\"name\"`",
+				"\tTags      map[string]string  `json:\"tags\"`",
+				"}",
+			},
+		},
+		{
+			name:  "person-deref",
+			deref: true,
+			want: []string{
+				"package schema",
+				"",
+				"import \"time\"",
+				"",
+				"type Person struct {",
+				"\tAddresses []struct {",
+				"\t\tCity string `json:\"city\"`",
+				"\t} `json:\"addresses\"`",
+				"\tBorn     time.Time          `json:\"born,omitempty\"`",
+				"\tDashName int64              `json:\"dash-name\"`",
+				"\tFriends  map[string]*Person `json:\"friends\"`",
+				"\tHome     *struct {",
+				"\t\tCity string `json:\"city\"`",
+				"\t} `json:\"home\"`",
+				"\tName string            `json:\"name\"`",
+				"\tTags map[string]string `json:\"tags\"`",
+				"}",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := reflector.NewReflector()
+			r.TreatGoMapsAsOpen = true
+			schema := r.DeriveSchema(Person{}, test.name)
+
+			opt := renderer.NewOptions()
+			opt.DeReference = test.deref
+			got, err := NewGoStructRenderer(opt).ProcessSchema(schema)
+			if err != nil {
+				t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+			}
+			util.CompareStrings(t, test.name, got, test.want)
+		})
+	}
+}
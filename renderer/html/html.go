@@ -0,0 +1,263 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// style is the inline CSS of the page so that the page does not need external assets.
+var style = []string{
+	`body { font-family: sans-serif; margin: 2em; }`,
+	`details { border: 1px solid #ccc; border-radius: 4px; margin: 0.5em 0; padding: 0.5em; }`,
+	`summary { cursor: pointer; font-weight: bold; }`,
+	`ul { list-style: none; padding-left: 1.5em; }`,
+	`.name { font-family: monospace; }`,
+	`.type { color: #666; font-family: monospace; }`,
+	`.optional { color: #999; font-style: italic; }`,
+	`.deprecated { text-decoration: line-through; }`,
+	`.error { color: #b00; font-weight: bold; }`,
+}
+
+// HTMLRenderer renders a schema as a self-contained HTML page for browsing.
+// - Each top-level element (root or TypeRef) is a collapsible section with a list of its fields.
+// - References link to the section of their TypeRef unless de-referencing.
+// - Errors are highlighted next to their element.
+type HTMLRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+}
+
+func NewHTMLRenderer(opt *renderer.Options) *HTMLRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	// Nested elements are indented with spaces.
+	return &HTMLRenderer{opt: opt, defaultPrefix: "  "}
+}
+
+// ProcessSchema renders a page with settings that change options for this call only, see renderer.Options.WithSettings.
+func (r *HTMLRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	c, err := r.withSettings(settings...)
+	if err != nil {
+		return []string{}, err
+	}
+	return renderer.RenderSchema(schema, c), nil
+}
+
+// ProcessSchemaTo writes lines to w as they are rendered, see renderer.WriterRenderer.
+func (r *HTMLRenderer) ProcessSchemaTo(w io.Writer, schema *types.Schema, settings ...string) error {
+	c, err := r.withSettings(settings...)
+	if err != nil {
+		return err
+	}
+	return renderer.RenderSchemaTo(w, schema, c)
+}
+
+// withSettings returns a copy of the renderer with a copy of its options.
+// - Header and Footer change the indent of the copy so that shared Options are not changed.
+func (r *HTMLRenderer) withSettings(settings ...string) (*HTMLRenderer, error) {
+	opt, err := r.opt.WithSettings(settings...)
+	if err != nil {
+		return nil, err
+	}
+	return &HTMLRenderer{opt: opt, defaultPrefix: r.defaultPrefix}, nil
+}
+
+func (r *HTMLRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *HTMLRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *HTMLRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *HTMLRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *HTMLRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *HTMLRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *HTMLRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+// Header starts the document with the inline style sheet.
+// - Elements are rendered as XHTML-compatible markup so that the page is well-formed.
+func (r *HTMLRenderer) Header(schema *types.Schema) []string {
+	out := []string{
+		`<!DOCTYPE html>`,
+		`<html>`,
+		`<head>`,
+		`<meta charset="utf-8" />`,
+		`<title>Schema</title>`,
+		`<style>`,
+	}
+	out = append(out, style...)
+	out = append(out,
+		`</style>`,
+		`</head>`,
+		`<body>`,
+	)
+
+	// Sections are indented inside body.
+	r.SetIndent(r.Indent() + 1)
+
+	return out
+}
+
+func (r *HTMLRenderer) Footer(schema *types.Schema) []string {
+	r.SetIndent(r.Indent() - 1)
+
+	return []string{
+		`</body>`,
+		`</html>`,
+	}
+}
+
+// Pre opens the markup of an element.
+// - Root elements are sections with a heading.
+// - Top-level elements are collapsible details with an anchor.
+// - All other elements are list items.
+func (r *HTMLRenderer) Pre(t *types.TypeNode) []string {
+	// Root elements start a new section.
+	if t.Type == generictype.Root.String() {
+		out := []string{r.Prefix() + `<section>`}
+		r.SetIndent(r.Indent() + 1)
+		return append(out, r.Prefix()+`<h1>`+html.EscapeString(t.Name)+`</h1>`)
+	}
+
+	if t.Parent.Type == generictype.Root.String() {
+		heading := t.MapKey()
+		if heading == "" {
+			heading = "-"
+		}
+
+		out := []string{r.Prefix() + fmt.Sprintf(`<details id="%s" open="open">`, html.EscapeString(r.anchor(t)))}
+		r.SetIndent(r.Indent() + 1)
+		out = append(out, r.Prefix()+`<summary>`+html.EscapeString(heading)+` `+r.typeSpan(t)+r.errorSpan(t)+`</summary>`)
+		if r.hasChildren(t) {
+			out = append(out, r.Prefix()+`<ul>`)
+			r.SetIndent(r.Indent() + 1)
+		}
+		return out
+	}
+
+	item := r.Prefix() + `<li>` + r.nameSpan(t) + ` ` + r.typeSpan(t) + r.errorSpan(t)
+	if !r.hasChildren(t) {
+		// Items without children are closed on the same line.
+		return []string{item + `</li>`}
+	}
+
+	r.SetIndent(r.Indent() + 1)
+	out := []string{item, r.Prefix() + `<ul>`}
+	r.SetIndent(r.Indent() + 1)
+	return out
+}
+
+// Post closes the markup opened by Pre.
+// - Post is called with the indent of Pre.
+func (r *HTMLRenderer) Post(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() {
+		return []string{r.Prefix() + `</section>`}
+	}
+
+	isTopLevel := t.Parent.Type == generictype.Root.String()
+	if !isTopLevel && !r.hasChildren(t) {
+		return []string{}
+	}
+
+	out := []string{}
+	if r.hasChildren(t) {
+		out = append(out, r.Prefix()+r.opt.IndentPrefix(r.defaultPrefix)+`</ul>`)
+	}
+	return append(out, r.Prefix()+util.ValueIfTrue(isTopLevel, `</details>`, `</li>`))
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *HTMLRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// hasChildren returns true if the children of an element are rendered.
+// - Children of references are only rendered if de-referencing.
+func (r *HTMLRenderer) hasChildren(t *types.TypeNode) bool {
+	return len(t.Children) > 0 && (r.DeReference() || t.TypeRef == "")
+}
+
+// anchor returns the id of a top-level element, e.g. "TypeRef.Name"
+func (r *HTMLRenderer) anchor(t *types.TypeNode) string {
+	return t.Parent.Name + "." + t.MapKey()
+}
+
+// nameSpan returns the field name of an element, list items and map values are shown as "[]" and "{*}".
+func (r *HTMLRenderer) nameSpan(t *types.TypeNode) string {
+	name := r.NativeType(t).Name
+	if name == "" {
+		name = "[]"
+		if t.Parent.Type == generictype.Map.String() {
+			name = "{*}"
+		} else if t.Parent.Type == generictype.Union.String() {
+			name = generictype.Interface.PathDefault()
+		}
+	}
+
+	class := "name"
+	if r.opt.IsDeprecated(t) {
+		class += " deprecated"
+	}
+
+	out := fmt.Sprintf(`<span class="%s">%s</span>`, class, html.EscapeString(name))
+	if t.Name != "" && t.IsOptional() {
+		out += ` <span class="optional">optional</span>`
+	}
+	return out
+}
+
+// typeSpan returns the generic type of an element with a link to its TypeRef if set.
+// - References are plain text if de-referencing because TypeRef sections are not rendered.
+func (r *HTMLRenderer) typeSpan(t *types.TypeNode) string {
+	out := html.EscapeString(t.Type)
+	if t.Nullable {
+		out += "|null"
+	}
+
+	if t.TypeRef != "" {
+		ref := html.EscapeString(t.TypeRef)
+		if r.DeReference() {
+			out += " (" + ref + ")"
+		} else {
+			anchor := html.EscapeString(types.TYPEREF_NAME + "." + t.TypeRef)
+			out += fmt.Sprintf(` (<a href="#%s">%s</a>)`, anchor, ref)
+		}
+	}
+
+	return `<span class="type">` + out + `</span>`
+}
+
+// errorSpan returns the highlighted error of an element or empty string.
+func (r *HTMLRenderer) errorSpan(t *types.TypeNode) string {
+	if t.Error == "" {
+		return ""
+	}
+	return ` <span class="error">ERROR: ` + html.EscapeString(t.Error) + `</span>`
+}
@@ -0,0 +1,110 @@
+package html
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type OuterStruct struct {
+	ID    int          `json:"id"`
+	Inner *InnerStruct `json:"inner,omitempty"`
+}
+
+type InnerStruct struct {
+	Label  string            `json:"label"`
+	Tags   map[string]string `json:"tags"`
+	BadVal chan int          `json:"badVal"`
+}
+
+func TestHTMLRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		deref    bool
+		settings []string
+		want     []string
+	}{
+		{
+			name: "outer",
+			want: []string{
+				`<details id="Root.outer" open="open">`,
+				`<details id="TypeRef.InnerStruct" open="open">`,
+				`<details id="TypeRef.OuterStruct" open="open">`,
+				`<a href="#TypeRef.InnerStruct">InnerStruct</a>`,
+				`<span class="name">label</span>`,
+				`<span class="error">ERROR: kind not supported</span>`,
+			},
+		},
+		{
+			name:  "outer-deref",
+			deref: true,
+			want: []string{
+				`<details id="Root.outer-deref" open="open">`,
+				`<span class="type">struct|null (InnerStruct)</span>`,
+				`<span class="name">{*}</span>`,
+			},
+		},
+		{
+			name:     "outer-settings",
+			settings: []string{"deref", "prefix=\t"},
+			want: []string{
+				"\t<details id=\"Root.outer-settings\" open=\"open\">",
+				`<span class="type">struct|null (InnerStruct)</span>`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(OuterStruct{}, test.name)
+
+		opt := renderer.NewOptions()
+		opt.DeReference = test.deref
+
+		gotStrings, err := NewHTMLRenderer(opt).ProcessSchema(schema, test.settings...)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+		got := strings.Join(gotStrings, "\n")
+
+		ok := true
+		// Settings, defaults and indents do not change the caller's options.
+		if opt.DeReference != test.deref || opt.Prefix != "" || opt.Indent != 0 {
+			t.Errorf("TEST_FAIL %s: options changed: %+v", test.name, *opt)
+			ok = false
+		}
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("TEST_FAIL %s: missing %q", test.name, want)
+				ok = false
+			}
+		}
+
+		// Pages must be well-formed with balanced tags.
+		if err := checkWellFormed(got); err != nil {
+			t.Errorf("TEST_FAIL %s: not well-formed: %s\n%s", test.name, err, got)
+			ok = false
+		}
+
+		if ok {
+			t.Logf("TEST_OK %s", test.name)
+		}
+	}
+}
+
+// checkWellFormed parses a page with a strict XML decoder, which fails on unbalanced or unescaped markup.
+func checkWellFormed(page string) error {
+	d := xml.NewDecoder(strings.NewReader(page))
+	d.Strict = true
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
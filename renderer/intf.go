@@ -1,6 +1,8 @@
 package renderer
 
 import (
+	"io"
+
 	"github.com/gitmann/b9schema-golang/common/types"
 )
 
@@ -12,6 +14,12 @@ type Renderer interface {
 	// DeReference returns true if schema references should be replaced with inline types.
 	DeReference() bool
 
+	// PreserveOrder returns true if children should be rendered in stored order instead of alphabetically.
+	PreserveOrder() bool
+
+	// ExcludeErrored returns true if elements with errors and their children should not be rendered.
+	ExcludeErrored() bool
+
 	// Indent returns the current indent value.
 	Indent() int
 
@@ -24,6 +32,11 @@ type Renderer interface {
 	// NativeType returns the native type for the renderer.
 	NativeType(t *types.TypeNode) *types.NativeType
 
+	// Header and Footer return strings before/after the rendered schema, e.g. document metadata.
+	// - Called by RenderSchema.
+	Header(schema *types.Schema) []string
+	Footer(schema *types.Schema) []string
+
 	// Pre and Post return strings before/after a type element's children are processed.
 	Pre(t *types.TypeNode) []string
 	Post(t *types.TypeNode) []string
@@ -31,3 +44,13 @@ type Renderer interface {
 	// Path is a function that builds a path string from a TypeNode.
 	Path(t *types.TypeNode) []string
 }
+
+// WriterRenderer is a Renderer that can write its output directly instead of building a slice of lines.
+// - Used by ProcessSchemaTo to stream large schemas.
+type WriterRenderer interface {
+	Renderer
+
+	// ProcessSchemaTo starts the render process on a Schema and writes lines to w.
+	// - Lines are the same as the result of ProcessSchema with a newline after each line.
+	ProcessSchemaTo(w io.Writer, schema *types.Schema, settings ...string) error
+}
@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// MarkdownRenderer renders a schema as Markdown tables.
+// - Each top-level element (root or TypeRef) gets a heading and a table of its fields.
+type MarkdownRenderer struct {
+	opt *renderer.Options
+}
+
+func NewMarkdownRenderer(opt *renderer.Options) *MarkdownRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &MarkdownRenderer{opt: opt}
+}
+
+func (r *MarkdownRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	return renderer.RenderSchema(schema, r), nil
+}
+
+// ProcessSchemaTo writes lines to w as they are rendered, see renderer.WriterRenderer.
+func (r *MarkdownRenderer) ProcessSchemaTo(w io.Writer, schema *types.Schema, settings ...string) error {
+	return renderer.RenderSchemaTo(w, schema, r)
+}
+
+func (r *MarkdownRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *MarkdownRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *MarkdownRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *MarkdownRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *MarkdownRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *MarkdownRenderer) Prefix() string {
+	if r.opt.Prefix == "" {
+		return ""
+	}
+	return strings.Repeat(r.opt.Prefix, r.opt.Indent)
+}
+
+func (r *MarkdownRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+func (r *MarkdownRenderer) Pre(t *types.TypeNode) []string {
+	// Root elements start a new section.
+	if t.Type == generictype.Root.String() {
+		if len(t.Children) == 0 {
+			return []string{}
+		}
+		return []string{"# " + t.Name}
+	}
+
+	// Top-level elements get a heading and a table header.
+	if t.Parent.Type == generictype.Root.String() {
+		heading := t.MapKey()
+		if heading == "" {
+			heading = "-"
+		}
+
+		out := []string{
+			"## " + heading,
+			"Type: " + r.typeString(t),
+		}
+		if t.Error != "" {
+			out = append(out, "Error: "+t.Error)
+		}
+
+		// Children are only rendered if the element is not a reference.
+		if len(t.Children) > 0 && (r.DeReference() || t.TypeRef == "") {
+			out = append(out,
+				"| Field | Type | Required | Nullable | Notes |",
+				"| --- | --- | --- | --- | --- |",
+			)
+		}
+
+		return out
+	}
+
+	// All other elements are table rows.
+	required := "-"
+	if t.Name != "" {
+		required = util.ValueIfTrue(t.IsOptional(), "no", "yes")
+	}
+
+	notes := ""
+	if t.Error != "" {
+		notes = "ERROR: " + t.Error
+	}
+
+	field := strings.Join(r.Path(t), ".")
+	if r.opt.IsDeprecated(t) {
+		field = "~~" + field + "~~"
+	}
+
+	row := []string{
+		field,
+		r.typeString(t),
+		required,
+		util.ValueIfTrue(t.Nullable, "yes", "no"),
+		notes,
+	}
+	for i, cell := range row {
+		row[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+
+	return []string{"| " + strings.Join(row, " | ") + " |"}
+}
+
+func (r *MarkdownRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *MarkdownRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *MarkdownRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+// - Path is relative to the top-level element.
+// - Names use the json dialect.
+// - List elements are shown as "[]" and map values as "{*}".
+func (r *MarkdownRenderer) Path(t *types.TypeNode) []string {
+	if t.Parent == nil || t.Parent.Type == generictype.Root.String() {
+		return []string{}
+	}
+
+	parentPath := r.Path(t.Parent)
+
+	name := r.NativeType(t).Name
+	if name == "" {
+		suffix := "[]"
+		if t.Parent.Type == generictype.Map.String() {
+			suffix = "{*}"
+		} else if t.Parent.Type == generictype.Union.String() {
+			// Union alternatives use the interface path default.
+			suffix = generictype.Interface.PathDefault()
+		}
+
+		if len(parentPath) == 0 {
+			return []string{suffix}
+		}
+		parentPath[len(parentPath)-1] += suffix
+		return parentPath
+	}
+
+	return append(parentPath, name)
+}
+
+// typeString returns the generic type with the TypeRef name if set.
+func (r *MarkdownRenderer) typeString(t *types.TypeNode) string {
+	out := t.Type
+	if t.TypeRef != "" {
+		out += " (" + t.TypeRef + ")"
+	}
+	return out
+}
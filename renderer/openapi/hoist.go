@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// hoistAnonymous returns a copy of a schema where repeated anonymous structs are replaced by references.
+// - Only anonymous structs that are rendered more than once are hoisted into TypeRef.
+// - Structures are compared by their rendered properties so that hoisted schemas render the same as inline ones.
+// - Names are built from the element and its ancestors (e.g. "OtherEntityAnonStruct") with a number if taken.
+func (r *OpenAPIRenderer) hoistAnonymous(schema *types.Schema) *types.Schema {
+	out := &types.Schema{
+		Root:    schema.Root.Copy(),
+		TypeRef: schema.TypeRef.Copy(),
+	}
+
+	// Find anonymous structs in render order.
+	candidates := []*types.TypeNode{}
+	var visit func(t *types.TypeNode)
+	visit = func(t *types.TypeNode) {
+		for _, child := range renderer.Children(t, r) {
+			if r.NativeType(child).Include == threeflag.False || renderer.IsErrorExcluded(child, r) || child.TypeRef != "" {
+				// Excluded elements and references are not rendered inline.
+				continue
+			}
+
+			isTopLevel := t.Type == generictype.Root.String()
+			if !isTopLevel && child.Type == generictype.Struct.String() && child.Error == "" && len(child.Children) > 0 {
+				candidates = append(candidates, child)
+			}
+			visit(child)
+		}
+	}
+	visit(out.Root)
+	visit(out.TypeRef)
+
+	// Count structures and name the repeated ones.
+	keys := make([]string, len(candidates))
+	counts := map[string]int{}
+	for i, t := range candidates {
+		keys[i] = r.structureKey(t)
+		counts[keys[i]]++
+	}
+
+	usedNames := map[string]bool{}
+	for _, t := range out.TypeRef.Children {
+		usedNames[t.Name] = true
+	}
+
+	names := map[string]string{}
+	for i, t := range candidates {
+		if counts[keys[i]] < 2 || names[keys[i]] != "" {
+			continue
+		}
+
+		baseName := hoistName(t)
+		name := baseName
+		for n := 2; usedNames[name]; n++ {
+			name = fmt.Sprintf("%s%d", baseName, n)
+		}
+		usedNames[name] = true
+		names[keys[i]] = name
+	}
+
+	// Replace structs in reverse order so that nested structs are replaced before their parents are copied.
+	defined := map[string]bool{}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		name := names[keys[i]]
+		if name == "" {
+			continue
+		}
+		t := candidates[i]
+
+		if !defined[name] {
+			refElem := t.Copy()
+			refElem.Name = name
+			refElem.TypeRef = ""
+			refElem.MetaKey = ""
+			refElem.Description = ""
+			refElem.Nullable = false
+			for _, native := range refElem.Native {
+				native.Name = ""
+				native.TypeRef = ""
+			}
+			out.TypeRef.AddChild(refElem)
+			defined[name] = true
+		}
+
+		t.TypeRef = name
+		t.NativeDefault().TypeRef = name
+		t.RemoveAllChildren()
+	}
+
+	return out
+}
+
+// structureKey returns the rendered properties of an element to compare structures.
+func (r *OpenAPIRenderer) structureKey(t *types.TypeNode) string {
+	opt := *r.Options
+	opt.Indent = 0
+	keyRenderer := &OpenAPIRenderer{Options: &opt}
+
+	lines := []string{t.Type}
+	for _, child := range renderer.Children(t, keyRenderer) {
+		if keyRenderer.NativeType(child).Include == threeflag.False || renderer.IsErrorExcluded(child, keyRenderer) {
+			continue
+		}
+		lines = append(lines, renderer.RenderType(child, keyRenderer)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hoistName builds a name from the names of an element and its ancestors.
+// - Unnamed top-level elements use their MetaKey.
+func hoistName(t *types.TypeNode) string {
+	name := ""
+	for n := t; n != nil && n.Type != generictype.Root.String(); n = n.Parent {
+		part := n.Name
+		if part == "" && n.Parent != nil && n.Parent.Type == generictype.Root.String() {
+			part = n.MetaKey
+		}
+		name = util.ToIdentifier(part) + name
+	}
+
+	if name == "" {
+		return "Anonymous"
+	}
+	return name
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gitmann/b9schema-golang/common/util"
 	"net/mail"
 	"net/url"
+	"sort"
 	"strings"
 )
 
@@ -15,6 +16,9 @@ import (
 
 const OPENAPI_VERSION = "3.0.0"
 
+// OPENAPI_31_VERSION selects OpenAPI 3.1 output, which uses JSON Schema 2020-12 type arrays for nullable elements.
+const OPENAPI_31_VERSION = "3.1.0"
+
 type MetaData struct {
 	// REQUIRED. This string MUST be the semantic version number of the OpenAPI Specification version that
 	// the OpenAPI document uses. The openapi field SHOULD be used by tooling specifications and clients
@@ -30,6 +34,20 @@ type MetaData struct {
 
 	// Additional external documentation.
 	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	// An element to hold various schemas for the specification.
+	// - Schemas are rendered from TypeRef elements, other components are rendered by OpenAPIRenderer.Footer.
+	Components *ComponentsObject `json:"components,omitempty"`
+
+	// A declaration of which security mechanisms can be used across the API. The list of values includes
+	// alternative security requirement objects that can be used. Only one of the security requirement objects
+	// need to be satisfied to authorize a request. Individual operations can override this definition.
+	Security []SecurityRequirementObject `json:"security,omitempty"`
+
+	// A list of tags used by the document with additional metadata. The order of the tags can be used to reflect
+	// on their order by the parsing tools. Not all tags that are used by the Operation Object must be declared.
+	// Each tag name in the list MUST be unique.
+	Tags []*TagObject `json:"tags,omitempty"`
 }
 
 // NewMetaData returns an empty metadata struct with the default version.
@@ -72,7 +90,7 @@ func (m *MetaData) MarshalYAML(prefix string) ([]byte, error) {
 
 	// ExternalDocs
 	if m.ExternalDocs != nil {
-		if b, err := yaml.Marshal(m.ExternalDocs); err != nil {
+		if b, err := m.ExternalDocs.MarshalYAML(); err != nil {
 			return nil, err
 		} else {
 			outLines = append(outLines, `externalDocs:`)
@@ -81,24 +99,70 @@ func (m *MetaData) MarshalYAML(prefix string) ([]byte, error) {
 	}
 
 	// Servers
-	if m.Servers != nil {
-		if b, err := yaml.Marshal(m.Servers); err != nil {
+	if len(m.Servers) > 0 {
+		outLines = append(outLines, `servers:`)
+		for _, srv := range m.Servers {
+			if b, err := srv.MarshalYAML(); err != nil {
+				return nil, err
+			} else {
+				// Servers are list items like tags.
+				item := util.BlockIndent(string(b), nil, []string{"- ", "  "})
+				outLines = util.AppendStrings(outLines, []string{item}, prefix)
+			}
+		}
+	}
+
+	// Security
+	if len(m.Security) > 0 {
+		if b, err := marshalSecurity(m.Security); err != nil {
 			return nil, err
 		} else {
-			outLines = append(outLines, `servers:`)
+			outLines = append(outLines, `security:`)
 			outLines = util.AppendStrings(outLines, []string{string(b)}, prefix)
 		}
 	}
 
+	// Tags
+	if len(m.Tags) > 0 {
+		outLines = append(outLines, `tags:`)
+		for _, tag := range m.Tags {
+			if b, err := tag.MarshalYAML(prefix); err != nil {
+				return nil, err
+			} else {
+				// Tags are list items, following lines are indented to line up with the first key.
+				item := util.BlockIndent(string(b), nil, []string{"- ", "  "})
+				outLines = util.AppendStrings(outLines, []string{item}, prefix)
+			}
+		}
+	}
+
 	outLines = append(outLines, "")
 	finalOut := strings.Join(outLines, "\n")
 
 	return []byte(finalOut), nil
 }
 
+// AddSecurityScheme adds a named security scheme to the components.
+// - Security requirements reference the scheme by name, e.g. SecurityRequirementObject{"bearerAuth": {}}
+func (m *MetaData) AddSecurityScheme(name string, scheme *SecuritySchemeObject) {
+	if m.Components == nil {
+		m.Components = &ComponentsObject{}
+	}
+	if m.Components.SecuritySchemes == nil {
+		m.Components.SecuritySchemes = map[string]*SecuritySchemeObject{}
+	}
+	m.Components.SecuritySchemes[name] = scheme
+}
+
+// Is31 returns true if the metadata is for OpenAPI 3.1.
+// - nil metadata is not OpenAPI 3.1 so that renderers without metadata use 3.0 output.
+func (m *MetaData) Is31() bool {
+	return m != nil && strings.HasPrefix(m.OpenAPI, "3.1")
+}
+
 // Validate checks that metadata contains required fields.
 func (m *MetaData) Validate() error {
-	if !strings.HasPrefix(m.OpenAPI, "3.0") {
+	if !strings.HasPrefix(m.OpenAPI, "3.0") && !m.Is31() {
 		return fmt.Errorf("invalid 'openapi' value %q", m.OpenAPI)
 	}
 
@@ -120,6 +184,39 @@ func (m *MetaData) Validate() error {
 		}
 	}
 
+	if m.Components != nil {
+		if err := m.Components.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.ValidateSecurity(m.Security); err != nil {
+		return err
+	}
+
+	tagNames := map[string]bool{}
+	for _, tag := range m.Tags {
+		if err := tag.Validate(); err != nil {
+			return err
+		}
+		if tagNames[tag.Name] {
+			return fmt.Errorf("duplicate tag name %q", tag.Name)
+		}
+		tagNames[tag.Name] = true
+	}
+
+	return nil
+}
+
+// ValidateSecurity checks that security requirements reference security schemes in the components.
+func (m *MetaData) ValidateSecurity(reqs []SecurityRequirementObject) error {
+	for _, req := range reqs {
+		for name := range req {
+			if m.Components == nil || m.Components.SecuritySchemes[name] == nil {
+				return fmt.Errorf("'security' references unknown security scheme %q", name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -207,7 +304,7 @@ func (i *InfoObject) MarshalYAML(prefix string) ([]byte, error) {
 
 	// Contact
 	if i.Contact != nil {
-		if b, err := yaml.Marshal(i.Contact); err != nil {
+		if b, err := i.Contact.MarshalYAML(); err != nil {
 			return nil, err
 		} else {
 			outLines = append(outLines, `contact:`)
@@ -217,7 +314,7 @@ func (i *InfoObject) MarshalYAML(prefix string) ([]byte, error) {
 
 	// License
 	if i.License != nil {
-		if b, err := yaml.Marshal(i.License); err != nil {
+		if b, err := i.License.MarshalYAML(); err != nil {
 			return nil, err
 		} else {
 			outLines = append(outLines, `license:`)
@@ -254,6 +351,11 @@ func (c *ContactObject) Validate() error {
 	return nil
 }
 
+// MarshalYAML builds YAML strings in the key order of the OpenAPI specification.
+func (c *ContactObject) MarshalYAML() ([]byte, error) {
+	return marshalFields("name", c.Name, "url", c.URL, "email", c.Email)
+}
+
 type LicenseObject struct {
 	// REQUIRED. The license name used for the API.
 	Name string `json:"name"`
@@ -275,6 +377,11 @@ func (lic *LicenseObject) Validate() error {
 	return nil
 }
 
+// MarshalYAML builds YAML strings in the key order of the OpenAPI specification.
+func (lic *LicenseObject) MarshalYAML() ([]byte, error) {
+	return marshalFields("name", lic.Name, "url", lic.URL)
+}
+
 type ServerObject struct {
 	// REQUIRED. A URL to the target host. This URL supports Server Variables and MAY be relative, to indicate that the host location is relative to the location where the OpenAPI document is being served. Variable substitutions will be made when a variable is named in {brackets}.
 	URL string `json:"url"`
@@ -294,6 +401,11 @@ func (s *ServerObject) Validate() error {
 	return nil
 }
 
+// MarshalYAML builds YAML strings in the key order of the OpenAPI specification.
+func (s *ServerObject) MarshalYAML() ([]byte, error) {
+	return marshalFields("url", s.URL, "description", s.Description)
+}
+
 type ExternalDocumentationObject struct {
 	// REQUIRED. The URL for the target documentation. Value MUST be in the format of a URL.
 	URL string `json:"url"`
@@ -309,6 +421,248 @@ func (d *ExternalDocumentationObject) Validate() error {
 	return nil
 }
 
+// MarshalYAML builds YAML strings in the key order of the OpenAPI specification.
+func (d *ExternalDocumentationObject) MarshalYAML() ([]byte, error) {
+	return marshalFields("url", d.URL, "description", d.Description)
+}
+
+// marshalFields builds YAML strings for key-value pairs of string fields in the given order.
+// - Fields with empty values are omitted.
+func marshalFields(keyVals ...string) ([]byte, error) {
+	outLines := []string{}
+	for i := 0; i+1 < len(keyVals); i += 2 {
+		if keyVals[i+1] == "" {
+			continue
+		}
+		if b, err := yaml.Marshal(keyVals[i+1]); err != nil {
+			return nil, err
+		} else {
+			outLines = append(outLines, fmt.Sprintf(`%s: %s`, keyVals[i], strings.TrimSpace(string(b))))
+		}
+	}
+
+	finalOut := strings.Join(outLines, "\n")
+	return []byte(finalOut), nil
+}
+
+type TagObject struct {
+	// REQUIRED. The name of the tag.
+	Name string `json:"name"`
+	// A short description for the tag. CommonMark syntax MAY be used for rich text representation.
+	Description string `json:"description,omitempty"`
+	// Additional external documentation for this tag.
+	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty"`
+}
+
+func (tag *TagObject) Validate() error {
+	if tag == nil {
+		return errors.New("tag is nil")
+	}
+	if tag.Name == "" {
+		return errors.New("'tag.name' is required")
+	}
+
+	if tag.ExternalDocs != nil {
+		if err := tag.ExternalDocs.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tag *TagObject) MarshalYAML(prefix string) ([]byte, error) {
+	outLines := []string{}
+
+	// Name
+	if b, err := yaml.Marshal(tag.Name); err != nil {
+		return nil, err
+	} else {
+		outLines = append(outLines, fmt.Sprintf(`name: %s`, strings.TrimSpace(string(b))))
+	}
+
+	// Description
+	if tag.Description != "" {
+		if b, err := yaml.Marshal(tag.Description); err != nil {
+			return nil, err
+		} else {
+			outLines = append(outLines, fmt.Sprintf(`description: %s`, strings.TrimSpace(string(b))))
+		}
+	}
+
+	// ExternalDocs
+	if tag.ExternalDocs != nil {
+		if b, err := tag.ExternalDocs.MarshalYAML(); err != nil {
+			return nil, err
+		} else {
+			outLines = append(outLines, `externalDocs:`)
+			outLines = util.AppendStrings(outLines, []string{string(b)}, prefix)
+		}
+	}
+
+	finalOut := strings.Join(outLines, "\n")
+	return []byte(finalOut), nil
+}
+
+type ComponentsObject struct {
+	//securitySchemes	Map[string, Security Scheme Object | Reference Object]	An object to hold reusable Security Scheme Objects.
+	SecuritySchemes map[string]*SecuritySchemeObject `json:"securitySchemes,omitempty"`
+
+	// OMITTED FIELDS
+	//schemas	Map[string, Schema Object | Reference Object]	Schemas are rendered from TypeRef elements.
+	//responses, parameters, examples, requestBodies, headers, links, callbacks
+}
+
+func (c *ComponentsObject) Validate() error {
+	for name, scheme := range c.SecuritySchemes {
+		if scheme == nil {
+			return fmt.Errorf("security scheme %q is nil", name)
+		}
+		if err := scheme.Validate(); err != nil {
+			return fmt.Errorf("security scheme %q: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// MarshalYAML builds YAML strings for the components without the "components:" key.
+// - Returns an empty slice if there are no components.
+func (c *ComponentsObject) MarshalYAML(prefix string) ([]byte, error) {
+	outLines := []string{}
+
+	// SecuritySchemes are sorted by name.
+	if len(c.SecuritySchemes) > 0 {
+		names := []string{}
+		for name := range c.SecuritySchemes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		outLines = append(outLines, `securitySchemes:`)
+		for _, name := range names {
+			key, err := yaml.Marshal(name)
+			if err != nil {
+				return nil, err
+			}
+			b, err := c.SecuritySchemes[name].MarshalYAML()
+			if err != nil {
+				return nil, err
+			}
+			outLines = append(outLines, fmt.Sprintf(`%s%s:`, prefix, strings.TrimSpace(string(key))))
+			outLines = util.AppendStrings(outLines, []string{string(b)}, prefix+prefix)
+		}
+	}
+
+	finalOut := strings.Join(outLines, "\n")
+	return []byte(finalOut), nil
+}
+
+// Security scheme types.
+const (
+	SECURITY_API_KEY        = "apiKey"
+	SECURITY_HTTP           = "http"
+	SECURITY_OAUTH2         = "oauth2"
+	SECURITY_OPENID_CONNECT = "openIdConnect"
+)
+
+type SecuritySchemeObject struct {
+	//type	string	REQUIRED. The type of the security scheme. Valid values are "apiKey", "http", "oauth2", "openIdConnect".
+	Type string `json:"type"`
+	//description	string	A short description for security scheme. CommonMark syntax MAY be used for rich text representation.
+	Description string `json:"description,omitempty"`
+	//name	string	apiKey	REQUIRED. The name of the header, query or cookie parameter to be used.
+	Name string `json:"name,omitempty"`
+	//in	string	apiKey	REQUIRED. The location of the API key. Valid values are "query", "header" or "cookie".
+	In string `json:"in,omitempty"`
+	//scheme	string	http	REQUIRED. The name of the HTTP Authorization scheme to be used in the Authorization header as defined in RFC7235.
+	Scheme string `json:"scheme,omitempty"`
+	//bearerFormat	string	http ("bearer")	A hint to the client to identify how the bearer token is formatted.
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	//openIdConnectUrl	string	openIdConnect	REQUIRED. OpenId Connect URL to discover OAuth2 configuration values. This MUST be in the form of a URL.
+	OpenIdConnectUrl string `json:"openIdConnectUrl,omitempty"`
+
+	// OMITTED FIELDS
+	//flows	OAuth Flows Object	oauth2	REQUIRED. An object containing configuration information for the flow types supported.
+}
+
+// NewBearerAuth returns an HTTP bearer security scheme.
+// - bearerFormat is a hint for clients, e.g. "JWT"; it may be empty.
+// - The scheme is not named here: schemes are named by their key in Components.SecuritySchemes, see MetaData.AddSecurityScheme.
+func NewBearerAuth(bearerFormat string) *SecuritySchemeObject {
+	return &SecuritySchemeObject{
+		Type:         SECURITY_HTTP,
+		Scheme:       "bearer",
+		BearerFormat: bearerFormat,
+	}
+}
+
+// NewApiKeyAuth returns an API key security scheme.
+// - name is the name of the header, query or cookie parameter, e.g. "X-API-Key"
+// - in is the location of the API key: "query", "header" or "cookie"
+// - There is no separate header argument, a header key is name with in set to "header".
+// - The scheme is not named here, see NewBearerAuth.
+func NewApiKeyAuth(name, in string) *SecuritySchemeObject {
+	return &SecuritySchemeObject{
+		Type: SECURITY_API_KEY,
+		Name: name,
+		In:   in,
+	}
+}
+
+func (s *SecuritySchemeObject) Validate() error {
+	switch s.Type {
+	case SECURITY_API_KEY:
+		if s.Name == "" {
+			return errors.New("'name' is required")
+		}
+		switch s.In {
+		case "query", "header", "cookie":
+		default:
+			return fmt.Errorf("invalid 'in' value %q", s.In)
+		}
+	case SECURITY_HTTP:
+		if s.Scheme == "" {
+			return errors.New("'scheme' is required")
+		}
+	case SECURITY_OPENID_CONNECT:
+		if _, err := url.ParseRequestURI(s.OpenIdConnectUrl); err != nil {
+			return errors.New("'openIdConnectUrl' is not a valid URL")
+		}
+	case SECURITY_OAUTH2:
+		return errors.New("'flows' are not supported")
+	default:
+		return fmt.Errorf("invalid 'type' value %q", s.Type)
+	}
+	return nil
+}
+
+// MarshalYAML builds YAML strings in the key order of the OpenAPI specification.
+func (s *SecuritySchemeObject) MarshalYAML() ([]byte, error) {
+	return marshalFields("type", s.Type, "description", s.Description, "name", s.Name, "in", s.In,
+		"scheme", s.Scheme, "bearerFormat", s.BearerFormat, "openIdConnectUrl", s.OpenIdConnectUrl)
+}
+
+// SecurityRequirementObject maps security scheme names to required scopes.
+// - Scopes are only used by "oauth2" and "openIdConnect" schemes, other schemes have an empty list.
+type SecurityRequirementObject map[string][]string
+
+// marshalSecurity returns security requirements as a YAML list.
+// - Missing scopes are empty lists because null is not a valid list of scopes.
+func marshalSecurity(reqs []SecurityRequirementObject) ([]byte, error) {
+	out := []SecurityRequirementObject{}
+	for _, req := range reqs {
+		r := SecurityRequirementObject{}
+		for name, scopes := range req {
+			if scopes == nil {
+				scopes = []string{}
+			}
+			r[name] = scopes
+		}
+		out = append(out, r)
+	}
+	return yaml.Marshal(out)
+}
+
 type PathsObject map[string]*PathItemObject
 
 type PathItemObject struct {
@@ -361,8 +715,10 @@ type OperationObject struct {
 	//servers	[Server Object]	An alternative server array to service this operation. If an alternative server object is specified at the Path Item Object or Root level, it will be overridden by this value.
 	Servers []*ServerObject `json:"servers,omitempty"`
 
-	// OMITTED FIELDS
 	//security	[Security Requirement Object]	A declaration of which security mechanisms can be used for this operation. The list of values includes alternative security requirement objects that can be used. Only one of the security requirement objects need to be satisfied to authorize a request. To make security optional, an empty security requirement ({}) can be included in the array. This definition overrides any declared top-level security. To remove a top-level security declaration, an empty array can be used.
+	Security []SecurityRequirementObject `json:"security,omitempty"`
+
+	// OMITTED FIELDS
 	//requestBody	Request Body Object | Reference Object	The request body applicable for this operation. The requestBody is only supported in HTTP methods where the HTTP 1.1 specification RFC7231 has explicitly defined semantics for request bodies. In other cases where the HTTP spec is vague, requestBody SHALL be ignored by consumers.
 	//callbacks	Map[string, Callback Object | Reference Object]	A map of possible out-of band callbacks related to the parent operation. The key is a unique identifier for the Callback Object. Each value in the map is a Callback Object that describes a request that may be initiated by the API provider and the expected responses.
 }
@@ -391,6 +747,7 @@ type ParameterObject struct {
 // SimpleSchemaObject is a lightweight representation of the SchemaObject.
 type SimpleSchemaObject struct {
 	Type      string `json:"type,omitempty"`
+	Format    string `json:"format,omitempty"`
 	Reference string `json:"$ref,omitempty"`
 }
 
@@ -65,20 +65,56 @@ func TestNewMetaData(t *testing.T) {
 				`  description: This is a description.`,
 				`  termsOfService: https://test.tos.site.com/terms`,
 				`  contact:`,
-				`    email: support@site.com`,
 				`    name: Support Team`,
 				`    url: https://support.site.com/`,
+				`    email: support@site.com`,
 				`  license:`,
 				`    name: This is the license.`,
 				`    url: https://license.site.com/`,
 				`externalDocs:`,
-				`  description: This is the test doc site.`,
 				`  url: https://test.doc.site.com/path/to/docs`,
+				`  description: This is the test doc site.`,
 				`servers:`,
-				`  - description: Production server.`,
-				`    url: https://www.site.com`,
-				`  - description: Development server.`,
-				`    url: https://www.dev.site.com`,
+				`  - url: https://www.site.com`,
+				`    description: Production server.`,
+				`  - url: https://www.dev.site.com`,
+				`    description: Development server.`,
+			},
+		},
+		{
+			name: "tags",
+			meta: &MetaData{
+				OpenAPI: OPENAPI_VERSION,
+				Info: &InfoObject{
+					Title:   "Tags",
+					Version: "v1.0.0",
+				},
+				Tags: []*TagObject{
+					{
+						Name:        "users",
+						Description: "Operations on users.",
+						ExternalDocs: &ExternalDocumentationObject{
+							URL:         "https://test.doc.site.com/users",
+							Description: "User docs.",
+						},
+					},
+					{
+						Name: "health",
+					},
+				},
+			},
+			wantYAML: []string{
+				`openapi: 3.0.0`,
+				`info:`,
+				`  title: Tags`,
+				`  version: v1.0.0`,
+				`tags:`,
+				`  - name: users`,
+				`    description: Operations on users.`,
+				`    externalDocs:`,
+				`      url: https://test.doc.site.com/users`,
+				`      description: User docs.`,
+				`  - name: health`,
 			},
 		},
 	}
@@ -94,3 +130,80 @@ func TestNewMetaData(t *testing.T) {
 		}
 	}
 }
+
+// TestMetaData_Validate validates the supported OpenAPI versions.
+func TestMetaData_Validate(t *testing.T) {
+	testCases := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: OPENAPI_VERSION},
+		{version: "3.0.3"},
+		{version: OPENAPI_31_VERSION},
+		{version: "2.0", wantErr: true},
+		{version: "3.2.0", wantErr: true},
+	}
+
+	for _, test := range testCases {
+		meta := NewMetaData("", "")
+		meta.OpenAPI = test.version
+
+		if err := meta.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("TEST_FAIL %s: err=%v wantErr=%t", test.version, err, test.wantErr)
+		}
+	}
+}
+
+// TestMetaData_ValidateTags validates that tags have unique names.
+func TestMetaData_ValidateTags(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tags    []*TagObject
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "valid", tags: []*TagObject{{Name: "users"}, {Name: "health", Description: "Health checks."}}},
+		{name: "missing-name", tags: []*TagObject{{Description: "No name."}}, wantErr: true},
+		{name: "duplicate", tags: []*TagObject{{Name: "users"}, {Name: "users"}}, wantErr: true},
+		{name: "docs-url", tags: []*TagObject{{Name: "users", ExternalDocs: &ExternalDocumentationObject{URL: "not a url"}}}, wantErr: true},
+	}
+
+	for _, test := range testCases {
+		meta := NewMetaData("", "")
+		meta.Tags = test.tags
+
+		if err := meta.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("TEST_FAIL %s: err=%v wantErr=%t", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestMetaData_ValidateSecurity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scheme   *SecuritySchemeObject
+		security []SecurityRequirementObject
+		wantErr  bool
+	}{
+		{name: "bearer", scheme: NewBearerAuth("JWT"), security: []SecurityRequirementObject{{"auth": nil}}},
+		{name: "api-key", scheme: NewApiKeyAuth("X-API-Key", "header")},
+		{name: "api-key-name", scheme: NewApiKeyAuth("", "header"), wantErr: true},
+		{name: "api-key-in", scheme: NewApiKeyAuth("X-API-Key", "body"), wantErr: true},
+		{name: "http-scheme", scheme: &SecuritySchemeObject{Type: SECURITY_HTTP}, wantErr: true},
+		{name: "openid", scheme: &SecuritySchemeObject{Type: SECURITY_OPENID_CONNECT, OpenIdConnectUrl: "https://example.com/.well-known"}},
+		{name: "openid-url", scheme: &SecuritySchemeObject{Type: SECURITY_OPENID_CONNECT}, wantErr: true},
+		{name: "oauth2", scheme: &SecuritySchemeObject{Type: SECURITY_OAUTH2}, wantErr: true},
+		{name: "type", scheme: &SecuritySchemeObject{Type: "basic"}, wantErr: true},
+		{name: "unknown", scheme: NewBearerAuth(""), security: []SecurityRequirementObject{{"other": nil}}, wantErr: true},
+	}
+
+	for _, test := range testCases {
+		meta := NewMetaData("", "")
+		meta.AddSecurityScheme("auth", test.scheme)
+		meta.Security = test.security
+
+		if err := meta.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("TEST_FAIL %s: err=%v wantErr=%t", test.name, err, test.wantErr)
+		}
+	}
+}
@@ -8,16 +8,132 @@ import (
 	"github.com/gitmann/b9schema-golang/common/types"
 	"github.com/gitmann/b9schema-golang/common/util"
 	"github.com/gitmann/b9schema-golang/renderer"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Default location for schema references without leading or training "/".
 const SCHEMA_PATH = "components/schemas"
 
+// MAIN_FILE and SCHEMA_FILE_DIR are the file names of multi-file output, see ProcessSchemaMultiFile.
+const (
+	MAIN_FILE       = "openapi.yaml"
+	SCHEMA_FILE_DIR = "schemas"
+)
+
+// PathInfo holds optional details for an OpenAPI operation.
+type PathInfo struct {
+	OperationId string
+	Summary     string
+	Description string
+	Tags        []string
+
+	// Security overrides the top-level security requirements of MetaData.
+	// - nil uses the top-level requirements, an empty list removes them.
+	Security []SecurityRequirementObject
+
+	// Responses holds responses by status code, e.g. "200", "404", "4XX" or "default".
+	// - nil renders a single "200" response with the derived schema.
+	// - Operations without a request body render the derived schema in the first 2XX response without SchemaRef.
+	//   If there is none, a "200" response is used and it must not have a SchemaRef.
+	Responses map[string]ResponseSpec
+}
+
+// ResponseSpec describes an operation response in PathInfo.
+type ResponseSpec struct {
+	// Description is required.
+	Description string
+
+	// SchemaRef is the name of a component schema for the response body, e.g. "Error"
+	// - Options.NamePrefix and Options.NameSuffix are added like for generated schema names.
+	// - If empty, the response has no body unless it has the derived schema.
+	SchemaRef string
+}
+
+// statusCodeRegexp matches the response keys allowed by OpenAPI.
+var statusCodeRegexp = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+
+// validateResponses returns an error if a response has an invalid status code or no description.
+func (info PathInfo) validateResponses() error {
+	for _, code := range info.responseCodes() {
+		if !statusCodeRegexp.MatchString(code) {
+			return fmt.Errorf("invalid response status code %q", code)
+		}
+		if info.Responses[code].Description == "" {
+			return fmt.Errorf("response %q is missing description", code)
+		}
+	}
+	return nil
+}
+
+// responseCodes returns the status codes of Responses in order, "default" is last.
+func (info PathInfo) responseCodes() []string {
+	codes := make([]string, 0, len(info.Responses))
+	for code := range info.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// validateBody returns an error if an operation without a request body has no response for the derived schema.
+// - The fallback "200" response must not have a SchemaRef because the derived schema replaces it.
+func (info PathInfo) validateBody(method string) error {
+	if hasRequestBody(method) {
+		return nil
+	}
+	if code := info.bodyCode(); info.Responses[code].SchemaRef != "" {
+		return fmt.Errorf("response %q has SchemaRef but is needed for the derived schema", code)
+	}
+	return nil
+}
+
+// bodyCode returns the status code of the response with the derived schema.
+func (info PathInfo) bodyCode() string {
+	for _, code := range info.responseCodes() {
+		if strings.HasPrefix(code, "2") && info.Responses[code].SchemaRef == "" {
+			return code
+		}
+	}
+	return "200"
+}
+
 // OpenAPIRenderer provides a simple string renderer.
 type OpenAPIRenderer struct {
 	MetaData *MetaData
 	Options  *renderer.Options
+
+	// PathOptions holds operation details by URL path.
+	// - Keys may be a path ("/users") or a path with method ("/users post"), see types.OperationKey.
+	// - A path with method takes precedence over a path.
+	PathOptions map[string]PathInfo
+
+	// HoistAnonymous replaces repeated anonymous structs with references to new component schemas.
+	// - Ignored if de-referencing.
+	HoistAnonymous bool
+
+	// AlwaysEmitComponents renders the components/schemas block from TypeRef elements even if de-referencing.
+	// - Paths still have inline schemas, the components are reusable definitions for other tools.
+	AlwaysEmitComponents bool
+
+	// parameters holds operation parameters by path, see AddParameters.
+	parameters map[string][]*ParameterObject
+
+	// pathStarts holds the first root element of each path, see groupOperations.
+	pathStarts map[*types.TypeNode]bool
+
+	// marshalErr is an error from marshaling metadata (e.g. in Header), returned by ProcessSchema.
+	marshalErr error
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+
+	// schemaDir is the directory of schema files relative to the file being rendered, see ProcessSchemaMultiFile.
+	// - References point to SCHEMA_PATH in the same document if empty.
+	schemaDir string
 }
 
 func NewOpenAPIRenderer(metadata *MetaData, opt *renderer.Options) *OpenAPIRenderer {
@@ -25,41 +141,190 @@ func NewOpenAPIRenderer(metadata *MetaData, opt *renderer.Options) *OpenAPIRende
 		opt = renderer.NewOptions()
 	}
 
-	opt.Prefix = "  "
-
+	// YAML is indented with two spaces unless the caller set a prefix.
 	return &OpenAPIRenderer{
-		MetaData: metadata,
-		Options:  opt,
+		MetaData:      metadata,
+		Options:       opt,
+		PathOptions:   map[string]PathInfo{},
+		defaultPrefix: "  ",
 	}
 }
 
+// ProcessSchema renders a schema as an OpenAPI document.
+// - settings change options for this call only, see renderer.Options.WithSettings.
 func (r *OpenAPIRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
 	out := []string{}
 
+	if len(settings) > 0 {
+		opt, err := r.Options.WithSettings(settings...)
+		if err != nil {
+			return out, err
+		}
+
+		// Render with a copy so that the renderer keeps its options.
+		c := *r
+		c.Options = opt
+		return c.ProcessSchema(schema)
+	}
+
+	schema, err := r.prepareSchema(schema)
+	if err != nil {
+		return out, err
+	}
+
+	r.marshalErr = nil
+	out = renderer.RenderSchema(schema, r)
+	if r.marshalErr != nil {
+		return []string{}, r.marshalErr
+	}
+
+	return out, nil
+}
+
+// ProcessSchemaMultiFile renders a schema as a main document and one file per component schema.
+// - Keys are file names, MAIN_FILE and "schemas/<Name>.yaml"
+// - Each schema file has a single key with the schema name, the main file references "./schemas/Name.yaml#/Name"
+// - Schema files reference each other in the same directory, e.g. "./Address.yaml#/Address"
+// - If de-referencing, schemas are inline so only the main file is returned.
+func (r *OpenAPIRenderer) ProcessSchemaMultiFile(schema *types.Schema) (map[string][]string, error) {
+	files := map[string][]string{}
+
+	if r.DeReference() {
+		out, err := r.ProcessSchema(schema)
+		if err != nil {
+			return nil, err
+		}
+		files[MAIN_FILE] = out
+		return files, nil
+	}
+
+	schema, err := r.prepareSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { r.schemaDir = "" }()
+
+	// The main file has paths and other components but no schemas.
+	r.schemaDir = "./" + SCHEMA_FILE_DIR + "/"
+	mainSchema := &types.Schema{
+		Root:    schema.Root,
+		TypeRef: types.NewRootNode(types.TYPEREF_NAME, schema.TypeRef.NativeDialect),
+	}
+
+	r.marshalErr = nil
+	files[MAIN_FILE] = renderer.RenderSchema(mainSchema, r)
+	if r.marshalErr != nil {
+		return nil, r.marshalErr
+	}
+
+	r.schemaDir = "./"
+	for _, ref := range schema.TypeRef.Children {
+		native := r.NativeType(ref)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(ref, r) {
+			continue
+		}
+		files[path.Join(SCHEMA_FILE_DIR, native.Name+".yaml")] = renderer.RenderType(ref, r)
+	}
+
+	return files, nil
+}
+
+// prepareSchema validates the renderer configuration and returns the schema to render.
+// - Anonymous structs are hoisted into a copy of the schema if HoistAnonymous is set.
+func (r *OpenAPIRenderer) prepareSchema(schema *types.Schema) (*types.Schema, error) {
 	if r.MetaData == nil {
-		return out, errors.New("missing metadata")
+		return nil, errors.New("missing metadata")
 	} else if err := r.MetaData.Validate(); err != nil {
-		return out, err
+		return nil, err
 	}
 
-	// Header
-	if b, err := r.MetaData.MarshalYAML(r.Options.Prefix); err != nil {
-		return out, err
-	} else {
-		out = append(out, string(b))
+	for key, info := range r.PathOptions {
+		if err := r.MetaData.ValidateSecurity(info.Security); err != nil {
+			return nil, err
+		}
+		if err := info.validateResponses(); err != nil {
+			return nil, fmt.Errorf("path %q: %s", key, err)
+		}
 	}
 
-	out = util.AppendStrings(out, renderer.RenderSchema(schema, r), "")
+	if r.HoistAnonymous && !r.DeReference() {
+		schema = r.hoistAnonymous(schema)
+	}
 
-	// Footer
+	return r.groupOperations(schema)
+}
 
-	return out, nil
+// groupOperations returns a copy of the schema with copies of root elements grouped by path.
+// - MetaKeys are normalized to "<path> <method>", e.g. "users" and "/users get" are both "/users get"
+// - Operations on a path are sorted by method and the first one is added to pathStarts.
+// - Returns an error if two root elements are the same operation or if PathInfo has no response for the schema.
+func (r *OpenAPIRenderer) groupOperations(schema *types.Schema) (*types.Schema, error) {
+	paths := []string{}
+	pathOps := map[string][]*types.TypeNode{}
+	seen := map[string]bool{}
+	for _, t := range renderer.Children(schema.Root, r) {
+		if r.NativeType(t).Include == threeflag.False || renderer.IsErrorExcluded(t, r) {
+			continue
+		}
+
+		urlPath, method := r.operation(t)
+		key := types.OperationKey(urlPath, method)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate operation %q", key)
+		}
+		seen[key] = true
+		if err := r.pathOptions(urlPath, method).validateBody(method); err != nil {
+			return nil, fmt.Errorf("operation %q: %s", key, err)
+		}
+
+		if pathOps[urlPath] == nil {
+			paths = append(paths, urlPath)
+		}
+		// Shallow copies share children with the schema, deep copies would not end on cyclical graphs.
+		op := *t
+		op.Parent = nil
+		op.MetaKey = key
+		pathOps[urlPath] = append(pathOps[urlPath], &op)
+	}
+
+	// Render order follows the grouped order with and without PreserveOrder.
+	root := types.NewRootNode(types.ROOT_NAME, schema.Root.NativeDialect)
+	r.pathStarts = map[*types.TypeNode]bool{}
+	for _, urlPath := range paths {
+		ops := pathOps[urlPath]
+		sort.SliceStable(ops, func(i, j int) bool { return ops[i].MetaKey < ops[j].MetaKey })
+
+		r.pathStarts[ops[0]] = true
+		for _, op := range ops {
+			op.Order = len(root.Children) + 1
+			root.AddChild(op)
+		}
+	}
+
+	return &types.Schema{Root: root, TypeRef: schema.TypeRef}, nil
+}
+
+// schemaRef returns the reference to a component schema by name.
+// - e.g. "#/components/schemas/Name" or "./schemas/Name.yaml#/Name" for multi-file output.
+func (r *OpenAPIRenderer) schemaRef(name string) string {
+	if r.schemaDir == "" {
+		return "#/" + SCHEMA_PATH + "/" + name
+	}
+	return r.schemaDir + name + ".yaml#/" + name
 }
 
 func (r *OpenAPIRenderer) DeReference() bool {
 	return r.Options.DeReference
 }
 
+func (r *OpenAPIRenderer) PreserveOrder() bool {
+	return r.Options.PreserveOrder
+}
+
+func (r *OpenAPIRenderer) ExcludeErrored() bool {
+	return r.Options.ExcludeErrored
+}
+
 func (r *OpenAPIRenderer) Indent() int {
 	return r.Options.Indent
 }
@@ -69,14 +334,99 @@ func (r *OpenAPIRenderer) SetIndent(value int) {
 }
 
 func (r *OpenAPIRenderer) Prefix() string {
-	if r.Options.Prefix == "" {
-		return ""
+	return strings.Repeat(r.indentPrefix(), r.Options.Indent)
+}
+
+// indentPrefix returns the prefix of one indent level, see renderer.Options.IndentPrefix.
+func (r *OpenAPIRenderer) indentPrefix() string {
+	return r.Options.IndentPrefix(r.defaultPrefix)
+}
+
+// itemPrefix returns the prefix of the first line of a list item at an indent level.
+// - The "- " marker replaces the end of the prefix so the item content aligns with its following lines.
+func (r *OpenAPIRenderer) itemPrefix(indent int) string {
+	prefix := strings.Repeat(r.indentPrefix(), indent)
+	if len(prefix) < 2 {
+		return prefix + "- "
 	}
-	return strings.Repeat(r.Options.Prefix, r.Options.Indent)
+	return prefix[:len(prefix)-2] + "- "
 }
 
+// NativeType resolves names and types from Options.Dialects in order, then json, then the generic element.
+// - e.g. Dialects ["bigquery"] uses bigquery column names and falls back to json names for untagged fields.
 func (r *OpenAPIRenderer) NativeType(t *types.TypeNode) *types.NativeType {
-	return t.GetNativeType("json")
+	jsonType := r.Options.NativeType(t, "json")
+
+	// Map values that cannot be resolved are skipped, the map allows any additionalProperties.
+	if t.Parent != nil && t.Parent.Type == generictype.Map.String() && mapValue(t.Parent) == nil {
+		jsonType.Include = threeflag.False
+	}
+
+	// Inline maps are rendered as additionalProperties of their parent struct.
+	if t.Parent != nil && renderer.InlineField(t.Parent) == t {
+		jsonType.Include = threeflag.False
+	}
+
+	// Component schema names and references have the same NamePrefix and NameSuffix.
+	jsonType.TypeRef = r.Options.SchemaName(jsonType.TypeRef)
+	if t.Parent != nil && t.Parent.Parent == nil && t.Parent.Name == types.TYPEREF_NAME {
+		jsonType.Name = r.Options.SchemaName(jsonType.Name)
+	}
+
+	return jsonType
+}
+
+// Header returns the OpenAPI metadata as a single YAML string.
+// - Marshal errors are returned by ProcessSchema.
+func (r *OpenAPIRenderer) Header(schema *types.Schema) []string {
+	b, err := r.MetaData.MarshalYAML(r.indentPrefix())
+	if err != nil {
+		r.marshalErr = err
+		return []string{}
+	}
+	return []string{string(b)}
+}
+
+// Footer returns components that are not rendered by RenderSchema.
+// - Schemas are rendered from TypeRef elements with references if de-referencing with AlwaysEmitComponents.
+// - Other components (e.g. security schemes) are added to the "components" key of the schemas if it was rendered.
+func (r *OpenAPIRenderer) Footer(schema *types.Schema) []string {
+	out := []string{}
+	if r.DeReference() && r.AlwaysEmitComponents && len(schema.TypeRef.Children) > 0 {
+		// Components reference each other like without de-referencing.
+		// - A copy of the renderer and options is used so that shared Options are not changed.
+		c := *r
+		opt := *r.Options
+		opt.DeReference = false
+		c.Options = &opt
+		out = append(out, renderer.RenderType(schema.TypeRef, &c)...)
+		if c.marshalErr != nil {
+			r.marshalErr = c.marshalErr
+		}
+	}
+
+	if r.MetaData.Components == nil {
+		return out
+	}
+
+	b, err := r.MetaData.Components.MarshalYAML(r.indentPrefix())
+	if err != nil {
+		r.marshalErr = err
+		return []string{}
+	}
+	if len(b) == 0 {
+		return out
+	}
+
+	if !r.hasSchemas(schema) {
+		out = append(out, "components:")
+	}
+	return util.AppendStrings(out, []string{string(b)}, r.indentPrefix())
+}
+
+// hasSchemas returns true if the components/schemas block is rendered.
+func (r *OpenAPIRenderer) hasSchemas(schema *types.Schema) bool {
+	return len(schema.TypeRef.Children) > 0 && (!r.DeReference() || r.AlwaysEmitComponents)
 }
 
 func (r *OpenAPIRenderer) Pre(t *types.TypeNode) []string {
@@ -108,33 +458,54 @@ func (r *OpenAPIRenderer) Pre(t *types.TypeNode) []string {
 
 	nativeType := t.NativeDefault()
 
+	// startIndent is the indent of the first line.
+	startIndent := r.Indent()
+
 	out := []string{}
 
 	// Start PathItem block if current element parent is Root.
 	if t.Parent.Name == types.ROOT_NAME {
-		urlPath := "/unknown/path"
-		if t.MetaKey != "" {
-			urlPath = t.MetaKey
-		}
+		urlPath, method := r.operation(t)
 
-		// Path must start with "/"
-		if !strings.HasPrefix(urlPath, "/") {
-			urlPath = "/" + urlPath
+		// Operations on the same path share a PathItem that is started by the first operation, see groupOperations.
+		if r.pathStarts[t] {
+			out = append(out, r.Prefix()+urlPath+":")
 		}
-		out = append(out, r.Prefix()+urlPath+":")
 
 		r.SetIndent(r.Indent() + 1)
-		out = append(out, r.Prefix()+`get:`)
+		out = append(out, r.Prefix()+method+":")
 
 		r.SetIndent(r.Indent() + 1)
-		out = append(out, r.Prefix()+`summary: Return data.`)
-		out = append(out, r.Prefix()+`responses:`)
+		out = append(out, r.pathInfo(urlPath, method)...)
+		if hasRequestBody(method) {
+			// Request schema is in the requestBody, responses are added in Post.
+			out = append(out, r.Prefix()+`requestBody:`)
 
-		r.SetIndent(r.Indent() + 1)
-		out = append(out, r.Prefix()+`'200':`)
+			r.SetIndent(r.Indent() + 1)
+			out = append(out, r.Prefix()+`required: true`)
+		} else {
+			out = append(out, r.Prefix()+`responses:`)
 
-		r.SetIndent(r.Indent() + 1)
-		out = append(out, r.Prefix()+`description: Success`)
+			// Responses before the response with the schema, the others are added in Post.
+			info := r.pathOptions(urlPath, method)
+			bodyCode := info.bodyCode()
+
+			r.SetIndent(r.Indent() + 1)
+			for _, code := range info.responseCodes() {
+				if code >= bodyCode {
+					break
+				}
+				out = append(out, r.response(code, info.Responses[code])...)
+			}
+			out = append(out, r.Prefix()+quote(bodyCode)+`:`)
+
+			description := "Success"
+			if spec, ok := info.Responses[bodyCode]; ok {
+				description = quote(spec.Description)
+			}
+			r.SetIndent(r.Indent() + 1)
+			out = append(out, r.Prefix()+`description: `+description)
+		}
 		out = append(out, r.Prefix()+`content:`)
 
 		r.SetIndent(r.Indent() + 1)
@@ -144,10 +515,6 @@ func (r *OpenAPIRenderer) Pre(t *types.TypeNode) []string {
 		out = append(out, r.Prefix()+`schema:`)
 
 		r.SetIndent(r.Indent() + 1)
-	} else if t.Parent.Type == generictype.Map.String() {
-		// Map child only exists when map has no known keys. In order to build a valid OpenAPI
-		// schema, make a fake property with the name "unknownKey".
-		jsonType.Name = "valueType"
 	}
 
 	if jsonType.Name != "" {
@@ -155,17 +522,59 @@ func (r *OpenAPIRenderer) Pre(t *types.TypeNode) []string {
 		r.SetIndent(r.Indent() + 1)
 	}
 
-	if !r.Options.DeReference && jsonType.TypeRef != "" {
-		out = append(out, fmt.Sprintf(`%s$ref: '#/%s/%s'`, r.Prefix(), SCHEMA_PATH, jsonType.TypeRef))
+	// keyLine is the number of lines up to the key of the current schema.
+	keyLine := len(out)
+
+	if !r.Options.DeReference && jsonType.TypeRef != "" && r.MetaData.Is31() {
+		// OpenAPI 3.1 allows keys next to $ref, null is added with anyOf.
+		if t.Description != "" {
+			out = append(out, r.Prefix()+"description: "+quote(t.Description))
+		}
+		if r.Options.IsDeprecated(t) {
+			out = append(out, r.Prefix()+"deprecated: true")
+		}
+		out = append(out, r.extensions(t)...)
+		if t.Nullable {
+			out = append(out,
+				r.Prefix()+"anyOf:",
+				fmt.Sprintf(`%s- $ref: '%s'`, r.Prefix(), r.schemaRef(jsonType.TypeRef)),
+				r.Prefix()+"- type: 'null'",
+			)
+		} else {
+			out = append(out, fmt.Sprintf(`%s$ref: '%s'`, r.Prefix(), r.schemaRef(jsonType.TypeRef)))
+		}
+	} else if !r.Options.DeReference && jsonType.TypeRef != "" {
+		if extensions := r.extensions(t); t.Nullable || t.Description != "" || r.Options.IsDeprecated(t) || len(extensions) > 0 {
+			// OpenAPI 3.0 ignores keys next to $ref so wrap the reference in allOf.
+			if t.Description != "" {
+				out = append(out, r.Prefix()+"description: "+quote(t.Description))
+			}
+			if t.Nullable {
+				out = append(out, r.Prefix()+"nullable: true")
+			}
+			if r.Options.IsDeprecated(t) {
+				out = append(out, r.Prefix()+"deprecated: true")
+			}
+			out = append(out, extensions...)
+			out = append(out,
+				r.Prefix()+"allOf:",
+				fmt.Sprintf(`%s- $ref: '%s'`, r.Prefix(), r.schemaRef(jsonType.TypeRef)),
+			)
+		} else {
+			out = append(out, fmt.Sprintf(`%s$ref: '%s'`, r.Prefix(), r.schemaRef(jsonType.TypeRef)))
+		}
 	} else {
 		// Build description field.
 		descriptionTokens := []string{}
+		if t.Description != "" {
+			descriptionTokens = append(descriptionTokens, t.Description)
+		}
 		if r.Options.DeReference && jsonType.TypeRef != "" {
 			descriptionTokens = append(descriptionTokens, fmt.Sprintf(`From $ref: #/%s/%s`, SCHEMA_PATH, jsonType.TypeRef))
 		}
 		if t.Error != "" {
 			descriptionTokens = append(descriptionTokens, fmt.Sprintf("ERROR=%s", t.Error))
-			if strings.HasPrefix(t.Type, generictype.Invalid.String()) {
+			if t.IsInvalid() {
 				if t.Type != generictype.Invalid.String() {
 					// Add specific type error to description.
 					descriptionTokens = append(descriptionTokens, fmt.Sprintf("Kind=%s", t.Type))
@@ -173,85 +582,588 @@ func (r *OpenAPIRenderer) Pre(t *types.TypeNode) []string {
 			}
 		}
 		if len(descriptionTokens) > 0 {
-			out = append(out, fmt.Sprintf("%sdescription: '%s'", r.Prefix(), strings.Join(descriptionTokens, ";")))
+			out = append(out, r.Prefix()+"description: "+quote(strings.Join(descriptionTokens, ";")))
+		}
+
+		if t.Nullable && !r.MetaData.Is31() {
+			out = append(out, r.Prefix()+"nullable: true")
+		}
+		if r.Options.IsDeprecated(t) {
+			out = append(out, r.Prefix()+"deprecated: true")
 		}
 
 		switch t.Type {
 		case generictype.Struct.String():
-			out = append(out,
-				r.Prefix()+"type: object",
-				r.Prefix()+"additionalProperties: false",
-			)
-			if len(t.Children) > 0 {
+			out = append(out, r.typeLine(t, "object"))
+			// Count rendered properties, inline maps are not included.
+			properties := 0
+			for _, child := range t.Children {
+				if r.NativeType(child).Include != threeflag.False && !renderer.IsErrorExcluded(child, r) {
+					properties++
+				}
+			}
+			if inline := renderer.InlineField(t); inline != nil {
+				out = append(out, r.inlineProperties(inline)...)
+			} else {
+				out = append(out, r.Prefix()+"additionalProperties: false")
+			}
+			if properties > 0 {
 				out = append(out, r.Prefix()+"properties:")
 			}
 			r.SetIndent(r.Indent() + 1)
 		case generictype.Map.String():
 			out = append(out,
-				r.Prefix()+"type: object",
+				r.typeLine(t, "object"),
 			)
-			if len(t.Children) > 0 {
+			out = append(out, r.constraints(t)...)
+			if v, pattern := mapValue(t), r.keyPattern(t); pattern != "" {
+				// Keys must match the pattern, the value type is rendered by the child element.
 				out = append(out,
-					r.Prefix()+"additionalProperties: true",
-					r.Prefix()+"properties:",
+					r.Prefix()+"additionalProperties: false",
+					r.Prefix()+"patternProperties:",
 				)
+				r.SetIndent(r.Indent() + 1)
+				out = append(out, r.Prefix()+quote(pattern)+":"+util.ValueIfTrue(v == nil || r.isEmptySchema(v), " {}", ""))
+			} else if v != nil && r.isEmptySchema(v) {
+				// Empty value schemas are rendered inline.
+				out = append(out, r.Prefix()+"additionalProperties: {}")
+			} else if v != nil {
+				// Value type is rendered by the child element.
+				out = append(out, r.Prefix()+"additionalProperties:")
 			} else {
-				out = append(out, r.Prefix()+"additionalProperties: false")
+				out = append(out, r.Prefix()+"additionalProperties: true")
 			}
 			r.SetIndent(r.Indent() + 1)
 		case generictype.List.String():
 			out = append(out,
-				r.Prefix()+"type: array",
-				r.Prefix()+"items:",
+				r.typeLine(t, "array"),
 			)
+			if nativeType.Options.Get("Kind") == "array" && nativeType.Options.Get("Len") != "" {
+				// Go arrays have a fixed length.
+				out = append(out,
+					r.Prefix()+"minItems: "+nativeType.Options.Get("Len"),
+					r.Prefix()+"maxItems: "+nativeType.Options.Get("Len"),
+				)
+			}
+			if len(t.Children) > 0 && r.isEmptySchema(t.Children[0]) {
+				// Empty item schemas are rendered inline.
+				out = append(out, r.Prefix()+"items: {}")
+			} else {
+				out = append(out, r.Prefix()+"items:")
+			}
+			r.SetIndent(r.Indent() + 1)
+		case generictype.Union.String():
+			// Alternatives are rendered by the child elements as list items.
+			out = append(out, r.Prefix()+"oneOf:")
 			r.SetIndent(r.Indent() + 1)
 		case generictype.Boolean.String():
 			out = append(out,
-				r.Prefix()+"type: boolean",
+				r.typeLine(t, openAPIType(t, "boolean")),
 			)
 		case generictype.Integer.String():
 			out = append(out,
-				r.Prefix()+"type: integer",
+				r.typeLine(t, openAPIType(t, "integer")),
 			)
+			inferred := ""
 			if nativeType.Type == "int64" || nativeType.Type == "uint64" {
-				out = append(out,
-					r.Prefix()+"format: int64",
-				)
+				inferred = "int64"
 			}
+			out = append(out, r.format(t, inferred)...)
 		case generictype.Float.String():
 			out = append(out,
-				r.Prefix()+"type: number",
+				r.typeLine(t, openAPIType(t, "number")),
 			)
+			inferred := ""
 			if nativeType.Type == "float64" {
-				out = append(out,
-					r.Prefix()+"format: double",
-				)
+				inferred = "double"
 			}
+			out = append(out, r.format(t, inferred)...)
 		case generictype.String.String():
 			out = append(out,
-				r.Prefix()+"type: string",
+				r.typeLine(t, "string"),
 			)
+			out = append(out, r.format(t, r.inferFormat(t))...)
+			if pattern, ok := r.Options.ResolveOption(t, "pattern"); ok && pattern != "" {
+				out = append(out, r.Prefix()+"pattern: "+quote(pattern))
+			}
+		case generictype.Duration.String():
+			if r.Options.DurationAsString {
+				out = append(out, r.typeLine(t, "string"))
+				out = append(out, r.format(t, "")...)
+			} else {
+				out = append(out, r.typeLine(t, "integer"))
+				out = append(out, r.format(t, "int64")...)
+			}
 		case generictype.DateTime.String():
 			out = append(out,
-				r.Prefix()+"type: string",
-				r.Prefix()+"format: date-time",
+				r.typeLine(t, "string"),
 			)
+			out = append(out, r.format(t, "date-time")...)
+		case generictype.Any.String():
+			// An empty schema allows any value. Unnamed empty schemas are rendered inline by their parent.
+			if len(out) == keyLine && keyLine > 0 {
+				out[keyLine-1] += " {}"
+			}
 		default:
-			if strings.HasPrefix(t.Type, generictype.Invalid.String()) {
+			if t.IsInvalid() {
 				// Use "string" type for invalid elements so that OpenAPI schema is valid.
-				out = append(out, r.Prefix()+"type: string")
+				out = append(out, r.typeLine(t, "string"))
 			} else {
 				// What else could this be? Let OpenAPI figure it out.
-				out = append(out, r.Prefix()+"type: "+t.Type)
+				out = append(out, r.typeLine(t, t.Type))
 			}
 		}
+
+		if t.IsBasicType() {
+			out = append(out, r.enum(t)...)
+		}
+		if t.Type != generictype.Map.String() {
+			// Map constraints are rendered before the value schema.
+			out = append(out, r.constraints(t)...)
+		}
+		out = append(out, r.example(t)...)
+		out = append(out, r.extensions(t)...)
+	}
+
+	// Union alternatives are list items. The first line starts the item at the parent's indent.
+	if t.Parent.Type == generictype.Union.String() && len(out) > 0 {
+		out[0] = r.itemPrefix(startIndent) + strings.TrimPrefix(out[0], strings.Repeat(r.indentPrefix(), startIndent))
+	}
+
+	return out
+}
+
+// format returns a format line for an element.
+// - The resolved format (tag, native format, global default) overrides the inferred format.
+func (r *OpenAPIRenderer) format(t *types.TypeNode, inferred string) []string {
+	if val, ok := r.Options.ResolveOption(t, renderer.FORMAT_OPTION); ok {
+		inferred = val
+	}
+
+	if inferred == "" {
+		return []string{}
+	}
+	return []string{r.Prefix() + "format: " + inferred}
+}
+
+// uuidRegexp matches UUID strings, e.g. "123e4567-e89b-12d3-a456-426614174000"
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// numberRegexp matches decimal numbers in JSON syntax, e.g. "42", "-1.5" or "1e-3"
+// - strconv.ParseFloat also accepts values such as "NaN", "Inf", "0x1p4" or "1_0" that are not valid OpenAPI numbers.
+var numberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// inferFormat returns a string format guessed from the field name if InferFormats is set.
+// - "Email" or "*Email" --> email
+// - "*URL" or "*URI" --> uri
+// - "*UUID", or "*ID" with an example that looks like a UUID --> uuid
+// - Returns empty string if no format is inferred.
+func (r *OpenAPIRenderer) inferFormat(t *types.TypeNode) string {
+	if !r.Options.InferFormats || t.Name == "" {
+		return ""
+	}
+
+	name := t.Name
+	switch {
+	case strings.EqualFold(name, "email") || strings.HasSuffix(name, "Email"):
+		return "email"
+	case strings.EqualFold(name, "url") || strings.EqualFold(name, "uri") ||
+		strings.HasSuffix(name, "URL") || strings.HasSuffix(name, "URI"):
+		return "uri"
+	case strings.EqualFold(name, "uuid") || strings.HasSuffix(name, "UUID"):
+		return "uuid"
+	case strings.HasSuffix(name, "ID") || strings.HasSuffix(name, "Id"):
+		if example, ok := r.Options.ResolveOption(t, "example"); ok && uuidRegexp.MatchString(example) {
+			return "uuid"
+		}
+	}
+	return ""
+}
+
+// enum returns an enum list for an element with the "enum" option.
+// - String values are quoted so that YAML does not convert them to other types.
+func (r *OpenAPIRenderer) enum(t *types.TypeNode) []string {
+	vals := r.Options.ResolveList(t, "enum")
+	if len(vals) == 0 {
+		return []string{}
+	}
+
+	out := []string{r.Prefix() + "enum:"}
+	for _, val := range vals {
+		if t.Type == generictype.String.String() || isStringEncoded(t) {
+			val = quote(val)
+		}
+		out = append(out, r.Prefix()+"- "+val)
+	}
+	if t.Nullable && r.MetaData.Is31() {
+		// The null type must also be an enum value.
+		out = append(out, r.Prefix()+"- null")
+	}
+	return out
+}
+
+// constraints returns length and range lines for elements with constraint options.
+// - "minLength" and "maxLength" apply to strings and must be non-negative integers.
+// - "minimum" and "maximum" apply to integers and floats and must be numbers.
+// - "minProperties" and "maxProperties" apply to maps and must be non-negative integers.
+// - Invalid values are ignored so that the OpenAPI schema is valid.
+func (r *OpenAPIRenderer) constraints(t *types.TypeNode) []string {
+	var keys []string
+	var isValid func(val string) bool
+
+	switch t.Type {
+	case generictype.String.String():
+		keys = []string{"minLength", "maxLength"}
+		isValid = func(val string) bool {
+			_, err := strconv.ParseUint(val, 10, 64)
+			return err == nil
+		}
+	case generictype.Integer.String(), generictype.Float.String():
+		keys = []string{"minimum", "maximum"}
+		isValid = numberRegexp.MatchString
+	case generictype.Map.String():
+		keys = []string{"minProperties", "maxProperties"}
+		isValid = func(val string) bool {
+			_, err := strconv.ParseUint(val, 10, 64)
+			return err == nil
+		}
+	default:
+		return []string{}
+	}
+
+	out := []string{}
+	for _, key := range keys {
+		if val, ok := r.Options.ResolveOption(t, key); ok && isValid(val) {
+			out = append(out, r.Prefix()+key+": "+val)
+		}
 	}
+	return out
+}
+
+// keyPattern returns the "keyPattern" option of a map element.
+// - patternProperties is only supported by OpenAPI 3.1, returns empty string for OpenAPI 3.0.
+func (r *OpenAPIRenderer) keyPattern(t *types.TypeNode) string {
+	if !r.MetaData.Is31() {
+		return ""
+	}
+	pattern, _ := r.Options.ResolveOption(t, "keyPattern")
+	return pattern
+}
+
+// example returns an example line for an element with the "example" option.
+// - Values of numeric and boolean types are bare if they parse as that type, all other values are quoted.
+// - Compound types do not have examples.
+func (r *OpenAPIRenderer) example(t *types.TypeNode) []string {
+	if !t.IsBasicType() && !t.IsKnown() {
+		return []string{}
+	}
+
+	val, ok := r.Options.ResolveOption(t, "example")
+	if !ok {
+		return []string{}
+	}
+
+	bare := false
+	switch t.Type {
+	case generictype.Boolean.String():
+		bare = val == "true" || val == "false"
+	case generictype.Integer.String():
+		_, err := strconv.ParseInt(val, 10, 64)
+		bare = err == nil
+	case generictype.Duration.String():
+		_, err := strconv.ParseInt(val, 10, 64)
+		bare = err == nil && !r.Options.DurationAsString
+	case generictype.Float.String():
+		bare = numberRegexp.MatchString(val)
+	}
+
+	if !bare || isStringEncoded(t) {
+		val = quote(val)
+	}
+	return []string{r.Prefix() + "example: " + val}
+}
 
+// extensions returns vendor extension lines for an element if EmitExtensions is set, see renderer.ResolveExtensions.
+// - Numbers and booleans are bare, all other values are quoted.
+func (r *OpenAPIRenderer) extensions(t *types.TypeNode) []string {
+	extensions := r.Options.ResolveExtensions(t)
+
+	out := []string{}
+	for _, key := range extensions.Keys() {
+		val := extensions.Get(key)
+		if !numberRegexp.MatchString(val) && val != "true" && val != "false" {
+			val = quote(val)
+		}
+		out = append(out, r.Prefix()+key+": "+val)
+	}
 	return out
 }
 
 func (r *OpenAPIRenderer) Post(t *types.TypeNode) []string {
-	return []string{}
+	out := []string{}
+
+	if t.Parent != nil && t.Parent.Name == types.ROOT_NAME {
+		urlPath, method := r.operation(t)
+		info := r.pathOptions(urlPath, method)
+
+		if hasRequestBody(method) {
+			// Operations with a request body still need a responses block.
+			r.SetIndent(r.Indent() + 2)
+			out = append(out, r.Prefix()+`responses:`)
+
+			r.SetIndent(r.Indent() + 1)
+			if len(info.Responses) == 0 {
+				out = append(out, r.Prefix()+`'200':`)
+
+				r.SetIndent(r.Indent() + 1)
+				out = append(out, r.Prefix()+`description: Success`)
+			}
+			for _, code := range info.responseCodes() {
+				out = append(out, r.response(code, info.Responses[code])...)
+			}
+		} else {
+			// Responses after the response with the schema.
+			bodyCode := info.bodyCode()
+
+			r.SetIndent(r.Indent() + 3)
+			for _, code := range info.responseCodes() {
+				if code > bodyCode {
+					out = append(out, r.response(code, info.Responses[code])...)
+				}
+			}
+		}
+	}
+
+	// Unions with a discriminator add it next to oneOf, references are rendered by the referenced schema.
+	if t.Type == generictype.Union.String() && (r.Options.DeReference || t.TypeRef == "") {
+		if r.NativeType(t).Name != "" {
+			r.SetIndent(r.Indent() + 1)
+		}
+		out = append(out, r.discriminator(t)...)
+	}
+
+	return out
+}
+
+// discriminator returns a discriminator block for a union with the "discriminator" option.
+// - Mapping values are references to the alternatives, so they are only rendered if not de-referencing.
+// - Alternatives without the "discriminatorValue" option are mapped by OpenAPI to their schema names.
+func (r *OpenAPIRenderer) discriminator(t *types.TypeNode) []string {
+	native := t.NativeDefault()
+	if native == nil || native.Options.Get("discriminator") == "" {
+		return []string{}
+	}
+
+	out := []string{
+		r.Prefix() + "discriminator:",
+		r.Prefix() + r.indentPrefix() + "propertyName: " + quote(native.Options.Get("discriminator")),
+	}
+	if r.Options.DeReference {
+		return out
+	}
+
+	mapping := []string{}
+	for _, child := range t.Children {
+		childNative := child.NativeDefault()
+		if childNative == nil || childNative.Options.Get("discriminatorValue") == "" || child.TypeRef == "" {
+			continue
+		}
+		mapping = append(mapping, fmt.Sprintf("%s%s: '%s'",
+			strings.Repeat(r.indentPrefix(), r.Indent()+2), quote(childNative.Options.Get("discriminatorValue")), r.schemaRef(r.NativeType(child).TypeRef)))
+	}
+	sort.Strings(mapping)
+
+	if len(mapping) > 0 {
+		out = append(out, r.Prefix()+r.indentPrefix()+"mapping:")
+		out = append(out, mapping...)
+	}
+	return out
+}
+
+// typeLine returns the type line of an element.
+// - Nullable elements in OpenAPI 3.1 have a type array with "null", e.g. "type: [string, 'null']"
+func (r *OpenAPIRenderer) typeLine(t *types.TypeNode, typeName string) string {
+	if t.Nullable && r.MetaData.Is31() {
+		return fmt.Sprintf("%stype: [%s, 'null']", r.Prefix(), typeName)
+	}
+	return r.Prefix() + "type: " + typeName
+}
+
+// inlineProperties returns the additionalProperties of a struct from the value type of its inline map.
+// - The value schema is rendered here because the inline map is not rendered as a property.
+func (r *OpenAPIRenderer) inlineProperties(inline *types.TypeNode) []string {
+	v := mapValue(inline)
+	if v == nil {
+		return []string{r.Prefix() + "additionalProperties: true"}
+	}
+	if r.isEmptySchema(v) {
+		return []string{r.Prefix() + "additionalProperties: {}"}
+	}
+
+	out := []string{r.Prefix() + "additionalProperties:"}
+	r.SetIndent(r.Indent() + 1)
+	out = append(out, renderer.RenderType(v, r)...)
+	r.SetIndent(r.Indent() - 1)
+	return out
+}
+
+// isEmptySchema returns true if an element is rendered as an empty schema without any keys.
+func (r *OpenAPIRenderer) isEmptySchema(t *types.TypeNode) bool {
+	return t.Type == generictype.Any.String() && t.TypeRef == "" && t.Error == "" &&
+		t.Description == "" && !t.Nullable && !r.Options.IsDeprecated(t)
+}
+
+// isStringEncoded returns true if a number or boolean is encoded as a JSON string with the json "string" option.
+// - e.g. `json:"id,string"`
+func isStringEncoded(t *types.TypeNode) bool {
+	switch t.Type {
+	case generictype.Boolean.String(), generictype.Integer.String(), generictype.Float.String():
+	default:
+		return false
+	}
+
+	if jsonNative := t.Native["json"]; jsonNative != nil {
+		if _, ok := jsonNative.Options.Lookup("string"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIType returns the OpenAPI type of a number or boolean, "string" if it is encoded as a JSON string.
+// - The format of string encoded numbers still describes the underlying number, e.g. "int64".
+func openAPIType(t *types.TypeNode, defaultType string) string {
+	if isStringEncoded(t) {
+		return "string"
+	}
+	return defaultType
+}
+
+// mapValue returns the element that describes the value type of a map.
+// - Map child only exists when map has no known keys.
+// - Returns nil if the map has no value element or the value type is invalid.
+func mapValue(t *types.TypeNode) *types.TypeNode {
+	if len(t.Children) != 1 || t.Children[0].Name != "" {
+		return nil
+	}
+
+	child := t.Children[0]
+	if child.IsInvalid() {
+		return nil
+	}
+	return child
+}
+
+// operation returns the API path and HTTP method for a root element.
+// - MetaKey is the path with an optional method, see types.OperationKey.
+// - Default method is "get".
+func (r *OpenAPIRenderer) operation(t *types.TypeNode) (urlPath, method string) {
+	urlPath, method = types.SplitOperationKey(t.MetaKey)
+	if urlPath == "" {
+		urlPath = "/unknown/path"
+	}
+
+	// Path must start with "/"
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+
+	if method == "" {
+		method = "get"
+	}
+
+	return urlPath, method
+}
+
+// pathOptions returns the PathOptions of an operation.
+// - A path with method takes precedence over a path.
+func (r *OpenAPIRenderer) pathOptions(urlPath, method string) PathInfo {
+	info, ok := r.PathOptions[types.OperationKey(urlPath, method)]
+	if !ok {
+		info = r.PathOptions[urlPath]
+	}
+	return info
+}
+
+// pathInfo returns lines for the operation details from PathOptions.
+// - Default summary is used if PathOptions has no summary.
+func (r *OpenAPIRenderer) pathInfo(urlPath, method string) []string {
+	info := r.pathOptions(urlPath, method)
+
+	out := []string{}
+	if info.OperationId != "" {
+		out = append(out, r.Prefix()+"operationId: "+quote(info.OperationId))
+	}
+
+	if info.Summary != "" {
+		out = append(out, r.Prefix()+"summary: "+quote(info.Summary))
+	} else if hasRequestBody(method) {
+		out = append(out, r.Prefix()+"summary: Send data.")
+	} else {
+		out = append(out, r.Prefix()+"summary: Return data.")
+	}
+
+	if info.Description != "" {
+		out = append(out, r.Prefix()+"description: "+quote(info.Description))
+	}
+
+	if len(info.Tags) > 0 {
+		out = append(out, r.Prefix()+"tags:")
+		for _, tag := range info.Tags {
+			out = append(out, r.Prefix()+"- "+quote(tag))
+		}
+	}
+
+	if info.Security != nil {
+		if len(info.Security) == 0 {
+			out = append(out, r.Prefix()+"security: []")
+		} else if b, err := marshalSecurity(info.Security); err != nil {
+			r.marshalErr = err
+		} else {
+			out = append(out, r.Prefix()+"security:")
+			out = util.AppendStrings(out, []string{string(b)}, r.Prefix())
+		}
+	}
+
+	out = append(out, r.parameterLines(urlPath, method)...)
+
+	return out
+}
+
+// response returns lines for a response at the current indent.
+// - The body is a reference to the component schema in SchemaRef if set.
+func (r *OpenAPIRenderer) response(code string, spec ResponseSpec) []string {
+	out := []string{r.Prefix() + quote(code) + ":"}
+
+	indent := r.Indent()
+	r.SetIndent(indent + 1)
+	out = append(out, r.Prefix()+"description: "+quote(spec.Description))
+	if spec.SchemaRef != "" {
+		out = append(out, r.Prefix()+"content:")
+		r.SetIndent(indent + 2)
+		out = append(out, r.Prefix()+"application/json:")
+		r.SetIndent(indent + 3)
+		out = append(out, r.Prefix()+"schema:")
+		r.SetIndent(indent + 4)
+		out = append(out, fmt.Sprintf(`%s$ref: '%s'`, r.Prefix(), r.schemaRef(r.Options.SchemaName(spec.SchemaRef))))
+	}
+	r.SetIndent(indent)
+
+	return out
+}
+
+// quote returns a single-quoted YAML string.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// hasRequestBody returns true if the HTTP method sends the schema as a request body.
+func hasRequestBody(method string) bool {
+	switch method {
+	case "post", "put", "patch":
+		return true
+	}
+	return false
 }
 
 // Path is a function that builds a path string from a TypeNode.
@@ -0,0 +1,1144 @@
+package openapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type OperationUser struct {
+	Name string `json:"name"`
+}
+
+type OperationStatus struct {
+	OK bool `json:"ok"`
+}
+
+// TestOpenAPIRenderer_Operations validates that operations on the same path are merged into one PathItem.
+func TestOpenAPIRenderer_Operations(t *testing.T) {
+	r := reflector.NewReflector()
+	r.DeriveSchemaForOperation(OperationUser{}, "/users", "")
+	r.DeriveSchemaForOperation(OperationUser{}, "/users", "POST")
+	r.DeriveSchemaForOperation(OperationUser{}, "/users", "put")
+	schema := r.DeriveSchemaForOperation(OperationStatus{}, "/status", "get")
+
+	opt := renderer.NewOptions()
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("operations", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL operations: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: operations`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /status:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/OperationStatus'`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/OperationUser'`,
+		`    post:`,
+		`      summary: Send data.`,
+		`      requestBody:`,
+		`        required: true`,
+		`        content:`,
+		`          application/json:`,
+		`            schema:`,
+		`              $ref: '#/components/schemas/OperationUser'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`    put:`,
+		`      summary: Send data.`,
+		`      requestBody:`,
+		`        required: true`,
+		`        content:`,
+		`          application/json:`,
+		`            schema:`,
+		`              $ref: '#/components/schemas/OperationUser'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`components:`,
+		`  schemas:`,
+		`    OperationStatus:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        ok:`,
+		`          type: boolean`,
+		`    OperationUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        name:`,
+		`          type: string`,
+	}
+
+	util.CompareStrings(t, "operations", gotStrings, wantStrings)
+}
+
+// TestOpenAPIRenderer_OperationGroups validates that operations on the same path share a PathItem in any order.
+func TestOpenAPIRenderer_OperationGroups(t *testing.T) {
+	testCases := []struct {
+		name     string
+		order    bool
+		paths    [][2]string
+		wantErr  string
+		wantKeys []string
+	}{
+		{
+			name:  "normalized",
+			paths: [][2]string{{"users", "post"}, {"/status", ""}, {"/users", "get"}},
+			wantKeys: []string{
+				`  /status:`,
+				`    get:`,
+				`  /users:`,
+				`    get:`,
+				`    post:`,
+			},
+		},
+		{
+			name:  "preserve-order",
+			order: true,
+			paths: [][2]string{{"/users", "post"}, {"/status", ""}, {"users", "get"}},
+			wantKeys: []string{
+				`  /users:`,
+				`    get:`,
+				`    post:`,
+				`  /status:`,
+				`    get:`,
+			},
+		},
+		{
+			name:    "duplicate",
+			paths:   [][2]string{{"/users", ""}, {"/users", "get"}},
+			wantErr: `duplicate operation "/users get"`,
+		},
+	}
+
+	for _, test := range testCases {
+		r := reflector.NewReflector()
+		var schema *types.Schema
+		for _, p := range test.paths {
+			schema = r.DeriveSchemaForOperation(OperationUser{}, p[0], p[1])
+		}
+
+		opt := renderer.NewOptions()
+		opt.PreserveOrder = test.order
+		gotStrings, err := NewOpenAPIRenderer(NewMetaData("groups", "v1.0.0"), opt).ProcessSchema(schema)
+		if test.wantErr != "" {
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("TEST_FAIL %s: err=%v want=%s", test.name, err, test.wantErr)
+			} else {
+				t.Logf("TEST_OK %s: err=%s", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+
+		// Only path and method keys are compared.
+		gotKeys := []string{}
+		for _, line := range gotStrings {
+			if strings.HasPrefix(line, "  /") || regexp.MustCompile(`^    [a-z]+:$`).MatchString(line) {
+				gotKeys = append(gotKeys, line)
+			}
+		}
+		util.CompareStrings(t, test.name, gotKeys, test.wantKeys)
+	}
+}
+
+// TestOpenAPIRenderer_PathOptions validates operation details by path and by path with method.
+func TestOpenAPIRenderer_PathOptions(t *testing.T) {
+	r := reflector.NewReflector()
+	r.DeriveSchemaForOperation(OperationUser{}, "/users", "")
+	schema := r.DeriveSchemaForOperation(OperationUser{}, "/users", "post")
+
+	opt := renderer.NewOptions()
+	openAPI := NewOpenAPIRenderer(NewMetaData("path-options", "v1.0.0"), opt)
+	openAPI.PathOptions["/users"] = PathInfo{
+		OperationId: "getUser",
+		Summary:     "Get a user's data.",
+		Tags:        []string{"users", "read"},
+	}
+	openAPI.PathOptions["/users post"] = PathInfo{
+		OperationId: "createUser",
+		Description: "Creates a user.",
+	}
+
+	gotStrings, err := openAPI.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL path-options: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: path-options`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users:`,
+		`    get:`,
+		`      operationId: 'getUser'`,
+		`      summary: 'Get a user''s data.'`,
+		`      tags:`,
+		`      - 'users'`,
+		`      - 'read'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/OperationUser'`,
+		`    post:`,
+		`      operationId: 'createUser'`,
+		`      summary: Send data.`,
+		`      description: 'Creates a user.'`,
+		`      requestBody:`,
+		`        required: true`,
+		`        content:`,
+		`          application/json:`,
+		`            schema:`,
+		`              $ref: '#/components/schemas/OperationUser'`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`components:`,
+		`  schemas:`,
+		`    OperationUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        name:`,
+		`          type: string`,
+	}
+
+	util.CompareStrings(t, "path-options", gotStrings, wantStrings)
+}
+
+type DurationStruct struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
+// TestOpenAPIRenderer_DurationAsString validates that durations can be rendered as integers or strings.
+func TestOpenAPIRenderer_DurationAsString(t *testing.T) {
+	testCases := []struct {
+		name             string
+		durationAsString bool
+		want             []string
+	}{
+		{
+			name: "integer",
+			want: []string{`          type: integer`, `          format: int64`},
+		},
+		{
+			name:             "string",
+			durationAsString: true,
+			want:             []string{`          type: string`},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(DurationStruct{}, "/duration")
+
+		opt := renderer.NewOptions()
+		opt.DurationAsString = test.durationAsString
+		gotStrings, err := NewOpenAPIRenderer(NewMetaData(test.name, "v1.0.0"), opt).ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+		}
+
+		// Compare lines after the property name.
+		for i, s := range gotStrings {
+			if s == `        timeout:` {
+				gotStrings = gotStrings[i+1:]
+				break
+			}
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}
+
+type DescribedAddress struct {
+	City string `json:"city"`
+}
+
+type DescribedUser struct {
+	Name    string            `json:"name" b9schema:"desc=Full name"`
+	Email   string            `json:"email"`
+	Tags    map[int]string    `json:"tags"`
+	Home    DescribedAddress  `json:"home"`
+	Work    *DescribedAddress `json:"work"`
+	Comment string            `json:"comment"`
+}
+
+// TestOpenAPIRenderer_Descriptions validates descriptions from tags and registered descriptions.
+func TestOpenAPIRenderer_Descriptions(t *testing.T) {
+	r := reflector.NewReflector()
+	r.SetDescriptions(map[string]string{
+		"DescribedUser":         "A user",
+		"DescribedUser.Name":    "Ignored because of the tag",
+		"DescribedUser.Email":   "Email address",
+		"DescribedUser.Tags":    "User tags",
+		"DescribedUser.Home":    "Home address",
+		"DescribedUser.Work":    "Work address",
+		"DescribedUser.Comment": "It's a comment",
+		"DescribedAddress":      "An address",
+	})
+	schema := r.DeriveSchema(DescribedUser{}, "/users")
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("descriptions", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL descriptions: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: descriptions`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/DescribedUser'`,
+		`components:`,
+		`  schemas:`,
+		`    DescribedAddress:`,
+		`      description: 'An address'`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        city:`,
+		`          type: string`,
+		`    DescribedUser:`,
+		`      description: 'A user'`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        comment:`,
+		`          description: 'It''s a comment'`,
+		`          type: string`,
+		`        email:`,
+		`          description: 'Email address'`,
+		`          type: string`,
+		`        home:`,
+		`          description: 'Home address'`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/DescribedAddress'`,
+		`        name:`,
+		`          description: 'Full name'`,
+		`          type: string`,
+		`        tags:`,
+		`          description: 'User tags;ERROR=map key type must be string'`,
+		`          type: object`,
+		`          additionalProperties: true`,
+		`        work:`,
+		`          description: 'Work address'`,
+		`          nullable: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/DescribedAddress'`,
+	}
+	util.CompareStrings(t, "descriptions", gotStrings, wantStrings)
+}
+
+type FormatStruct struct {
+	Int32Val   int32     `json:"int32Val"`
+	Int64Val   int64     `json:"int64Val"`
+	Float32Val float32   `json:"float32Val"`
+	TimeVal    time.Time `json:"timeVal"`
+	UnixTime   int64     `json:"unixTime" b9schema:"format=unix-time"`
+	Email      string    `json:"email" b9schema:"format=email"`
+}
+
+// TestOpenAPIRenderer_NativeFormat validates formats set during reflection and tag overrides.
+func TestOpenAPIRenderer_NativeFormat(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(FormatStruct{}, "/formats")
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("formats", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL formats: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: formats`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /formats:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/FormatStruct'`,
+		`components:`,
+		`  schemas:`,
+		`    FormatStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        email:`,
+		`          type: string`,
+		`          format: email`,
+		`        float32Val:`,
+		`          type: number`,
+		`          format: float`,
+		`        int32Val:`,
+		`          type: integer`,
+		`          format: int32`,
+		`        int64Val:`,
+		`          type: integer`,
+		`          format: int64`,
+		`        timeVal:`,
+		`          type: string`,
+		`          format: date-time`,
+		`        unixTime:`,
+		`          type: integer`,
+		`          format: unix-time`,
+	}
+	util.CompareStrings(t, "formats", gotStrings, wantStrings)
+}
+
+type InferFormatStruct struct {
+	Email      string   `json:"email"`
+	WorkEmail  string   `json:"workEmail"`
+	WebsiteURL string   `json:"websiteUrl"`
+	SourceURI  string   `json:"sourceUri"`
+	TraceUUID  string   `json:"traceUuid"`
+	RequestID  string   `json:"requestId" b9schema:"example=123e4567-e89b-12d3-a456-426614174000"`
+	UserID     string   `json:"userId"`
+	LogoURL    string   `json:"logoUrl" b9schema:"format=uri-reference"`
+	Emails     []string `json:"emails"`
+}
+
+// TestOpenAPIRenderer_InferFormats validates formats guessed from field names and that tag formats take precedence.
+func TestOpenAPIRenderer_InferFormats(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(InferFormatStruct{}, "/formats")
+
+	opt := renderer.NewOptions()
+	opt.InferFormats = true
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("formats", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL infer-formats: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: formats`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /formats:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/InferFormatStruct'`,
+		`components:`,
+		`  schemas:`,
+		`    InferFormatStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        email:`,
+		`          type: string`,
+		`          format: email`,
+		`        emails:`,
+		`          type: array`,
+		`          items:`,
+		`            type: string`,
+		`        logoUrl:`,
+		`          type: string`,
+		`          format: uri-reference`,
+		`        requestId:`,
+		`          type: string`,
+		`          format: uuid`,
+		`          example: '123e4567-e89b-12d3-a456-426614174000'`,
+		`        sourceUri:`,
+		`          type: string`,
+		`          format: uri`,
+		`        traceUuid:`,
+		`          type: string`,
+		`          format: uuid`,
+		`        userId:`,
+		`          type: string`,
+		`        websiteUrl:`,
+		`          type: string`,
+		`          format: uri`,
+		`        workEmail:`,
+		`          type: string`,
+		`          format: email`,
+	}
+	util.CompareStrings(t, "infer-formats", gotStrings, wantStrings)
+}
+
+// HoistStructHome has the name that would be built for HoistStruct.Home.
+type HoistStructHome struct {
+	Street string `json:"street"`
+}
+
+type HoistStruct struct {
+	Home struct {
+		City string `json:"city"`
+	} `json:"home"`
+	Work *struct {
+		City string `json:"city"`
+	} `json:"work"`
+	Renamed struct {
+		City string `json:"town"`
+	} `json:"renamed"`
+	List []struct {
+		City string `json:"city"`
+	} `json:"list"`
+	Unique struct {
+		Zip string `json:"zip"`
+	} `json:"unique"`
+	Existing HoistStructHome `json:"existing"`
+}
+
+// TestOpenAPIRenderer_HoistAnonymous validates that repeated anonymous structs become component schemas.
+func TestOpenAPIRenderer_HoistAnonymous(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(HoistStruct{}, "/hoist")
+
+	r := NewOpenAPIRenderer(NewMetaData("hoist", "v1.0.0"), nil)
+	r.HoistAnonymous = true
+
+	gotStrings, err := r.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL hoist: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: hoist`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /hoist:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/HoistStruct'`,
+		`components:`,
+		`  schemas:`,
+		`    HoistStruct:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        existing:`,
+		`          $ref: '#/components/schemas/HoistStructHome'`,
+		`        home:`,
+		`          $ref: '#/components/schemas/HoistStructHome2'`,
+		`        list:`,
+		`          type: array`,
+		`          items:`,
+		`            $ref: '#/components/schemas/HoistStructHome2'`,
+		`        renamed:`,
+		`          type: object`,
+		`          additionalProperties: false`,
+		`          properties:`,
+		`            town:`,
+		`              type: string`,
+		`        unique:`,
+		`          type: object`,
+		`          additionalProperties: false`,
+		`          properties:`,
+		`            zip:`,
+		`              type: string`,
+		`        work:`,
+		`          nullable: true`,
+		`          allOf:`,
+		`          - $ref: '#/components/schemas/HoistStructHome2'`,
+		`    HoistStructHome:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        street:`,
+		`          type: string`,
+		`    HoistStructHome2:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        city:`,
+		`          type: string`,
+	}
+	util.CompareStrings(t, "hoist", gotStrings, wantStrings)
+
+	// The original schema is not changed.
+	if got := len(schema.TypeRef.Children); got != 2 {
+		t.Errorf("TEST_FAIL hoist: got=%d type refs want=2", got)
+	}
+}
+
+type ExampleUser struct {
+	Name    string    `json:"name" b9schema:"example=Tag wins"`
+	Age     int       `json:"age"`
+	Admin   bool      `json:"admin"`
+	Joined  time.Time `json:"joined"`
+	Comment string    `json:"comment"`
+}
+
+func TestOpenAPIRenderer_Examples(t *testing.T) {
+	r := reflector.NewReflector()
+	r.SetExamples(map[string]interface{}{
+		"ExampleUser.Name":    "Ignored because of the tag",
+		"ExampleUser.Age":     42,
+		"ExampleUser.Admin":   false,
+		"ExampleUser.Joined":  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		"ExampleUser.Comment": "It's 42",
+	})
+	schema := r.DeriveSchema(ExampleUser{}, "/users")
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("examples", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL examples: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: examples`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/ExampleUser'`,
+		`components:`,
+		`  schemas:`,
+		`    ExampleUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        admin:`,
+		`          type: boolean`,
+		`          example: false`,
+		`        age:`,
+		`          type: integer`,
+		`          format: int64`,
+		`          example: 42`,
+		`        comment:`,
+		`          type: string`,
+		`          example: 'It''s 42'`,
+		`        joined:`,
+		`          type: string`,
+		`          format: date-time`,
+		`          example: '2021-01-02T03:04:05Z'`,
+		`        name:`,
+		`          type: string`,
+		`          example: 'Tag wins'`,
+	}
+	util.CompareStrings(t, "examples", gotStrings, wantStrings)
+}
+
+type SampleUser struct {
+	ExampleUser
+	Nickname *string  `json:"nickname"`
+	Tags     []string `json:"tags"`
+	secret   string
+}
+
+func TestOpenAPIRenderer_CaptureExamples(t *testing.T) {
+	nickname := "Bobby"
+	sample := SampleUser{
+		ExampleUser: ExampleUser{
+			Name:   "Bob",
+			Age:    42,
+			Joined: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Nickname: &nickname,
+		Tags:     []string{"new"},
+		secret:   "hidden",
+	}
+
+	r := reflector.NewReflector()
+	r.CaptureExamples = true
+	schema := r.DeriveSchema(sample, "/users")
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("capture", "v1.0.0"), nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL capture: err=%s", err)
+	}
+
+	// Zero values (admin, comment) and lists do not have examples, the tag example wins over the value.
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: capture`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/SampleUser'`,
+		`components:`,
+		`  schemas:`,
+		`    SampleUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        admin:`,
+		`          type: boolean`,
+		`        age:`,
+		`          type: integer`,
+		`          format: int64`,
+		`          example: 42`,
+		`        comment:`,
+		`          type: string`,
+		`        joined:`,
+		`          type: string`,
+		`          format: date-time`,
+		`          example: '2021-01-02T03:04:05Z'`,
+		`        name:`,
+		`          type: string`,
+		`          example: 'Tag wins'`,
+		`        nickname:`,
+		`          nullable: true`,
+		`          type: string`,
+		`          example: 'Bobby'`,
+		`        tags:`,
+		`          type: array`,
+		`          items:`,
+		`            type: string`,
+	}
+	util.CompareStrings(t, "capture", gotStrings, wantStrings)
+}
+
+type NullableAddress struct {
+	City string `json:"city"`
+}
+
+type NullableUser struct {
+	Name    string           `json:"name"`
+	Email   *string          `json:"email"`
+	Age     *int             `json:"age"`
+	Role    *string          `json:"role" b9schema:"enum=admin|user"`
+	Home    NullableAddress  `json:"home" b9schema:"deprecated"`
+	Work    *NullableAddress `json:"work"`
+	Friends []*string        `json:"friends"`
+}
+
+// TestOpenAPIRenderer_OpenAPI31 validates that OpenAPI 3.1 uses type arrays instead of the nullable keyword.
+func TestOpenAPIRenderer_OpenAPI31(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(NullableUser{}, "/users")
+
+	metadata := NewMetaData("openapi-3.1", "v1.0.0")
+	metadata.OpenAPI = OPENAPI_31_VERSION
+
+	gotStrings, err := NewOpenAPIRenderer(metadata, nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL openapi-3.1: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.1.0`,
+		`info:`,
+		`  title: openapi-3.1`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/NullableUser'`,
+		`components:`,
+		`  schemas:`,
+		`    NullableAddress:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        city:`,
+		`          type: string`,
+		`    NullableUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        age:`,
+		`          type: [integer, 'null']`,
+		`          format: int64`,
+		`        email:`,
+		`          type: [string, 'null']`,
+		`        friends:`,
+		`          type: array`,
+		`          items:`,
+		`            type: [string, 'null']`,
+		`        home:`,
+		`          deprecated: true`,
+		`          $ref: '#/components/schemas/NullableAddress'`,
+		`        name:`,
+		`          type: string`,
+		`        role:`,
+		`          type: [string, 'null']`,
+		`          enum:`,
+		`          - 'admin'`,
+		`          - 'user'`,
+		`          - null`,
+		`        work:`,
+		`          anyOf:`,
+		`          - $ref: '#/components/schemas/NullableAddress'`,
+		`          - type: 'null'`,
+	}
+	util.CompareStrings(t, "openapi-3.1", gotStrings, wantStrings)
+}
+
+type PatternLabels struct {
+	Labels map[string]string      `json:"labels" b9schema:"keyPattern=^[a-z]+$,minProperties=1"`
+	Extras map[string]interface{} `json:"extras" b9schema:"keyPattern=^x-"`
+}
+
+// TestOpenAPIRenderer_KeyPattern validates that OpenAPI 3.1 renders map key patterns as patternProperties.
+func TestOpenAPIRenderer_KeyPattern(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(PatternLabels{}, "/labels")
+
+	metadata := NewMetaData("key-pattern", "v1.0.0")
+	metadata.OpenAPI = OPENAPI_31_VERSION
+
+	gotStrings, err := NewOpenAPIRenderer(metadata, nil).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL key-pattern: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.1.0`,
+		`info:`,
+		`  title: key-pattern`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /labels:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/PatternLabels'`,
+		`components:`,
+		`  schemas:`,
+		`    PatternLabels:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        extras:`,
+		`          type: object`,
+		`          additionalProperties: false`,
+		`          patternProperties:`,
+		`            '^x-': {}`,
+		`        labels:`,
+		`          type: object`,
+		`          minProperties: 1`,
+		`          additionalProperties: false`,
+		`          patternProperties:`,
+		`            '^[a-z]+$':`,
+		`              type: string`,
+	}
+	util.CompareStrings(t, "key-pattern", gotStrings, wantStrings)
+}
+
+type NumberValues struct {
+	Valid   float64 `json:"valid" b9schema:"minimum=-1.5,maximum=1e3,example=0.5,x-scale=2"`
+	Special float64 `json:"special" b9schema:"minimum=NaN,maximum=Inf,example=0x1p4,x-scale=1_0"`
+}
+
+// TestOpenAPIRenderer_Numbers validates that only decimal numbers are rendered as bare numbers.
+func TestOpenAPIRenderer_Numbers(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(NumberValues{}, "/numbers")
+
+	opt := renderer.NewOptions()
+	opt.EmitExtensions = true
+
+	gotStrings, err := NewOpenAPIRenderer(NewMetaData("numbers", "v1.0.0"), opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL numbers: err=%s", err)
+	}
+
+	util.CompareStrings(t, "numbers", gotStrings, []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: numbers`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /numbers:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/NumberValues'`,
+		`components:`,
+		`  schemas:`,
+		`    NumberValues:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        special:`,
+		`          type: number`,
+		`          format: double`,
+		`          example: '0x1p4'`,
+		`          x-scale: '1_0'`,
+		`        valid:`,
+		`          type: number`,
+		`          format: double`,
+		`          minimum: -1.5`,
+		`          maximum: 1e3`,
+		`          example: 0.5`,
+		`          x-scale: 2`,
+	})
+}
+
+type UserQuery struct {
+	ID     int64     `json:"id"`
+	Search string    `json:"q,omitempty" b9schema:"desc=Search text"`
+	Limit  int32     `json:"limit"`
+	Since  time.Time `json:"since,omitempty"`
+	Old    bool      `json:"old,omitempty" b9schema:"deprecated"`
+}
+
+// TestOpenAPIRenderer_Parameters validates path and query parameters derived from a struct.
+func TestOpenAPIRenderer_Parameters(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchemaForOperation(OperationUser{}, "/users/{id}", "")
+
+	r := NewOpenAPIRenderer(NewMetaData("parameters", "v1.0.0"), nil)
+	if err := r.AddParameters("/users/{id}", UserQuery{}); err != nil {
+		t.Fatalf("TEST_FAIL parameters: err=%s", err)
+	}
+
+	// Invalid parameters return errors.
+	if err := r.AddParameters("/users/{userId}", UserQuery{}); err == nil {
+		t.Errorf("TEST_FAIL missing path field: want error")
+	}
+	if err := r.AddParameters("/users", OperationUser{Name: "x"}); err != nil {
+		t.Errorf("TEST_FAIL scalar fields: err=%s", err)
+	}
+	if err := r.AddParameters("/users", struct{ Tags []string }{}); err == nil {
+		t.Errorf("TEST_FAIL list field: want error")
+	}
+	if err := r.AddParameters("/users", "q"); err == nil {
+		t.Errorf("TEST_FAIL not a struct: want error")
+	}
+
+	gotStrings, err := r.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL parameters: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`  title: parameters`,
+		`  version: v1.0.0`,
+		``,
+		`paths:`,
+		`  /users/{id}:`,
+		`    get:`,
+		`      summary: Return data.`,
+		`      parameters:`,
+		`      - name: 'id'`,
+		`        in: path`,
+		`        required: true`,
+		`        schema:`,
+		`          type: integer`,
+		`          format: int64`,
+		`      - name: 'limit'`,
+		`        in: query`,
+		`        required: true`,
+		`        schema:`,
+		`          type: integer`,
+		`          format: int32`,
+		`      - name: 'old'`,
+		`        in: query`,
+		`        deprecated: true`,
+		`        schema:`,
+		`          type: boolean`,
+		`      - name: 'q'`,
+		`        in: query`,
+		`        description: 'Search text'`,
+		`        schema:`,
+		`          type: string`,
+		`      - name: 'since'`,
+		`        in: query`,
+		`        schema:`,
+		`          type: string`,
+		`          format: date-time`,
+		`      responses:`,
+		`        '200':`,
+		`          description: Success`,
+		`          content:`,
+		`            application/json:`,
+		`              schema:`,
+		`                $ref: '#/components/schemas/OperationUser'`,
+		`components:`,
+		`  schemas:`,
+		`    OperationUser:`,
+		`      type: object`,
+		`      additionalProperties: false`,
+		`      properties:`,
+		`        name:`,
+		`          type: string`,
+	}
+	util.CompareStrings(t, "parameters", gotStrings, wantStrings)
+}
+
+type PrefixUser struct {
+	Name    string `json:"name"`
+	Address struct {
+		City string `json:"city"`
+	} `json:"address"`
+}
+
+type PrefixQuery struct {
+	ID int `json:"id"`
+}
+
+// TestOpenAPIRenderer_Prefix validates that a caller-provided prefix is used for indentation.
+func TestOpenAPIRenderer_Prefix(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchemaForOperation(PrefixUser{}, "/users/{id}", "")
+
+	opt := renderer.NewOptions()
+	opt.Prefix = "    "
+	r := NewOpenAPIRenderer(NewMetaData("prefix", "v1.0.0"), opt)
+	if err := r.AddParameters("/users/{id}", PrefixQuery{}); err != nil {
+		t.Fatalf("TEST_FAIL prefix: err=%s", err)
+	}
+
+	gotStrings, err := r.ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL prefix: err=%s", err)
+	}
+
+	wantStrings := []string{
+		`openapi: 3.0.0`,
+		`info:`,
+		`    title: prefix`,
+		`    version: v1.0.0`,
+		``,
+		`paths:`,
+		`    /users/{id}:`,
+		`        get:`,
+		`            summary: Return data.`,
+		`            parameters:`,
+		`              - name: 'id'`,
+		`                in: path`,
+		`                required: true`,
+		`                schema:`,
+		`                    type: integer`,
+		`                    format: int64`,
+		`            responses:`,
+		`                '200':`,
+		`                    description: Success`,
+		`                    content:`,
+		`                        application/json:`,
+		`                            schema:`,
+		`                                $ref: '#/components/schemas/PrefixUser'`,
+		`components:`,
+		`    schemas:`,
+		`        PrefixUser:`,
+		`            type: object`,
+		`            additionalProperties: false`,
+		`            properties:`,
+		`                address:`,
+		`                    type: object`,
+		`                    additionalProperties: false`,
+		`                    properties:`,
+		`                        city:`,
+		`                            type: string`,
+		`                name:`,
+		`                    type: string`,
+	}
+	util.CompareStrings(t, "prefix", gotStrings, wantStrings)
+
+	// The output must still be valid YAML with the expected structure.
+	b, err := yaml.YAMLToJSON([]byte(strings.Join(gotStrings, "\n")))
+	if err != nil {
+		t.Fatalf("TEST_FAIL prefix yaml: err=%s", err)
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("TEST_FAIL prefix json: err=%s", err)
+	}
+	params := doc["paths"].(map[string]interface{})["/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})["parameters"].([]interface{})
+	if got := params[0].(map[string]interface{})["in"]; got != "path" {
+		t.Errorf("TEST_FAIL prefix parameter: got=%v want=path", got)
+	}
+}
@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// pathParamRegexp matches template expressions in a path, e.g. "{id}" in "/users/{id}"
+var pathParamRegexp = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// AddParameters derives operation parameters from the fields of a struct.
+// - path is a path ("/users/{id}") or a path with method ("/users/{id} get"), see types.OperationKey.
+// - Fields with json names in braces of the path are "path" parameters, other fields are "query" parameters.
+// - Path parameters are always required, query parameters are required unless nullable or "omitempty".
+// - Returns an error if paramStruct is not a struct, a field is not a basic or known type, or a path parameter has no field.
+func (r *OpenAPIRenderer) AddParameters(path string, paramStruct interface{}) error {
+	urlPath, _ := types.SplitOperationKey(path)
+
+	inPath := map[string]bool{}
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(urlPath, -1) {
+		inPath[match[1]] = true
+	}
+
+	schema := reflector.NewReflector().DeriveSchema(paramStruct, path)
+	elem := schema.Root.Children[0]
+	if elem.Error != "" {
+		return fmt.Errorf("parameters for %q: %s", path, elem.Error)
+	}
+	if elem.TypeRef != "" {
+		elem = schema.TypeRef.ChildByName(elem.TypeRef, nil)
+	}
+
+	params := []*ParameterObject{}
+	found := map[string]bool{}
+	for _, child := range renderer.Children(elem, r) {
+		jsonType := child.GetNativeType("json")
+		if jsonType.Include == threeflag.False {
+			continue
+		}
+
+		paramSchema := r.parameterSchema(child)
+		if child.Error != "" || paramSchema == nil {
+			return fmt.Errorf("parameter %q for %q: type %s is not supported", jsonType.Name, path, child.Type)
+		}
+
+		param := &ParameterObject{
+			Name:        jsonType.Name,
+			In:          "query",
+			Description: child.Description,
+			Required:    !child.IsOptional(),
+			Deprecated:  r.Options.IsDeprecated(child),
+			Schema:      paramSchema,
+		}
+		if inPath[param.Name] {
+			param.In = "path"
+			param.Required = true
+			found[param.Name] = true
+		}
+		params = append(params, param)
+	}
+
+	for name := range inPath {
+		if !found[name] {
+			return fmt.Errorf("path parameter %q for %q has no field", name, path)
+		}
+	}
+
+	if r.parameters == nil {
+		r.parameters = map[string][]*ParameterObject{}
+	}
+	r.parameters[path] = params
+
+	return nil
+}
+
+// parameterSchema returns the schema of a parameter, nil if the type cannot be a parameter.
+func (r *OpenAPIRenderer) parameterSchema(t *types.TypeNode) *SimpleSchemaObject {
+	out := &SimpleSchemaObject{}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		out.Type = "boolean"
+	case generictype.Integer.String():
+		out.Type = "integer"
+	case generictype.Float.String():
+		out.Type = "number"
+	case generictype.String.String(), generictype.DateTime.String():
+		out.Type = "string"
+	case generictype.Duration.String():
+		if r.Options.DurationAsString {
+			out.Type = "string"
+		} else {
+			out.Type = "integer"
+			out.Format = "int64"
+		}
+	default:
+		return nil
+	}
+
+	if val, ok := r.Options.ResolveOption(t, renderer.FORMAT_OPTION); ok {
+		out.Format = val
+	}
+
+	return out
+}
+
+// parameterLines returns a parameters block for an operation.
+// - Parameters for the path with method take precedence over parameters for the path.
+func (r *OpenAPIRenderer) parameterLines(urlPath, method string) []string {
+	params, ok := r.parameters[types.OperationKey(urlPath, method)]
+	if !ok {
+		params = r.parameters[urlPath]
+	}
+	if len(params) == 0 {
+		return []string{}
+	}
+
+	out := []string{r.Prefix() + "parameters:"}
+	for _, param := range params {
+		r.SetIndent(r.Indent() + 1)
+
+		out = append(out,
+			r.itemPrefix(r.Indent())+"name: "+quote(param.Name),
+			r.Prefix()+"in: "+param.In,
+		)
+		if param.Description != "" {
+			out = append(out, r.Prefix()+"description: "+quote(param.Description))
+		}
+		if param.Required {
+			out = append(out, r.Prefix()+"required: true")
+		}
+		if param.Deprecated {
+			out = append(out, r.Prefix()+"deprecated: true")
+		}
+		out = append(out, r.Prefix()+"schema:")
+
+		r.SetIndent(r.Indent() + 1)
+		out = append(out, r.Prefix()+"type: "+param.Schema.Type)
+		if param.Schema.Format != "" {
+			out = append(out, r.Prefix()+"format: "+param.Schema.Format)
+		}
+		r.SetIndent(r.Indent() - 2)
+	}
+	return out
+}
@@ -1,5 +1,22 @@
 package renderer
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+)
+
+// EXTENSION_PREFIX is the key prefix of vendor extension options, e.g. `b9schema:"x-go-type=uuid.UUID"`
+const EXTENSION_PREFIX = "x-"
+
+// FORMAT_OPTION is the element option that refines a generic type, e.g. `b9schema:"format=email"`
+// - The native default Format (e.g. from Reflector.RegisterKnownType) is the registered value of this option.
+const FORMAT_OPTION = "format"
+
 type Options struct {
 	// DeReference converts TypeRef to their included types.
 	// - If TyepRefs have a cyclical relationship, the last TypeRef is kept as a TypeRef.
@@ -18,11 +35,243 @@ type Options struct {
 
 	// Indent is used for rendering where indent matters.
 	Indent int
+
+	// JSONPointer renders paths as RFC 6901 JSON Pointers (e.g. "/MapOK/MapVal/Key1") instead of dotted paths.
+	// - May be overridden or ignored by renderers.
+	JSONPointer bool
+
+	// PreserveOrder renders children in their stored order (e.g. struct field order) instead of alphabetically.
+	PreserveOrder bool
+
+	// ExcludeErrored skips elements with errors (e.g. unsupported channels and funcs) and their children.
+	// - Only the valid part of a schema is rendered.
+	ExcludeErrored bool
+
+	// DurationAsString renders durations as strings instead of integer nanoseconds.
+	// - May be overridden or ignored by renderers.
+	DurationAsString bool
+
+	// InferFormats guesses string formats from field names, e.g. "Email" is an email and "WebsiteURL" is a uri.
+	// - Explicit formats (e.g. `b9schema:"format=email"`) take precedence.
+	// - May be overridden or ignored by renderers.
+	InferFormats bool
+
+	// NamePrefix and NameSuffix are added to generated schema names and references, see SchemaName.
+	// - e.g. prefix "v2" turns "User" into "V2User" so that schemas of several services can be combined.
+	// - May be overridden or ignored by renderers.
+	NamePrefix string
+	NameSuffix string
+
+	// EmitExtensions renders options with EXTENSION_PREFIX keys as vendor extensions, see ResolveExtensions.
+	// - May be overridden or ignored by renderers.
+	EmitExtensions bool
+
+	// OptionDefaults are global defaults for element options (e.g. "format").
+	// - Keys may be an option name ("format") or a generic type and option name ("string.format").
+	// - See ResolveOption for precedence.
+	OptionDefaults map[string]string
 }
 
 func NewOptions() *Options {
 	opt := &Options{
-		Dialects: []string{},
+		Dialects:       []string{},
+		OptionDefaults: map[string]string{},
 	}
 	return opt
 }
+
+// NativeType returns the native type of an element from Dialects in order, then defaultDialect.
+// - e.g. Dialects ["yaml"] uses yaml names and falls back to json names for a renderer with the "json" default.
+func (opt *Options) NativeType(t *types.TypeNode, defaultDialect string) *types.NativeType {
+	if opt == nil {
+		return t.GetNativeType(defaultDialect)
+	}
+
+	dialects := append([]string{}, opt.Dialects...)
+	return t.ResolveNativeType(append(dialects, defaultDialect)...)
+}
+
+// SchemaName returns a schema name with NamePrefix and NameSuffix.
+// - The name is capitalized if NamePrefix is set so that it stays an exported name, e.g. "v2" + "User" --> "V2User"
+// - Names are unchanged if NamePrefix and NameSuffix are empty.
+func (opt *Options) SchemaName(name string) string {
+	if opt == nil || name == "" || (opt.NamePrefix == "" && opt.NameSuffix == "") {
+		return name
+	}
+	if opt.NamePrefix != "" {
+		name = util.Capitalize(opt.NamePrefix + name)
+	}
+	return name + opt.NameSuffix
+}
+
+// IndentPrefix returns Prefix, or defaultPrefix if Prefix is empty.
+// - Renderers keep their own default (e.g. a tab for Go source) so that shared Options are not changed.
+func (opt *Options) IndentPrefix(defaultPrefix string) string {
+	if opt == nil || opt.Prefix == "" {
+		return defaultPrefix
+	}
+	return opt.Prefix
+}
+
+// ResolveOption returns the value of an element option and true if the option was found.
+// Options are resolved in order of precedence:
+// - Struct tag options in the TAG_DIALECT, e.g. `b9schema:"format=email"`
+// - Registered options in the element's native default dialect (set by the reflector), and its Format for FORMAT_OPTION.
+// - Global defaults in OptionDefaults, "<type>.<key>" before "<key>".
+func (opt *Options) ResolveOption(t *types.TypeNode, key string) (string, bool) {
+	if tagNative := t.Native[types.TAG_DIALECT]; tagNative != nil {
+		if val, ok := tagNative.Options.Lookup(key); ok {
+			return val, true
+		}
+	}
+
+	if defaultNative := t.NativeDefault(); defaultNative != nil {
+		if val, ok := defaultNative.Options.Lookup(key); ok {
+			return val, true
+		}
+		if key == FORMAT_OPTION && defaultNative.Format != "" {
+			return defaultNative.Format, true
+		}
+	}
+
+	if opt != nil {
+		if val, ok := opt.OptionDefaults[t.Type+"."+key]; ok {
+			return val, true
+		}
+		if val, ok := opt.OptionDefaults[key]; ok {
+			return val, true
+		}
+	}
+
+	return "", false
+}
+
+// ResolveList returns the values of a list option using the same precedence as ResolveOption.
+// - Returns nil if the option is not found.
+func (opt *Options) ResolveList(t *types.TypeNode, key string) []string {
+	val, ok := opt.ResolveOption(t, key)
+	if !ok {
+		return nil
+	}
+	return types.SplitList(val)
+}
+
+// ResolveExtensions returns the vendor extension options of an element with sorted keys.
+// - Keys start with EXTENSION_PREFIX and values use the same precedence as ResolveOption.
+// - Options without a value are true, e.g. `b9schema:"x-internal"`
+// - Returns an empty NativeOption if EmitExtensions is not set.
+func (opt *Options) ResolveExtensions(t *types.TypeNode) types.NativeOption {
+	out := types.NewNativeOption()
+	if opt == nil || !opt.EmitExtensions {
+		return out
+	}
+
+	keySet := map[string]bool{}
+	for _, native := range []*types.NativeType{t.Native[types.TAG_DIALECT], t.NativeDefault()} {
+		if native != nil {
+			for _, key := range native.Options.Keys() {
+				keySet[key] = true
+			}
+		}
+	}
+	for key := range opt.OptionDefaults {
+		keySet[strings.TrimPrefix(key, t.Type+".")] = true
+	}
+
+	keys := []string{}
+	for key := range keySet {
+		if strings.HasPrefix(key, EXTENSION_PREFIX) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if val, ok := opt.ResolveOption(t, key); ok {
+			out.AddKeyVal(key, util.ValueIfTrue(val == "", "true", val))
+		}
+	}
+	return out
+}
+
+// WithSettings returns a copy of the options with "key=value" settings applied, e.g. "deref=true" or "indent=4"
+// - Settings are passed to Renderer.ProcessSchema so that a single call can change options without changing shared Options.
+// - Boolean keys: deref, order, native, pointer, durationAsString, excludeErrored, inferFormats, emitExtensions
+// - Boolean keys without a value are true, e.g. "deref"
+// - Other keys: indent (non-negative integer), prefix (non-empty string), namePrefix, nameSuffix (strings), dialects (comma-separated list)
+// - Returns an error for unknown keys and invalid values.
+func (opt *Options) WithSettings(settings ...string) (*Options, error) {
+	if opt == nil {
+		opt = NewOptions()
+	}
+
+	// Copy options so that the original slice and map are not changed.
+	c := *opt
+	c.Dialects = append([]string{}, opt.Dialects...)
+	c.OptionDefaults = map[string]string{}
+	for k, v := range opt.OptionDefaults {
+		c.OptionDefaults[k] = v
+	}
+
+	boolSettings := map[string]*bool{
+		"deref":            &c.DeReference,
+		"order":            &c.PreserveOrder,
+		"native":           &c.IncludeNative,
+		"pointer":          &c.JSONPointer,
+		"durationAsString": &c.DurationAsString,
+		"excludeErrored":   &c.ExcludeErrored,
+		"inferFormats":     &c.InferFormats,
+		"emitExtensions":   &c.EmitExtensions,
+	}
+
+	for _, setting := range settings {
+		key, val := setting, ""
+		hasVal := false
+		if i := strings.Index(setting, "="); i >= 0 {
+			key, val, hasVal = setting[:i], setting[i+1:], true
+		}
+		key = strings.TrimSpace(key)
+
+		if b := boolSettings[key]; b != nil {
+			if !hasVal {
+				*b = true
+				continue
+			}
+			parsed, err := strconv.ParseBool(strings.TrimSpace(val))
+			if err != nil {
+				return nil, fmt.Errorf("setting %q: invalid boolean %q", key, val)
+			}
+			*b = parsed
+			continue
+		}
+
+		switch key {
+		case "indent":
+			n, err := strconv.Atoi(strings.TrimSpace(val))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("setting %q: invalid indent %q", key, val)
+			}
+			c.Indent = n
+		case "prefix":
+			if val == "" {
+				return nil, fmt.Errorf("setting %q: empty prefix", key)
+			}
+			c.Prefix = val
+		case "namePrefix":
+			c.NamePrefix = val
+		case "nameSuffix":
+			c.NameSuffix = val
+		case "dialects":
+			c.Dialects = []string{}
+			for _, dialect := range strings.Split(val, ",") {
+				if dialect = strings.TrimSpace(dialect); dialect != "" {
+					c.Dialects = append(c.Dialects, dialect)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown setting %q", setting)
+		}
+	}
+
+	return &c, nil
+}
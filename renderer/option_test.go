@@ -0,0 +1,284 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/common/types"
+)
+
+func TestOptions_ResolveOption(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tag          map[string]string
+		registration map[string]string
+		nativeFormat string
+		defaults     map[string]string
+		wantVal      string
+		wantOk       bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "global-default",
+			defaults: map[string]string{"format": "global"},
+			wantVal:  "global",
+			wantOk:   true,
+		},
+		{
+			name:     "global-type-default",
+			defaults: map[string]string{"format": "global", "string.format": "string-global"},
+			wantVal:  "string-global",
+			wantOk:   true,
+		},
+		{
+			name:         "registration-over-default",
+			registration: map[string]string{"format": "uuid"},
+			defaults:     map[string]string{"format": "global"},
+			wantVal:      "uuid",
+			wantOk:       true,
+		},
+		{
+			name:         "native-format-over-default",
+			nativeFormat: "uuid",
+			defaults:     map[string]string{"format": "global", "string.format": "string-global"},
+			wantVal:      "uuid",
+			wantOk:       true,
+		},
+		{
+			name:         "tag-over-native-format",
+			tag:          map[string]string{"format": "email"},
+			nativeFormat: "uuid",
+			wantVal:      "email",
+			wantOk:       true,
+		},
+		{
+			name:         "tag-over-registration",
+			tag:          map[string]string{"format": "email"},
+			registration: map[string]string{"format": "uuid"},
+			defaults:     map[string]string{"format": "global"},
+			wantVal:      "email",
+			wantOk:       true,
+		},
+		{
+			name:         "tag-empty-value",
+			tag:          map[string]string{"format": ""},
+			registration: map[string]string{"format": "uuid"},
+			wantVal:      "",
+			wantOk:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			node := types.NewTypeNode("Field", "golang")
+			node.Type = "string"
+
+			node.NativeDefault().Options.UpdateFrom(types.NativeOptionFromMap(test.registration))
+			node.NativeDefault().Format = test.nativeFormat
+			if test.tag != nil {
+				tagNative := types.NewNativeType(types.TAG_DIALECT)
+				tagNative.Options.UpdateFrom(types.NativeOptionFromMap(test.tag))
+				node.Native[types.TAG_DIALECT] = tagNative
+			}
+
+			opt := NewOptions()
+			for k, v := range test.defaults {
+				opt.OptionDefaults[k] = v
+			}
+
+			gotVal, gotOk := opt.ResolveOption(node, "format")
+			if gotVal != test.wantVal || gotOk != test.wantOk {
+				t.Errorf("TEST_FAIL %s: got=%q,%v want=%q,%v", test.name, gotVal, gotOk, test.wantVal, test.wantOk)
+			} else {
+				t.Logf("TEST_OK %s: got=%q,%v", test.name, gotVal, gotOk)
+			}
+		})
+	}
+}
+
+func TestOptions_IsDeprecated(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tag      map[string]string
+		defaults map[string]string
+		want     bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "tag",
+			tag:  map[string]string{DEPRECATED_OPTION: ""},
+			want: true,
+		},
+		{
+			name:     "global-default",
+			defaults: map[string]string{DEPRECATED_OPTION: "true"},
+			want:     true,
+		},
+		{
+			name:     "tag-over-default",
+			tag:      map[string]string{DEPRECATED_OPTION: "false"},
+			defaults: map[string]string{DEPRECATED_OPTION: "true"},
+			want:     false,
+		},
+	}
+
+	for _, test := range testCases {
+		node := types.NewTypeNode("Field", "golang")
+		node.Type = "string"
+		if test.tag != nil {
+			tagNative := types.NewNativeType(types.TAG_DIALECT)
+			tagNative.Options.UpdateFrom(types.NativeOptionFromMap(test.tag))
+			node.Native[types.TAG_DIALECT] = tagNative
+		}
+
+		opt := NewOptions()
+		for k, v := range test.defaults {
+			opt.OptionDefaults[k] = v
+		}
+
+		if got := opt.IsDeprecated(node); got != test.want {
+			t.Errorf("TEST_FAIL %s: got=%t want=%t", test.name, got, test.want)
+		} else {
+			t.Logf("TEST_OK %s: got=%t", test.name, got)
+		}
+	}
+}
+
+func TestOptions_ResolveExtensions(t *testing.T) {
+	node := types.NewTypeNode("Field", "golang")
+	node.Type = "string"
+	node.NativeDefault().Options.UpdateFrom(types.NativeOptionFromMap(map[string]string{"x-order": "2", "format": "uuid"}))
+
+	tagNative := types.NewNativeType(types.TAG_DIALECT)
+	tagNative.Options.UpdateFrom(types.NativeOptionFromMap(map[string]string{"x-order": "1", "x-internal": ""}))
+	node.Native[types.TAG_DIALECT] = tagNative
+
+	opt := NewOptions()
+	opt.OptionDefaults["string.x-go-type"] = "uuid.UUID"
+	opt.OptionDefaults["integer.x-skip"] = "true"
+
+	testCases := []struct {
+		name string
+		emit bool
+		want []string
+	}{
+		{name: "disabled", want: []string{}},
+		{name: "enabled", emit: true, want: []string{"x-go-type=uuid.UUID", "x-internal=true", "x-order=1"}},
+	}
+
+	for _, test := range testCases {
+		opt.EmitExtensions = test.emit
+		got := opt.ResolveExtensions(node)
+
+		gotStr := strings.Join(got.AsOrderedList(), ",")
+		wantStr := strings.Join(test.want, ",")
+		if gotStr != wantStr {
+			t.Errorf("TEST_FAIL %s: got=%s want=%s", test.name, gotStr, wantStr)
+		} else {
+			t.Logf("TEST_OK %s: got=%s", test.name, gotStr)
+		}
+	}
+}
+
+func TestOptions_WithSettings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings []string
+		want     Options
+		wantErr  bool
+	}{
+		{
+			name: "none",
+			want: Options{Prefix: "  "},
+		},
+		{
+			name:     "bools",
+			settings: []string{"deref=true", "order", "excludeErrored=1", "inferFormats=false", "emitExtensions"},
+			want:     Options{Prefix: "  ", DeReference: true, PreserveOrder: true, ExcludeErrored: true, EmitExtensions: true},
+		},
+		{
+			name:     "values",
+			settings: []string{"indent=4", "prefix=\t", "dialects=yaml, json", "namePrefix=v2", "nameSuffix=Type"},
+			want:     Options{Prefix: "\t", Indent: 4, Dialects: []string{"yaml", "json"}, NamePrefix: "v2", NameSuffix: "Type"},
+		},
+		{
+			name:     "bad-bool",
+			settings: []string{"deref=yes"},
+			wantErr:  true,
+		},
+		{
+			name:     "bad-indent",
+			settings: []string{"indent=-1"},
+			wantErr:  true,
+		},
+		{
+			name:     "empty-prefix",
+			settings: []string{"prefix="},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown",
+			settings: []string{"color=blue"},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		opt := NewOptions()
+		opt.Prefix = "  "
+		opt.OptionDefaults["format"] = "email"
+
+		got, err := opt.WithSettings(test.settings...)
+		if (err != nil) != test.wantErr {
+			t.Errorf("TEST_FAIL %s: err=%v wantErr=%t", test.name, err, test.wantErr)
+			continue
+		}
+
+		// Original options are never changed.
+		if opt.DeReference || opt.Indent != 0 || opt.Prefix != "  " || len(opt.Dialects) != 0 {
+			t.Errorf("TEST_FAIL %s: original options changed: %+v", test.name, *opt)
+		}
+		if err != nil {
+			continue
+		}
+
+		gotStr := fmt.Sprintf("%t,%t,%t,%t,%t,%d,%q,%q,%q,%q,%q", got.DeReference, got.PreserveOrder, got.ExcludeErrored, got.InferFormats, got.EmitExtensions,
+			got.Indent, got.Prefix, strings.Join(got.Dialects, "|"), got.NamePrefix, got.NameSuffix, got.OptionDefaults["format"])
+		wantStr := fmt.Sprintf("%t,%t,%t,%t,%t,%d,%q,%q,%q,%q,%q", test.want.DeReference, test.want.PreserveOrder, test.want.ExcludeErrored, test.want.InferFormats, test.want.EmitExtensions,
+			test.want.Indent, test.want.Prefix, strings.Join(test.want.Dialects, "|"), test.want.NamePrefix, test.want.NameSuffix, "email")
+		if gotStr != wantStr {
+			t.Errorf("TEST_FAIL %s: got=%s want=%s", test.name, gotStr, wantStr)
+		} else {
+			t.Logf("TEST_OK %s: got=%s", test.name, gotStr)
+		}
+	}
+}
+
+func TestOptions_SchemaName(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opt    *Options
+		schema string
+		want   string
+	}{
+		{name: "nil", schema: "User", want: "User"},
+		{name: "none", opt: &Options{}, schema: "user", want: "user"},
+		{name: "prefix", opt: &Options{NamePrefix: "v2"}, schema: "User", want: "V2User"},
+		{name: "suffix", opt: &Options{NameSuffix: "V2"}, schema: "User", want: "UserV2"},
+		{name: "both", opt: &Options{NamePrefix: "api", NameSuffix: "Model"}, schema: "User", want: "ApiUserModel"},
+		{name: "empty", opt: &Options{NamePrefix: "v2"}, schema: "", want: ""},
+	}
+
+	for _, test := range testCases {
+		if got := test.opt.SchemaName(test.schema); got != test.want {
+			t.Errorf("TEST_FAIL %s: got=%q want=%q", test.name, got, test.want)
+		} else {
+			t.Logf("TEST_OK %s: got=%q", test.name, got)
+		}
+	}
+}
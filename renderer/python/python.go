@@ -0,0 +1,306 @@
+package python
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// identifierRegexp matches names that can be used as Python attribute names.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// keywords are Python keywords that cannot be used as attribute names.
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// PythonRenderer renders TypeRef structs as Python dataclasses or Pydantic models.
+// - Each TypeRef struct becomes a class, other TypeRefs become type aliases, e.g. "Status = str"
+// - Root elements are not rendered.
+// - References to names that are not declared yet (e.g. cycles) are quoted forward references.
+// - Optional fields (see TypeNode.IsOptional) are rendered as "Optional[T] = None" after required fields.
+// - Pydantic fields with a json name that is not a Python identifier have a Field alias.
+// - Imports are collected while rendering and emitted at the top.
+type PythonRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+
+	// Pydantic renders Pydantic BaseModel subclasses instead of dataclasses.
+	Pydantic bool
+
+	// declared stores names of classes that have been rendered.
+	declared map[string]bool
+
+	// typingImports stores names imported from the typing module.
+	typingImports map[string]bool
+
+	// datetimeImport is true if datetime is used.
+	datetimeImport bool
+
+	// fieldImport is true if pydantic.Field is used.
+	fieldImport bool
+}
+
+func NewPythonRenderer(opt *renderer.Options) *PythonRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &PythonRenderer{opt: opt, defaultPrefix: "    "}
+}
+
+// ProcessSchema renders classes for TypeRef structs with imports.
+func (r *PythonRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	r.declared = map[string]bool{}
+	r.typingImports = map[string]bool{}
+	r.datetimeImport = false
+	r.fieldImport = false
+
+	// Aliases are declared before classes so that fields use them without quotes.
+	classes := []string{}
+	for _, t := range renderer.Children(schema.TypeRef, r) {
+		if t.Type != generictype.Struct.String() && !renderer.IsErrorExcluded(t, r) {
+			classes = append(classes, r.alias(t)...)
+		}
+	}
+	for _, line := range renderer.RenderType(schema.TypeRef, r) {
+		if line != "" {
+			classes = append(classes, line)
+		}
+	}
+	if len(classes) == 0 {
+		return []string{}, nil
+	}
+
+	out := []string{}
+	if r.Pydantic {
+		out = append(out, "from pydantic import BaseModel"+util.ValueIfTrue(r.fieldImport, ", Field", ""))
+	} else {
+		out = append(out, "from dataclasses import dataclass")
+	}
+	if r.datetimeImport {
+		out = append(out, "from datetime import datetime")
+	}
+	if len(r.typingImports) > 0 {
+		names := []string{}
+		for name := range r.typingImports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out = append(out, "from typing import "+strings.Join(names, ", "))
+	}
+
+	return append(out, classes...), nil
+}
+
+func (r *PythonRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *PythonRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *PythonRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *PythonRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *PythonRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *PythonRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *PythonRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+// Pre renders a complete class for each TypeRef struct.
+// - Fields are rendered here so that required fields can be placed before optional fields.
+// - Other TypeRefs are aliases that are rendered before classes, see alias.
+func (r *PythonRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Name != types.TYPEREF_NAME || t.Type != generictype.Struct.String() {
+		return []string{}
+	}
+
+	name := util.ToIdentifier(t.Name)
+
+	out := []string{}
+	if t.Error != "" {
+		out = append(out, "# ERROR: "+t.Error)
+	}
+	if r.Pydantic {
+		out = append(out, fmt.Sprintf("class %s(BaseModel):", name))
+	} else {
+		out = append(out, "@dataclass", fmt.Sprintf("class %s:", name))
+	}
+
+	// Build required and optional fields.
+	r.SetIndent(r.Indent() + 1)
+	required := []string{}
+	optional := []string{}
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		fieldName := native.Name
+		comment := ""
+		alias := ""
+		if !identifierRegexp.MatchString(fieldName) || keywords[fieldName] {
+			fieldName = util.ToIdentifier(fieldName)
+			if fieldName == "" || keywords[fieldName] {
+				fieldName = "_" + fieldName
+			}
+			if r.Pydantic {
+				// Pydantic reads and writes the json name with an alias.
+				alias = fmt.Sprintf("alias=%q", native.Name)
+				r.fieldImport = true
+			} else {
+				comment = fmt.Sprintf("  # json: %q", native.Name)
+			}
+		}
+		if child.Error != "" {
+			comment += "  # ERROR: " + child.Error
+		}
+
+		fieldType := r.typeExpr(child)
+		if child.IsOptional() {
+			r.typingImports["Optional"] = true
+			defaultVal := "None"
+			if alias != "" {
+				defaultVal = fmt.Sprintf("Field(None, %s)", alias)
+			}
+			optional = append(optional, fmt.Sprintf("%s%s: Optional[%s] = %s%s", r.Prefix(), fieldName, fieldType, defaultVal, comment))
+		} else {
+			defaultVal := ""
+			if alias != "" {
+				defaultVal = fmt.Sprintf(" = Field(%s)", alias)
+			}
+			required = append(required, fmt.Sprintf("%s%s: %s%s%s", r.Prefix(), fieldName, fieldType, defaultVal, comment))
+		}
+	}
+
+	if len(required)+len(optional) == 0 {
+		out = append(out, r.Prefix()+"pass")
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	out = append(out, required...)
+	out = append(out, optional...)
+
+	// Declare after rendering so that self-references are quoted.
+	r.declared[name] = true
+
+	return out
+}
+
+// alias renders a type alias for a TypeRef that is not a struct, e.g. "type Status string" --> "Status = str"
+func (r *PythonRenderer) alias(t *types.TypeNode) []string {
+	name := util.ToIdentifier(t.Name)
+
+	out := []string{}
+	if t.Error != "" {
+		out = append(out, "# ERROR: "+t.Error)
+	}
+	out = append(out, fmt.Sprintf("%s = %s", name, r.typeExpr(t)))
+	r.declared[name] = true
+	return out
+}
+
+func (r *PythonRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *PythonRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *PythonRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *PythonRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// typeExpr returns the Python type annotation for an element.
+// - References are always rendered by class or alias name and quoted if the name is not declared yet.
+// - Anonymous structs and unknown types are rendered as Dict[str, Any] and Any.
+func (r *PythonRenderer) typeExpr(t *types.TypeNode) string {
+	if t.TypeRef != "" {
+		name := util.ToIdentifier(t.TypeRef)
+		if !r.declared[name] {
+			return fmt.Sprintf("%q", name)
+		}
+		return name
+	}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		return "bool"
+	case generictype.Integer.String():
+		return "int"
+	case generictype.Float.String():
+		return "float"
+	case generictype.String.String():
+		return "str"
+	case generictype.DateTime.String():
+		r.datetimeImport = true
+		return "datetime"
+	case generictype.Duration.String():
+		return util.ValueIfTrue(r.opt.DurationAsString, "str", "int")
+	case generictype.Struct.String():
+		r.typingImports["Any"] = true
+		r.typingImports["Dict"] = true
+		return "Dict[str, Any]"
+	case generictype.List.String():
+		r.typingImports["List"] = true
+		return fmt.Sprintf("List[%s]", r.itemExpr(t))
+	case generictype.Map.String():
+		r.typingImports["Dict"] = true
+		return fmt.Sprintf("Dict[str, %s]", r.itemExpr(t))
+	}
+
+	// Invalid and unknown types.
+	r.typingImports["Any"] = true
+	return "Any"
+}
+
+// itemExpr returns the type annotation for list items and map values.
+func (r *PythonRenderer) itemExpr(t *types.TypeNode) string {
+	if len(t.Children) == 0 {
+		r.typingImports["Any"] = true
+		return "Any"
+	}
+
+	item := t.Children[0]
+	expr := r.typeExpr(item)
+	if item.Nullable {
+		r.typingImports["Optional"] = true
+		expr = fmt.Sprintf("Optional[%s]", expr)
+	}
+	return expr
+}
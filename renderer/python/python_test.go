@@ -0,0 +1,158 @@
+package python
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type BasicStruct struct {
+	BoolVal    bool
+	IntVal     int
+	Float64Val float64
+	StringVal  string
+}
+
+type OuterStruct struct {
+	ID    int          `json:"id"`
+	Inner *InnerStruct `json:"inner"`
+}
+
+type InnerStruct struct {
+	ListOfStrings []string       `json:"listOfStrings"`
+	ListOfStructs []*BasicStruct `json:"listOfStructs"`
+}
+
+type FieldStruct struct {
+	TimeVal   time.Time         `json:"time-val"`
+	Class     string            `json:"class"`
+	Ignored   string            `json:"-"`
+	MapVal    map[string]int    `json:"mapVal,omitempty"`
+	Anonymous struct{ Key int } `json:"anonymous"`
+	Self      *FieldStruct      `json:"self"`
+}
+
+// Named types that are not structs are type aliases.
+type Status string
+type Tags []string
+
+type AliasStruct struct {
+	State Status `json:"state"`
+	Tags  Tags   `json:"tags"`
+}
+
+func TestPythonRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		pydantic bool
+		want     []string
+	}{
+		{
+			name:  "dataclass",
+			value: OuterStruct{},
+			want: []string{
+				`from dataclasses import dataclass`,
+				`from typing import List, Optional`,
+				`@dataclass`,
+				`class BasicStruct:`,
+				`    BoolVal: bool`,
+				`    Float64Val: float`,
+				`    IntVal: int`,
+				`    StringVal: str`,
+				`@dataclass`,
+				`class InnerStruct:`,
+				`    listOfStrings: List[str]`,
+				`    listOfStructs: List[Optional[BasicStruct]]`,
+				`@dataclass`,
+				`class OuterStruct:`,
+				`    id: int`,
+				`    inner: Optional[InnerStruct] = None`,
+			},
+		},
+		{
+			name:     "pydantic",
+			value:    OuterStruct{},
+			pydantic: true,
+			want: []string{
+				`from pydantic import BaseModel`,
+				`from typing import List, Optional`,
+				`class BasicStruct(BaseModel):`,
+				`    BoolVal: bool`,
+				`    Float64Val: float`,
+				`    IntVal: int`,
+				`    StringVal: str`,
+				`class InnerStruct(BaseModel):`,
+				`    listOfStrings: List[str]`,
+				`    listOfStructs: List[Optional[BasicStruct]]`,
+				`class OuterStruct(BaseModel):`,
+				`    id: int`,
+				`    inner: Optional[InnerStruct] = None`,
+			},
+		},
+		{
+			name:  "fields",
+			value: FieldStruct{},
+			want: []string{
+				`from dataclasses import dataclass`,
+				`from datetime import datetime`,
+				`from typing import Any, Dict, Optional`,
+				`@dataclass`,
+				`class FieldStruct:`,
+				`    anonymous: Dict[str, Any]`,
+				`    Class: str  # json: "class"`,
+				`    TimeVal: datetime  # json: "time-val"`,
+				`    mapVal: Optional[Dict[str, int]] = None`,
+				`    self: Optional["FieldStruct"] = None`,
+			},
+		},
+		{
+			name:     "pydantic-fields",
+			value:    FieldStruct{},
+			pydantic: true,
+			want: []string{
+				`from pydantic import BaseModel, Field`,
+				`from datetime import datetime`,
+				`from typing import Any, Dict, Optional`,
+				`class FieldStruct(BaseModel):`,
+				`    anonymous: Dict[str, Any]`,
+				`    Class: str = Field(alias="class")`,
+				`    TimeVal: datetime = Field(alias="time-val")`,
+				`    mapVal: Optional[Dict[str, int]] = None`,
+				`    self: Optional["FieldStruct"] = None`,
+			},
+		},
+		{
+			name:  "aliases",
+			value: AliasStruct{},
+			want: []string{
+				`from dataclasses import dataclass`,
+				`from typing import List`,
+				`Status = str`,
+				`Tags = List[str]`,
+				`@dataclass`,
+				`class AliasStruct:`,
+				`    state: Status`,
+				`    tags: Tags`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			schema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+			r := NewPythonRenderer(renderer.NewOptions())
+			r.Pydantic = test.pydantic
+
+			got, err := r.ProcessSchema(schema)
+			if err != nil {
+				t.Fatalf("TEST_FAIL %s: err=%s", test.name, err)
+			}
+			util.CompareStrings(t, test.name, got, test.want)
+		})
+	}
+}
@@ -3,9 +3,10 @@ package simple
 import (
 	"fmt"
 	"github.com/gitmann/b9schema-golang/common/enum/generictype"
-	"github.com/gitmann/b9schema-golang/common/enum/typecategory"
 	"github.com/gitmann/b9schema-golang/common/types"
 	"github.com/gitmann/b9schema-golang/renderer"
+	"io"
+	"sort"
 	"strings"
 )
 
@@ -22,16 +23,44 @@ func NewSimpleRenderer(opt *renderer.Options) *SimpleRenderer {
 	return &SimpleRenderer{opt: opt}
 }
 
+// ProcessSchema renders a schema with settings that change options for this call only, see renderer.Options.WithSettings.
 func (r *SimpleRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
-	// Header
+	if len(settings) > 0 {
+		opt, err := r.opt.WithSettings(settings...)
+		if err != nil {
+			return []string{}, err
+		}
+		return NewSimpleRenderer(opt).ProcessSchema(schema)
+	}
+
 	return renderer.RenderSchema(schema, r), nil
-	// Footer
+}
+
+// ProcessSchemaTo writes lines to w as they are rendered, see renderer.WriterRenderer.
+func (r *SimpleRenderer) ProcessSchemaTo(w io.Writer, schema *types.Schema, settings ...string) error {
+	if len(settings) > 0 {
+		opt, err := r.opt.WithSettings(settings...)
+		if err != nil {
+			return err
+		}
+		return NewSimpleRenderer(opt).ProcessSchemaTo(w, schema)
+	}
+
+	return renderer.RenderSchemaTo(w, schema, r)
 }
 
 func (r *SimpleRenderer) DeReference() bool {
 	return r.opt.DeReference
 }
 
+func (r *SimpleRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *SimpleRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
 func (r *SimpleRenderer) Indent() int {
 	return r.opt.Indent
 }
@@ -48,7 +77,7 @@ func (r *SimpleRenderer) Prefix() string {
 }
 
 func (r *SimpleRenderer) NativeType(t *types.TypeNode) *types.NativeType {
-	return t.GetNativeType("")
+	return r.opt.NativeType(t, "")
 }
 
 func (r *SimpleRenderer) Pre(t *types.TypeNode) []string {
@@ -56,72 +85,85 @@ func (r *SimpleRenderer) Pre(t *types.TypeNode) []string {
 		return []string{}
 	}
 
-	path := r.Path(t)
-	out := strings.Join(path, ".")
+	// JSON Pointers are quoted so that the empty root pointer is visible.
+	var out string
+	if r.opt.JSONPointer {
+		out = fmt.Sprintf("%q", renderer.JSONPointer(t))
+	} else {
+		out = strings.Join(r.Path(t), ".")
+	}
 
 	if t.Error != "" {
 		out += " ERROR:" + t.Error
 	}
 
+	if r.opt.IsDeprecated(t) {
+		out += " DEPRECATED"
+	}
+
+	if r.opt.IncludeNative {
+		out += nativeDetails(t)
+	}
+
 	return []string{out}
 }
 
+func (r *SimpleRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *SimpleRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
 func (r *SimpleRenderer) Post(t *types.TypeNode) []string {
 	return []string{}
 }
 
 // Path is a function that builds a path string from a TypeNode.
-// Format is: [<Name>:]<Type>[:<TypeRef>]
-// - If Name is set, prefix with "Name", otherwise "-"
-// - If TypeRef is set, suffix with "TypeRef", otherwise "-"
-// - If Error is set, wrap entire string with "!"
+// - See TypeNode.PathSegment for the format of each element.
+// - TypeRef is omitted if de-referencing, except for cyclical references.
 func (r *SimpleRenderer) Path(t *types.TypeNode) []string {
 	if t.Parent == nil {
 		// Root element. Start a new path.
 		return []string{t.Name}
 	}
 
-	namePart := t.Name
-	if namePart != "" {
-		namePart += ":"
-	}
+	withTypeRef := !r.DeReference() || t.Error == types.CyclicalReferenceErr
 
-	// Type.
-	var typePart, typeCategory string
-	if gt := generictype.FromType(t.Type); gt != nil {
-		typeCategory = gt.Category().String()
-	}
-
-	if typeCategory == typecategory.Invalid.String() {
-		typePart = t.Type
-	} else {
-		typePart = generictype.PathDefaultOfType(t.Type)
-	}
-
-	// Add TypeRef suffix if set but not if de-referencing.
-	refPart := ""
-	if !r.DeReference() {
-		refPart = t.NativeDefault().TypeRef
-	} else if r.DeReference() && t.Error == types.CyclicalReferenceErr {
-		// Keep reference if it's a cyclical error.
-		refPart = t.NativeDefault().TypeRef
-	}
-	if refPart != "" {
-		refPart = ":" + refPart
-	}
-
-	// Build path.
-	path := namePart + typePart + refPart
+	return append(r.Path(t.Parent), t.PathSegmentNative(r.NativeType(t), withTypeRef))
+}
 
-	// Wrap type in "!" if current element is an error.
-	if t.Error != "" {
-		path = fmt.Sprintf("!%s!", path)
+// nativeDetails returns the native details of all dialects, e.g. " [golang:Kind=int;Type.Name=int]"
+// - Dialects and keys are sorted so that output is stable.
+// - Options are listed by their "key=value" strings.
+func nativeDetails(t *types.TypeNode) string {
+	dialects := make([]string, 0, len(t.Native))
+	for dialect := range t.Native {
+		dialects = append(dialects, dialect)
 	}
-
-	// Add quotes if path contains "."
-	if strings.Contains(path, ".") {
-		path = fmt.Sprintf("%q", path)
+	sort.Strings(dialects)
+
+	out := ""
+	for _, dialect := range dialects {
+		m := t.Native[dialect].AsMap()
+
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		details := make([]string, len(keys))
+		for i, k := range keys {
+			if strings.HasPrefix(k, "Options[") {
+				details[i] = m[k]
+			} else {
+				details[i] = k + "=" + m[k]
+			}
+		}
+
+		out += fmt.Sprintf(" [%s:%s]", dialect, strings.Join(details, ";"))
 	}
-
-	return append(r.Path(t.Parent), path)
+	return out
 }
@@ -0,0 +1,223 @@
+package simple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type PathInner struct {
+	Value string
+}
+
+type PathOuter struct {
+	Inner     PathInner
+	InnerPtr  *PathInner
+	InnerList []PathInner
+	Anon      struct {
+		Deep struct {
+			Key string
+		}
+	}
+}
+
+// findNode follows child names from the given node.
+// - Empty name matches the first child (e.g. list elements).
+func findNode(t *types.TypeNode, names ...string) *types.TypeNode {
+	for _, name := range names {
+		if t == nil {
+			return nil
+		}
+
+		if name == "" && len(t.Children) > 0 {
+			t = t.Children[0]
+		} else {
+			t = t.ChildByName(name, nil)
+		}
+	}
+	return t
+}
+
+func TestSimpleRenderer_Path(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(PathOuter{}, "path-test")
+	root := schema.Root.Children[0]
+
+	testCases := []struct {
+		name  string
+		node  *types.TypeNode
+		deref bool
+		want  string
+	}{
+		{
+			name: "root",
+			node: root,
+			want: `Root.{}:PathOuter`,
+		},
+		{
+			name:  "root-deref",
+			node:  root,
+			deref: true,
+			want:  `Root.{}`,
+		},
+		{
+			name: "nested-struct",
+			node: findNode(root, "Inner", "Value"),
+			want: `Root.{}:PathOuter.Inner:{}:PathInner.Value:string`,
+		},
+		{
+			name: "nested-pointer",
+			node: findNode(root, "InnerPtr", "Value"),
+			want: `Root.{}:PathOuter.InnerPtr:{}:PathInner.Value:string`,
+		},
+		{
+			name: "nested-list",
+			node: findNode(root, "InnerList", "", "Value"),
+			want: `Root.{}:PathOuter.InnerList:[].{}:PathInner.Value:string`,
+		},
+		{
+			name:  "nested-anonymous",
+			node:  findNode(root, "Anon", "Deep", "Key"),
+			deref: true,
+			want:  `Root.{}.Anon:{}.Deep:{}.Key:string`,
+		},
+		{
+			name: "typeref",
+			node: findNode(schema.TypeRef, "PathInner", "Value"),
+			want: `TypeRef.PathInner:{}.Value:string`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if test.node == nil {
+				t.Fatalf("TEST_FAIL %s: node not found", test.name)
+			}
+
+			opt := renderer.NewOptions()
+			opt.DeReference = test.deref
+			r := NewSimpleRenderer(opt)
+
+			got := strings.Join(r.Path(test.node), ".")
+			if got != test.want {
+				t.Errorf("TEST_FAIL %s: got=%q want=%q", test.name, got, test.want)
+			} else {
+				t.Logf("TEST_OK %s: got=%q", test.name, got)
+			}
+		})
+	}
+}
+
+type NativeTest struct {
+	IntVal int    `json:"intVal,omitempty"`
+	Hidden string `json:"-"`
+}
+
+func TestSimpleRenderer_IncludeNative(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(NativeTest{}, "native-test")
+
+	render := func(includeNative bool) []string {
+		opt := renderer.NewOptions()
+		opt.IncludeNative = includeNative
+		got, err := NewSimpleRenderer(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Fatalf("TEST_FAIL ProcessSchema: err=%s", err)
+		}
+		return got
+	}
+
+	// Default output has no native details.
+	for _, line := range render(false) {
+		if strings.Contains(line, " [") {
+			t.Errorf("TEST_FAIL default: unexpected native details in %q", line)
+		}
+	}
+
+	got := strings.Join(render(true), "\n")
+	t.Logf("got:\n%s", got)
+	for _, want := range []string{
+		`Root.{}:NativeTest [golang:Include=true;IsNil=undefined;IsValid=true;IsZero=true;Kind=struct;`,
+		`TypeRef.NativeTest:{}.IntVal:integer [golang:Format=int64;Include=true;IsNil=undefined;IsValid=true;IsZero=true;Kind=int;OmitEmpty=true;Type.Kind=int;Type.Name=int;Type=int] [json:Include=true;Name=intVal;omitempty]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("TEST_FAIL include-native: missing %q", want)
+		} else {
+			t.Logf("TEST_OK include-native: found %q", want)
+		}
+	}
+
+	// Output is stable.
+	if again := strings.Join(render(true), "\n"); again != got {
+		t.Errorf("TEST_FAIL include-native: output is not stable")
+	}
+}
+
+type PointerInner struct {
+	Value string `json:"a/b~c"`
+}
+
+type PointerTest struct {
+	Inner PointerInner       `json:"inner"`
+	List  []PointerInner     `json:"list"`
+	Map   map[string]float64 `json:"map"`
+}
+
+func TestSimpleRenderer_JSONPointer(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(PointerTest{}, "pointer-test")
+
+	opt := renderer.NewOptions()
+	opt.JSONPointer = true
+	opt.DeReference = true
+	got, err := NewSimpleRenderer(opt).ProcessSchema(schema)
+	if err != nil {
+		t.Fatalf("TEST_FAIL ProcessSchema: err=%s", err)
+	}
+
+	want := []string{
+		`""`,
+		`"/inner"`,
+		`"/inner/a~1b~0c"`,
+		`"/list"`,
+		`"/list/-"`,
+		`"/list/-/a~1b~0c"`,
+		`"/map"`,
+		`"/map/-"`,
+	}
+	util.CompareStrings(t, "json-pointer", got, want)
+
+	// Default output keeps dotted paths.
+	got, _ = NewSimpleRenderer(nil).ProcessSchema(schema)
+	if len(got) == 0 || !strings.HasPrefix(got[0], "Root.") {
+		t.Errorf("TEST_FAIL json-pointer: default output is not dotted: %v", got)
+	}
+}
+
+// fencedRenderer wraps simple output in a markdown code block.
+type fencedRenderer struct {
+	*SimpleRenderer
+}
+
+func (r *fencedRenderer) Header(schema *types.Schema) []string {
+	return []string{"```"}
+}
+
+func (r *fencedRenderer) Footer(schema *types.Schema) []string {
+	return []string{"```"}
+}
+
+func TestRenderSchema_HeaderFooter(t *testing.T) {
+	schema := reflector.NewReflector().DeriveSchema(PathInner{}, "header-test")
+
+	got := renderer.RenderSchema(schema, &fencedRenderer{NewSimpleRenderer(nil)})
+	want := []string{
+		"```",
+		`Root.{}:PathInner`,
+		`TypeRef.PathInner:{}`,
+		`TypeRef.PathInner:{}.Value:string`,
+		"```",
+	}
+	util.CompareStrings(t, "header-footer", got, want)
+}
@@ -0,0 +1,190 @@
+package sqlddl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// identifierRegexp matches names that can be used as SQL identifiers without quotes.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// nameDialects are native dialects used for column names in order of precedence.
+var nameDialects = []string{"db", "json"}
+
+// SQLDDLRenderer renders a schema as SQL CREATE TABLE statements.
+// - Each TypeRef struct becomes a table.
+// - Columns are built from basic types and datetime, other types are rendered as comments.
+type SQLDDLRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+}
+
+func NewSQLDDLRenderer(opt *renderer.Options) *SQLDDLRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &SQLDDLRenderer{opt: opt, defaultPrefix: "  "}
+}
+
+// ProcessSchema renders tables for TypeRef elements only.
+func (r *SQLDDLRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	out := []string{}
+	for _, line := range renderer.RenderType(schema.TypeRef, r) {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+func (r *SQLDDLRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *SQLDDLRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *SQLDDLRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *SQLDDLRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *SQLDDLRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *SQLDDLRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+// NativeType returns the native type of the first dialect that renames the element.
+func (r *SQLDDLRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	for _, dialect := range nameDialects {
+		if native := t.Native[dialect]; native != nil && native.Name != "" {
+			return t.GetNativeType(dialect)
+		}
+	}
+	return t.GetNativeType("")
+}
+
+// Pre renders a complete table for each TypeRef struct.
+// - Columns are rendered here so that commas can be placed between them.
+func (r *SQLDDLRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Name != types.TYPEREF_NAME {
+		return []string{}
+	}
+
+	if t.Type != generictype.Struct.String() {
+		return []string{fmt.Sprintf("-- unsupported table %s (%s)", t.Name, t.Type)}
+	}
+
+	// Build columns and comments in field order.
+	type line struct {
+		text     string
+		isColumn bool
+	}
+	lines := []line{}
+	lastColumn := -1
+
+	r.SetIndent(r.Indent() + 1)
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		name := quote(native.Name)
+		sqlType := r.columnType(child)
+		if sqlType == "" || child.Error != "" {
+			comment := fmt.Sprintf("%s-- unsupported column %s (%s)", r.Prefix(), name, child.Type)
+			if child.Error != "" {
+				comment += " ERROR: " + child.Error
+			}
+			lines = append(lines, line{text: comment})
+			continue
+		}
+
+		if !child.Nullable {
+			sqlType += " NOT NULL"
+		}
+		lines = append(lines, line{text: fmt.Sprintf("%s%s %s", r.Prefix(), name, sqlType), isColumn: true})
+		lastColumn = len(lines) - 1
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	if lastColumn < 0 {
+		return []string{fmt.Sprintf("-- unsupported table %s (no columns)", t.Name)}
+	}
+
+	out := []string{fmt.Sprintf("CREATE TABLE %s (", quote(t.Name))}
+	for i, l := range lines {
+		if l.isColumn && i < lastColumn {
+			l.text += ","
+		}
+		out = append(out, l.text)
+	}
+	out = append(out, ");")
+
+	return out
+}
+
+func (r *SQLDDLRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *SQLDDLRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *SQLDDLRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *SQLDDLRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// columnType returns the SQL type for an element or empty string if the type is not supported.
+func (r *SQLDDLRenderer) columnType(t *types.TypeNode) string {
+	switch t.Type {
+	case generictype.Boolean.String():
+		return "BOOLEAN"
+	case generictype.Integer.String():
+		switch t.NativeDefault().Type {
+		case "int8", "int16", "int32", "uint8", "uint16":
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case generictype.Duration.String():
+		return "BIGINT"
+	case generictype.Float.String():
+		return "DOUBLE PRECISION"
+	case generictype.String.String():
+		return "TEXT"
+	case generictype.DateTime.String():
+		return "TIMESTAMP"
+	}
+	return ""
+}
+
+// quote returns a name as a SQL identifier.
+func quote(name string) string {
+	if identifierRegexp.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
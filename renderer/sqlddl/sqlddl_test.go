@@ -0,0 +1,81 @@
+package sqlddl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+)
+
+type BasicStruct struct {
+	BoolVal    bool
+	IntVal     int
+	Float64Val float64
+	StringVal  string
+}
+
+type ColumnStruct struct {
+	ID        int32     `db:"id"`
+	UserName  string    `json:"userName"`
+	Email     *string   `db:"email_address" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Ignored   string    `json:"-"`
+	Nested    BasicStruct
+	List      []string
+	Map       map[string]string
+}
+
+func TestSQLDDLRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{
+			name:  "basic",
+			value: BasicStruct{},
+			want: []string{
+				`CREATE TABLE BasicStruct (`,
+				`  BoolVal BOOLEAN NOT NULL,`,
+				`  Float64Val DOUBLE PRECISION NOT NULL,`,
+				`  IntVal BIGINT NOT NULL,`,
+				`  StringVal TEXT NOT NULL`,
+				`);`,
+			},
+		},
+		{
+			name:  "columns",
+			value: ColumnStruct{},
+			want: []string{
+				`CREATE TABLE BasicStruct (`,
+				`  BoolVal BOOLEAN NOT NULL,`,
+				`  Float64Val DOUBLE PRECISION NOT NULL,`,
+				`  IntVal BIGINT NOT NULL,`,
+				`  StringVal TEXT NOT NULL`,
+				`);`,
+				`CREATE TABLE ColumnStruct (`,
+				`  created_at TIMESTAMP NOT NULL,`,
+				`  email_address TEXT,`,
+				`  id INTEGER NOT NULL,`,
+				`  -- unsupported column List (list)`,
+				`  -- unsupported column Map (map)`,
+				`  -- unsupported column Nested (struct)`,
+				`  userName TEXT NOT NULL`,
+				`);`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+		gotStrings, err := NewSQLDDLRenderer(nil).ProcessSchema(schema)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}
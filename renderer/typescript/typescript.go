@@ -0,0 +1,292 @@
+package typescript
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// identifierRegexp matches names that can be used as property names without quotes.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScriptRenderer renders a schema as TypeScript interfaces.
+// - Each TypeRef becomes an exported interface (or type alias for non-struct types).
+// - Root elements are exported only if they are not already exported as a TypeRef.
+type TypeScriptRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+}
+
+func NewTypeScriptRenderer(opt *renderer.Options) *TypeScriptRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &TypeScriptRenderer{opt: opt, defaultPrefix: "  "}
+}
+
+func (r *TypeScriptRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	return renderer.RenderSchema(schema, r), nil
+}
+
+// ProcessSchemaTo writes lines to w as they are rendered, see renderer.WriterRenderer.
+func (r *TypeScriptRenderer) ProcessSchemaTo(w io.Writer, schema *types.Schema, settings ...string) error {
+	return renderer.RenderSchemaTo(w, schema, r)
+}
+
+func (r *TypeScriptRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *TypeScriptRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *TypeScriptRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *TypeScriptRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *TypeScriptRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *TypeScriptRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *TypeScriptRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	jsonType := r.opt.NativeType(t, "json")
+
+	// Union alternatives are rendered as part of their parent's type.
+	if t.Parent != nil && t.Parent.Type == generictype.Union.String() {
+		jsonType.Include = threeflag.False
+	}
+
+	return jsonType
+}
+
+func (r *TypeScriptRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() {
+		return []string{}
+	}
+
+	out := []string{}
+
+	if t.Parent.Type == generictype.Root.String() {
+		// Top-level elements are exported.
+		name := renderer.TopLevelName(t, r)
+		if name == "" {
+			return out
+		}
+
+		out = append(out, r.errorComments(t)...)
+
+		if t.Type == generictype.Struct.String() {
+			out = append(out, fmt.Sprintf("%sexport interface %s {", r.Prefix(), name))
+			r.SetIndent(r.Indent() + 1)
+			return out
+		}
+
+		open, _, inline := r.typeExpr(t)
+		if inline {
+			out = append(out, fmt.Sprintf("%sexport type %s = %s", r.Prefix(), name, open))
+			r.SetIndent(r.Indent() + 1)
+		} else {
+			out = append(out, fmt.Sprintf("%sexport type %s = %s;", r.Prefix(), name, open))
+		}
+		return out
+	}
+
+	// Unnamed elements (list items, map values) are rendered as part of their parent's type.
+	if t.Name == "" {
+		return out
+	}
+
+	name := r.propertyName(t)
+
+	out = append(out, r.errorComments(t)...)
+	if r.opt.IsDeprecated(t) {
+		out = append(out, r.Prefix()+"/** @deprecated */")
+	}
+
+	open, _, inline := r.typeExpr(t)
+	if inline {
+		out = append(out, fmt.Sprintf("%s%s: %s", r.Prefix(), name, open))
+		r.SetIndent(r.Indent() + 1)
+	} else {
+		out = append(out, fmt.Sprintf("%s%s: %s;", r.Prefix(), name, open))
+	}
+
+	return out
+}
+
+func (r *TypeScriptRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *TypeScriptRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *TypeScriptRenderer) Post(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() {
+		return []string{}
+	}
+
+	if t.Parent.Type == generictype.Root.String() {
+		if renderer.TopLevelName(t, r) == "" {
+			return []string{}
+		}
+
+		if t.Type == generictype.Struct.String() {
+			return []string{r.Prefix() + "}"}
+		}
+	} else if t.Name == "" {
+		return []string{}
+	}
+
+	if _, close, inline := r.typeExpr(t); inline {
+		return []string{r.Prefix() + close + ";"}
+	}
+
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *TypeScriptRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// errorComments returns comment lines for errors on an element and its unnamed descendants.
+func (r *TypeScriptRenderer) errorComments(t *types.TypeNode) []string {
+	out := []string{}
+
+	for n := t; n != nil; {
+		if n.Error != "" {
+			out = append(out, fmt.Sprintf("%s// ERROR: %s", r.Prefix(), n.Error))
+		}
+
+		// Continue with unnamed list items and map values.
+		if len(n.Children) == 1 && n.Children[0].Name == "" && (n.TypeRef == "" || r.DeReference()) {
+			n = n.Children[0]
+		} else {
+			n = nil
+		}
+	}
+
+	return out
+}
+
+// typeExpr returns the TypeScript type expression for an element.
+// - If inline is false, open is the complete type expression.
+// - If inline is true, the type contains an object literal whose properties are rendered by children.
+// - For inline types, open is the text before the properties and close is the text after them.
+func (r *TypeScriptRenderer) typeExpr(t *types.TypeNode) (open, close string, inline bool) {
+	// References are rendered by name unless de-referencing.
+	// - Cyclical references are always kept as references.
+	if t.TypeRef != "" {
+		if !r.DeReference() || t.Error == types.CyclicalReferenceErr {
+			return t.TypeRef, "", false
+		}
+	}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		return "boolean", "", false
+	case generictype.Integer.String(), generictype.Float.String():
+		return "number", "", false
+	case generictype.String.String(), generictype.DateTime.String():
+		return "string", "", false
+	case generictype.Duration.String():
+		return util.ValueIfTrue(r.opt.DurationAsString, "string", "number"), "", false
+	case generictype.Struct.String():
+		if len(t.Children) == 0 {
+			return "{}", "", false
+		}
+		return "{", "}", true
+	case generictype.List.String():
+		if len(t.Children) == 0 {
+			return "unknown[]", "", false
+		}
+		open, close, inline = r.typeExpr(t.Children[0])
+		if inline {
+			return open, close + "[]", true
+		}
+		if t.Children[0].Type == generictype.Union.String() && strings.Contains(open, " | ") {
+			open = "(" + open + ")"
+		}
+		return open + "[]", "", false
+	case generictype.Map.String():
+		if len(t.Children) == 0 {
+			return "Record<string, unknown>", "", false
+		}
+		open, close, inline = r.typeExpr(t.Children[0])
+		if inline {
+			return "Record<string, " + open, close + ">", true
+		}
+		return "Record<string, " + open + ">", "", false
+	case generictype.Union.String():
+		if len(t.Children) == 0 {
+			return "unknown", "", false
+		}
+		parts := []string{}
+		for _, child := range renderer.Children(t, r) {
+			parts = append(parts, r.typeString(child))
+		}
+		return strings.Join(parts, " | "), "", false
+	}
+
+	// Invalid and unknown types.
+	return "unknown", "", false
+}
+
+// propertyName returns the property name of an element, quoted if needed and with "?" if optional.
+func (r *TypeScriptRenderer) propertyName(t *types.TypeNode) string {
+	name := r.NativeType(t).Name
+	if !identifierRegexp.MatchString(name) {
+		name = fmt.Sprintf("%q", name)
+	}
+	if t.IsOptional() {
+		name += "?"
+	}
+	return name
+}
+
+// typeString returns the complete type expression of an element on one line.
+// - Object literals include their properties, e.g. "{ radius: number; }"
+// - Used for union alternatives which cannot be rendered by children.
+func (r *TypeScriptRenderer) typeString(t *types.TypeNode) string {
+	open, close, inline := r.typeExpr(t)
+	if !inline {
+		return open
+	}
+
+	// Inline types wrap the object literal of the innermost struct, e.g. list items.
+	obj := t
+	for obj.Type != generictype.Struct.String() && len(obj.Children) > 0 {
+		obj = obj.Children[0]
+	}
+
+	props := []string{}
+	for _, child := range renderer.Children(obj, r) {
+		if r.NativeType(child).Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+		props = append(props, fmt.Sprintf("%s: %s;", r.propertyName(child), r.typeString(child)))
+	}
+
+	return open + " " + strings.Join(props, " ") + " " + close
+}
@@ -1,73 +1,121 @@
 package renderer
 
 import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
 	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
 	"github.com/gitmann/b9schema-golang/common/types"
 	"github.com/gitmann/b9schema-golang/common/util"
 )
 
-// RenderStrings builds a string representation of a type result using the given pre, path, and post functions.
+// RenderSchema builds a string representation of a schema using the renderer's header, pre, post, and footer functions.
+// - Lines are rendered with RenderSchemaTo into a buffer, see RenderSchemaTo for streaming large schemas.
 func RenderSchema(schema *types.Schema, r Renderer) []string {
-	// Build output outLines.
-	out := []string{}
+	buf := &bytes.Buffer{}
+	RenderSchemaTo(buf, schema, r)
+	return bufferLines(buf)
+}
+
+// RenderSchemaTo writes lines of a schema to w using the renderer's header, pre, post, and footer functions.
+// - Each line ends with a newline. Empty lines are skipped.
+// - Lines are written as elements are rendered so that the whole schema is never held in memory.
+// - Returns the first write error. Rendering stops after an error.
+func RenderSchemaTo(w io.Writer, schema *types.Schema, r Renderer) error {
+	lw := &lineWriter{w: w}
+
+	// Print header.
+	lw.writeBlocks(r.Header(schema))
 
 	//	Print types.
 	if len(schema.Root.Children) > 0 {
-		rendered := RenderType(schema.Root, r)
-		for _, r := range rendered {
-			if r != "" {
-				out = append(out, r)
-			}
-		}
+		renderTypeTo(lw, schema.Root, r, map[*types.TypeNode]bool{})
 	}
 
 	// Print type refs.
 	if !r.DeReference() {
 		if len(schema.TypeRef.Children) > 0 {
-			rendered := RenderType(schema.TypeRef, r)
-			for _, r := range rendered {
-				if r != "" {
-					out = append(out, r)
-				}
-			}
+			renderTypeTo(lw, schema.TypeRef, r, map[*types.TypeNode]bool{})
 		}
 	}
 
-	//	Return strings.
-	return out
+	// Print footer.
+	lw.writeBlocks(r.Footer(schema))
+
+	return lw.err
+}
+
+// ProcessSchemaTo writes the result of a renderer's ProcessSchema to w.
+// - Renderers that implement WriterRenderer stream their output.
+// - Other renderers build a slice of lines that is written as is when done.
+func ProcessSchemaTo(w io.Writer, schema *types.Schema, r Renderer, settings ...string) error {
+	if wr, ok := r.(WriterRenderer); ok {
+		return wr.ProcessSchemaTo(w, schema, settings...)
+	}
+
+	lines, err := r.ProcessSchema(schema, settings...)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RenderType builds strings for a TypeNode and its children.
 func RenderType(t *types.TypeNode, r Renderer) []string {
+	buf := &bytes.Buffer{}
+	renderTypeTo(&lineWriter{w: buf}, t, r, map[*types.TypeNode]bool{})
+	return bufferLines(buf)
+}
+
+// renderTypeTo writes lines for a TypeNode and its children.
+// - path holds the elements being rendered, the reflector marks cycles but hand-built schemas may not.
+// - An element that is its own ancestor is rendered with a CyclicalReferenceErr and without children.
+func renderTypeTo(lw *lineWriter, t *types.TypeNode, r Renderer, path map[*types.TypeNode]bool) {
+	if path[t] {
+		cycle := *t
+		cycle.Error = types.CyclicalReferenceErr
+		cycle.Children = nil
+		t = &cycle
+	}
+	path[t] = true
+	defer delete(path, t)
+
 	// Capture initial indent and restore on exit.
 	originalIndent := r.Indent()
 
-	out := []string{}
-
 	// Process element with preFunc.
-	out = util.AppendStrings(out, r.Pre(t), "")
+	lw.write(r.Pre(t))
 
 	// Process children.
 	if !r.DeReference() && t.TypeRef != "" {
 		// Skip children.
 	} else {
-		// Always process children in alphabetical order.
-		typeRefMap := t.ChildMap()
-		typeRefKeys := t.ChildKeys(typeRefMap)
-
 		// Capture indent before children.
 		childIndent := r.Indent()
 
-		for _, childName := range typeRefKeys {
-			childNode := typeRefMap[childName]
+		for _, childNode := range Children(t, r) {
+			if lw.err != nil {
+				break
+			}
+
 			childNative := r.NativeType(childNode)
-			if childNative.Include == threeflag.False {
+			if childNative.Include == threeflag.False || IsErrorExcluded(childNode, r) {
 				continue
 			}
 
 			// Reset indent before each child.
 			r.SetIndent(childIndent)
-			out = util.AppendStrings(out, RenderType(childNode, r), "")
+			renderTypeTo(lw, childNode, r, path)
 		}
 	}
 
@@ -75,10 +123,157 @@ func RenderType(t *types.TypeNode, r Renderer) []string {
 	r.SetIndent(originalIndent)
 
 	// Process element with postFunc.
-	out = util.AppendStrings(out, r.Post(t), "")
+	lw.write(r.Post(t))
 
 	// Restore original indent.
 	r.SetIndent(originalIndent)
+}
+
+// lineWriter writes non-empty lines to a writer and keeps the first error.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+// write writes each non-empty line followed by a newline.
+// - Strings with newlines are split into lines, see util.AppendStrings.
+func (lw *lineWriter) write(in []string) {
+	for _, s := range in {
+		for _, line := range strings.Split(s, "\n") {
+			if lw.err != nil {
+				return
+			}
+			if line != "" {
+				_, lw.err = io.WriteString(lw.w, line+"\n")
+			}
+		}
+	}
+}
+
+// writeBlocks writes each non-empty string followed by a newline.
+// - Strings are not split so that blocks of text (e.g. a YAML header) keep their empty lines.
+func (lw *lineWriter) writeBlocks(in []string) {
+	for _, s := range in {
+		if lw.err != nil {
+			return
+		}
+		if s != "" {
+			_, lw.err = io.WriteString(lw.w, s+"\n")
+		}
+	}
+}
+
+// bufferLines returns the lines written to a buffer by a lineWriter.
+func bufferLines(buf *bytes.Buffer) []string {
+	if buf.Len() == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// Children returns the children of an element in render order.
+// - Children are sorted alphabetically unless the renderer preserves declaration order, see TypeNode.Order.
+func Children(t *types.TypeNode, r Renderer) []*types.TypeNode {
+	if r.PreserveOrder() {
+		// Sort by declaration order, children without an order keep their positions after ordered children.
+		out := append([]*types.TypeNode{}, t.Children...)
+		sort.SliceStable(out, func(i, j int) bool {
+			return orderOf(out[i]) < orderOf(out[j])
+		})
+		return out
+	}
 
+	childMap := t.ChildMap()
+	out := []*types.TypeNode{}
+	for _, key := range t.ChildKeys(childMap) {
+		out = append(out, childMap[key])
+	}
 	return out
 }
+
+// orderOf returns the declaration order of an element for sorting, elements without an order sort last.
+func orderOf(t *types.TypeNode) int {
+	if t.Order == 0 {
+		return math.MaxInt32
+	}
+	return t.Order
+}
+
+// IsErrorExcluded returns true if an element has an error and the renderer excludes elements with errors.
+// - Children of excluded elements are not rendered either.
+func IsErrorExcluded(t *types.TypeNode, r Renderer) bool {
+	return r.ExcludeErrored() && t.Error != ""
+}
+
+// TopLevelName returns the name of a top-level element, i.e. a child of the TypeRef or Root tree.
+// - Elements of the TypeRef tree are named by their type name.
+// - Root elements that are references take their name from TypeRef and are only named if the renderer de-references.
+// - Other root elements are named by their MetaKey as an identifier (e.g. "/users" --> "Users"), or ROOT_NAME if there is none.
+// - Returns empty string if the element should not be rendered at the top level.
+func TopLevelName(t *types.TypeNode, r Renderer) string {
+	if t.Parent.Name == types.TYPEREF_NAME {
+		return t.Name
+	}
+
+	if t.TypeRef != "" {
+		if !r.DeReference() {
+			return ""
+		}
+		return t.TypeRef
+	}
+
+	if name := util.ToIdentifier(t.MetaKey); name != "" {
+		return name
+	}
+	return types.ROOT_NAME
+}
+
+// DEPRECATED_OPTION is the b9schema tag option that marks an element as deprecated, e.g. `b9schema:"deprecated"`
+const DEPRECATED_OPTION = "deprecated"
+
+// IsDeprecated returns true if an element is marked deprecated, see ResolveOption for precedence.
+// - "deprecated=false" does not mark an element, e.g. a tag can override a global default.
+func (opt *Options) IsDeprecated(t *types.TypeNode) bool {
+	val, ok := opt.ResolveOption(t, DEPRECATED_OPTION)
+	return ok && val != "false"
+}
+
+// InlineField returns the included map child of a struct that is marked inline, nil if there is none.
+// - The map values describe extra keys of the struct.
+func InlineField(t *types.TypeNode) *types.TypeNode {
+	if t.Type != generictype.Struct.String() {
+		return nil
+	}
+
+	for _, child := range t.Children {
+		if child.Type != generictype.Map.String() || child.GetNativeType("json").Include == threeflag.False {
+			continue
+		}
+		if tagNative := child.Native[types.TAG_DIALECT]; tagNative != nil {
+			if _, ok := tagNative.Options.Lookup(types.INLINE_OPTION); ok {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// jsonPointerEscaper escapes reference tokens per RFC 6901.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// JSONPointer builds an RFC 6901 JSON Pointer for an element, e.g. "/MapOK/MapVal/Key1"
+// - Top-level elements in Root and TypeRef are the whole document and return "".
+// - Names come from the json dialect so that pointers match serialized documents.
+// - Unnamed elements (e.g. list items and map values) use "-".
+func JSONPointer(t *types.TypeNode) string {
+	if t.Parent == nil || t.Parent.Parent == nil {
+		return ""
+	}
+
+	name := t.GetNativeType("json").Name
+	if name == "" {
+		name = "-"
+	}
+
+	return JSONPointer(t.Parent) + "/" + jsonPointerEscaper.Replace(name)
+}
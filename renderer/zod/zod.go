@@ -0,0 +1,280 @@
+package zod
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gitmann/b9schema-golang/common/enum/generictype"
+	"github.com/gitmann/b9schema-golang/common/enum/threeflag"
+	"github.com/gitmann/b9schema-golang/common/types"
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+// SCHEMA_SUFFIX is appended to type names to build exported schema names.
+const SCHEMA_SUFFIX = "Schema"
+
+// identifierRegexp matches names that can be used as property names without quotes.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// ZodRenderer renders a schema as Zod validation schemas.
+// - Each TypeRef becomes an exported const, e.g. "export const NameSchema = z.object({...});"
+// - Root elements are exported only if they are not already exported as a TypeRef.
+// - References to schemas that are not declared yet (e.g. cycles) are wrapped in z.lazy().
+type ZodRenderer struct {
+	opt *renderer.Options
+
+	// defaultPrefix is used if Options.Prefix is empty.
+	defaultPrefix string
+
+	// declared stores names of schemas that have been rendered.
+	declared map[string]bool
+}
+
+func NewZodRenderer(opt *renderer.Options) *ZodRenderer {
+	if opt == nil {
+		opt = renderer.NewOptions()
+	}
+
+	return &ZodRenderer{opt: opt, defaultPrefix: "  "}
+}
+
+// ProcessSchema renders TypeRef elements before Root elements so that roots can use them directly.
+func (r *ZodRenderer) ProcessSchema(schema *types.Schema, settings ...string) ([]string, error) {
+	r.declared = map[string]bool{}
+
+	lines := []string{}
+	if !r.DeReference() {
+		lines = append(lines, renderer.RenderType(schema.TypeRef, r)...)
+	}
+	lines = append(lines, renderer.RenderType(schema.Root, r)...)
+
+	out := []string{}
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+
+	if len(out) > 0 {
+		out = append([]string{`import { z } from "zod";`}, out...)
+	}
+	return out, nil
+}
+
+func (r *ZodRenderer) DeReference() bool {
+	return r.opt.DeReference
+}
+
+func (r *ZodRenderer) PreserveOrder() bool {
+	return r.opt.PreserveOrder
+}
+
+func (r *ZodRenderer) ExcludeErrored() bool {
+	return r.opt.ExcludeErrored
+}
+
+func (r *ZodRenderer) Indent() int {
+	return r.opt.Indent
+}
+
+func (r *ZodRenderer) SetIndent(value int) {
+	r.opt.Indent = value
+}
+
+func (r *ZodRenderer) Prefix() string {
+	return strings.Repeat(r.opt.IndentPrefix(r.defaultPrefix), r.opt.Indent)
+}
+
+func (r *ZodRenderer) NativeType(t *types.TypeNode) *types.NativeType {
+	return r.opt.NativeType(t, "json")
+}
+
+// Pre renders a complete schema for each top-level element.
+// - Properties are rendered here so that commas can be placed after them.
+func (r *ZodRenderer) Pre(t *types.TypeNode) []string {
+	if t.Type == generictype.Root.String() || t.Parent.Type != generictype.Root.String() {
+		return []string{}
+	}
+
+	name := renderer.TopLevelName(t, r)
+	if name == "" {
+		return []string{}
+	}
+
+	out := r.errorComments(t)
+
+	expr := r.schemaExpr(t)
+	expr[0] = fmt.Sprintf("%sexport const %s%s = %s", r.Prefix(), name, SCHEMA_SUFFIX, expr[0])
+	expr[len(expr)-1] += ";"
+	out = append(out, expr...)
+
+	// Declare after rendering so that self-references are lazy.
+	r.declared[name] = true
+
+	return out
+}
+
+func (r *ZodRenderer) Header(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *ZodRenderer) Footer(schema *types.Schema) []string {
+	return []string{}
+}
+
+func (r *ZodRenderer) Post(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// Path is a function that builds a path string from a TypeNode.
+func (r *ZodRenderer) Path(t *types.TypeNode) []string {
+	return []string{}
+}
+
+// errorComments returns comment lines for errors on an element and its unnamed descendants.
+func (r *ZodRenderer) errorComments(t *types.TypeNode) []string {
+	out := []string{}
+
+	for n := t; n != nil; {
+		if n.Error != "" {
+			out = append(out, fmt.Sprintf("%s// ERROR: %s", r.Prefix(), n.Error))
+		}
+
+		// Continue with unnamed list items and map values.
+		if len(n.Children) == 1 && n.Children[0].Name == "" && (n.TypeRef == "" || r.DeReference()) {
+			n = n.Children[0]
+		} else {
+			n = nil
+		}
+	}
+
+	return out
+}
+
+// fieldExpr returns the Zod expression for an element with nullable and optional modifiers.
+func (r *ZodRenderer) fieldExpr(t *types.TypeNode) []string {
+	expr := r.schemaExpr(t)
+
+	if t.Nullable {
+		expr[len(expr)-1] += ".nullable()"
+	}
+	if jsonNative := t.Native["json"]; jsonNative != nil {
+		if _, ok := jsonNative.Options.Lookup("omitempty"); ok {
+			expr[len(expr)-1] += ".optional()"
+		}
+	}
+
+	return expr
+}
+
+// schemaExpr returns the lines of the Zod expression for an element.
+// - The first line has no prefix so that it can follow a name.
+func (r *ZodRenderer) schemaExpr(t *types.TypeNode) []string {
+	// References are rendered by name unless de-referencing.
+	// - Cyclical references are always kept as references.
+	if t.TypeRef != "" {
+		if !r.DeReference() || t.Error == types.CyclicalReferenceErr {
+			name := t.TypeRef + SCHEMA_SUFFIX
+			if t.Error == types.CyclicalReferenceErr || !r.declared[t.TypeRef] {
+				return []string{fmt.Sprintf("z.lazy(() => %s)", name)}
+			}
+			return []string{name}
+		}
+	}
+
+	switch t.Type {
+	case generictype.Boolean.String():
+		return []string{"z.boolean()"}
+	case generictype.Integer.String(), generictype.Float.String():
+		return []string{"z.number()"}
+	case generictype.String.String():
+		return []string{"z.string()"}
+	case generictype.DateTime.String():
+		return []string{"z.string().datetime()"}
+	case generictype.Duration.String():
+		return []string{util.ValueIfTrue(r.opt.DurationAsString, "z.string()", "z.number()")}
+	case generictype.Struct.String():
+		return r.objectExpr(t)
+	case generictype.List.String():
+		if len(t.Children) == 0 {
+			return []string{"z.array(z.unknown())"}
+		}
+		return wrap("z.array(", r.fieldExpr(t.Children[0]), ")")
+	case generictype.Map.String():
+		if len(t.Children) == 0 {
+			return []string{"z.record(z.string(), z.unknown())"}
+		}
+		return wrap("z.record(z.string(), ", r.fieldExpr(t.Children[0]), ")")
+	case generictype.Union.String():
+		return r.unionExpr(t)
+	}
+
+	// Invalid and unknown types.
+	return []string{"z.unknown()"}
+}
+
+// objectExpr returns the lines of a z.object() expression with one line per property.
+func (r *ZodRenderer) objectExpr(t *types.TypeNode) []string {
+	out := []string{"z.object({"}
+
+	r.SetIndent(r.Indent() + 1)
+	for _, child := range renderer.Children(t, r) {
+		native := r.NativeType(child)
+		if native.Include == threeflag.False || renderer.IsErrorExcluded(child, r) {
+			continue
+		}
+
+		name := native.Name
+		if !identifierRegexp.MatchString(name) {
+			name = fmt.Sprintf("%q", name)
+		}
+
+		out = append(out, r.errorComments(child)...)
+
+		expr := r.fieldExpr(child)
+		expr[0] = fmt.Sprintf("%s%s: %s", r.Prefix(), name, expr[0])
+		expr[len(expr)-1] += ","
+		out = append(out, expr...)
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	if len(out) == 1 {
+		return []string{"z.object({})"}
+	}
+
+	return append(out, r.Prefix()+"})")
+}
+
+// unionExpr returns the lines of a z.union() expression with one line per alternative.
+// - z.union needs at least two alternatives so a single alternative is rendered alone.
+func (r *ZodRenderer) unionExpr(t *types.TypeNode) []string {
+	switch len(t.Children) {
+	case 0:
+		return []string{"z.unknown()"}
+	case 1:
+		return r.fieldExpr(t.Children[0])
+	}
+
+	out := []string{"z.union(["}
+
+	r.SetIndent(r.Indent() + 1)
+	for _, child := range renderer.Children(t, r) {
+		expr := r.fieldExpr(child)
+		expr[0] = r.Prefix() + expr[0]
+		expr[len(expr)-1] += ","
+		out = append(out, expr...)
+	}
+	r.SetIndent(r.Indent() - 1)
+
+	return append(out, r.Prefix()+"])")
+}
+
+// wrap adds open and close text around the lines of an expression.
+func wrap(open string, expr []string, close string) []string {
+	out := append([]string{}, expr...)
+	out[0] = open + out[0]
+	out[len(out)-1] += close
+	return out
+}
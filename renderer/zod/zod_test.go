@@ -0,0 +1,164 @@
+package zod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitmann/b9schema-golang/common/util"
+	"github.com/gitmann/b9schema-golang/reflector"
+	"github.com/gitmann/b9schema-golang/renderer"
+)
+
+type BasicStruct struct {
+	BoolVal     bool
+	IntVal      int
+	Float64Val  float64
+	StringVal   string    `json:"stringVal"`
+	TimeVal     time.Time `json:"time-val"`
+	OptionalVal *string   `json:"optionalVal,omitempty"`
+	Ignored     string    `json:"-"`
+	ListVal     []*int
+	MapVal      map[string][]string
+	Anonymous   struct {
+		Key string
+	}
+}
+
+type AStruct struct {
+	AName  string   `json:"aName,omitempty"`
+	AChild *BStruct `json:"aChild,omitempty"`
+}
+
+type BStruct struct {
+	BName  string   `json:"bName"`
+	BChild *CStruct `json:"bChild,omitempty"`
+}
+
+type CStruct struct {
+	CName  string   `json:"cName"`
+	CChild *AStruct `json:"cChild,omitempty"`
+}
+
+type CycleTest struct {
+	Level  int      `json:"-"`
+	CycleA AStruct  `json:"cycleA"`
+	CycleB *BStruct `json:"cycleB"`
+	CycleC struct {
+		C CStruct `json:"c"`
+	}
+}
+
+func TestZodRenderer_ProcessSchema(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+		deref bool
+		want  []string
+	}{
+		{
+			name:  "basic",
+			value: BasicStruct{},
+			want: []string{
+				`import { z } from "zod";`,
+				`export const BasicStructSchema = z.object({`,
+				`  Anonymous: z.object({`,
+				`    Key: z.string(),`,
+				`  }),`,
+				`  BoolVal: z.boolean(),`,
+				`  Float64Val: z.number(),`,
+				`  IntVal: z.number(),`,
+				`  ListVal: z.array(z.number().nullable()),`,
+				`  MapVal: z.record(z.string(), z.array(z.string())),`,
+				`  optionalVal: z.string().nullable().optional(),`,
+				`  stringVal: z.string(),`,
+				`  "time-val": z.string().datetime(),`,
+				`});`,
+			},
+		},
+		{
+			name:  "cycle",
+			value: &CycleTest{},
+			want: []string{
+				`import { z } from "zod";`,
+				`export const AStructSchema = z.object({`,
+				`  aChild: z.lazy(() => BStructSchema).nullable().optional(),`,
+				`  aName: z.string().optional(),`,
+				`});`,
+				`export const BStructSchema = z.object({`,
+				`  bChild: z.lazy(() => CStructSchema).nullable().optional(),`,
+				`  bName: z.string(),`,
+				`});`,
+				`export const CStructSchema = z.object({`,
+				`  cChild: AStructSchema.nullable().optional(),`,
+				`  cName: z.string(),`,
+				`});`,
+				`export const CycleTestSchema = z.object({`,
+				`  cycleA: AStructSchema,`,
+				`  cycleB: BStructSchema.nullable(),`,
+				`  CycleC: z.object({`,
+				`    c: CStructSchema,`,
+				`  }),`,
+				`});`,
+			},
+		},
+		{
+			name:  "cycle-deref",
+			value: &CycleTest{},
+			deref: true,
+			want: []string{
+				`import { z } from "zod";`,
+				`export const CycleTestSchema = z.object({`,
+				`  cycleA: z.object({`,
+				`    aChild: z.object({`,
+				`      bChild: z.object({`,
+				`        // ERROR: cyclical reference`,
+				`        cChild: z.lazy(() => AStructSchema).nullable().optional(),`,
+				`        cName: z.string(),`,
+				`      }).nullable().optional(),`,
+				`      bName: z.string(),`,
+				`    }).nullable().optional(),`,
+				`    aName: z.string().optional(),`,
+				`  }),`,
+				`  cycleB: z.object({`,
+				`    bChild: z.object({`,
+				`      cChild: z.object({`,
+				`        // ERROR: cyclical reference`,
+				`        aChild: z.lazy(() => BStructSchema).nullable().optional(),`,
+				`        aName: z.string().optional(),`,
+				`      }).nullable().optional(),`,
+				`      cName: z.string(),`,
+				`    }).nullable().optional(),`,
+				`    bName: z.string(),`,
+				`  }).nullable(),`,
+				`  CycleC: z.object({`,
+				`    c: z.object({`,
+				`      cChild: z.object({`,
+				`        aChild: z.object({`,
+				`          // ERROR: cyclical reference`,
+				`          bChild: z.lazy(() => CStructSchema).nullable().optional(),`,
+				`          bName: z.string(),`,
+				`        }).nullable().optional(),`,
+				`        aName: z.string().optional(),`,
+				`      }).nullable().optional(),`,
+				`      cName: z.string(),`,
+				`    }),`,
+				`  }),`,
+				`});`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		schema := reflector.NewReflector().DeriveSchema(test.value, test.name)
+
+		opt := renderer.NewOptions()
+		opt.DeReference = test.deref
+
+		gotStrings, err := NewZodRenderer(opt).ProcessSchema(schema)
+		if err != nil {
+			t.Errorf("TEST_FAIL %s: err=%s", test.name, err)
+			continue
+		}
+		util.CompareStrings(t, test.name, gotStrings, test.want)
+	}
+}